@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+
+	"github.com/fanmania/backend/internal/ai"
+	"github.com/fanmania/backend/internal/config"
+)
+
+// newAIProvider builds the providers listed in cfg.Providers, chained for
+// failover when there is more than one. Providers missing an API key are
+// skipped; a nil provider means AI generation is disabled.
+func newAIProvider(cfg config.AIConfig) (ai.Provider, error) {
+	providers := []ai.Provider{}
+	for _, name := range cfg.Providers {
+		switch name {
+		case "anthropic":
+			if cfg.AnthropicAPIKey == "" {
+				log.Println("⚠ Skipping anthropic AI provider (no ANTHROPIC_API_KEY)")
+				continue
+			}
+			providers = append(providers, ai.NewAnthropicClient(cfg.AnthropicAPIKey, cfg.AnthropicModel))
+		case "openai":
+			if cfg.OpenAIAPIKey == "" {
+				log.Println("⚠ Skipping openai AI provider (no OPENAI_API_KEY)")
+				continue
+			}
+			providers = append(providers, ai.NewOpenAIClient(cfg.OpenAIAPIKey, cfg.OpenAIModel, cfg.OpenAIBaseURL))
+		case "fake":
+			fake, err := ai.LoadFakeProvider(cfg.FakeFixtures)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, fake)
+		}
+	}
+
+	switch len(providers) {
+	case 0:
+		return nil, nil
+	case 1:
+		return providers[0], nil
+	default:
+		return ai.NewFailoverProvider(cfg.FailoverCooldown, providers...), nil
+	}
+}
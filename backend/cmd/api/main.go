@@ -9,12 +9,17 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/fanmania/backend/internal/ai"
 	"github.com/fanmania/backend/internal/config"
+	"github.com/fanmania/backend/internal/domain/models"
 	"github.com/fanmania/backend/internal/handler"
 	"github.com/fanmania/backend/internal/middleware"
 	"github.com/fanmania/backend/internal/repository/postgres"
 	"github.com/fanmania/backend/internal/service"
 	"github.com/fanmania/backend/pkg/jwt"
+	"github.com/fanmania/backend/pkg/mailer"
+	"github.com/fanmania/backend/pkg/ratelimit"
+	"github.com/fanmania/backend/pkg/redis"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
@@ -22,6 +27,11 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -38,15 +48,27 @@ func main() {
 	log.Println("✓ Connected to PostgreSQL")
 
 	// Run database migrations
-	if err := db.RunMigrations(context.Background()); err != nil {
-		log.Printf("⚠ Migration warning: %v", err)
+	migrator, err := postgres.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
 	}
+	log.Printf("✓ Database migrations up to date (%d applied)", applied)
 
 	// Initialize repositories
 	userRepo := postgres.NewUserRepository(db)
 	categoryRepo := postgres.NewCategoryRepository(db)
 	challengeRepo := postgres.NewChallengeRepository(db)
 	notificationRepo := postgres.NewNotificationRepository(db)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+	userTokenRepo := postgres.NewUserTokenRepository(db)
+	generationJobRepo := postgres.NewGenerationJobRepository(db)
+	reportRepo := postgres.NewReportRepository(db)
+	generationLogRepo := postgres.NewGenerationLogRepository(db)
+	skillRepo := postgres.NewSkillRepository(db)
 
 	// Initialize JWT token generator
 	jwtGen := jwt.NewTokenGenerator(
@@ -55,26 +77,98 @@ func main() {
 		cfg.JWT.RefreshTokenExpiry,
 	)
 
+	// Initialize outbound mail
+	var mail mailer.Mailer = mailer.NewLogMailer(cfg.Mail.From, cfg.Mail.OutputDir)
+	if cfg.Mail.Driver == "smtp" {
+		mail = mailer.NewSMTPMailer(
+			cfg.Mail.SMTPHost,
+			cfg.Mail.SMTPPort,
+			cfg.Mail.SMTPUsername,
+			cfg.Mail.SMTPPassword,
+			cfg.Mail.From,
+		)
+	}
+
 	// Initialize services
-	authService := service.NewAuthService(userRepo, jwtGen)
-	challengeService := service.NewChallengeService(challengeRepo, userRepo, categoryRepo)
+	authService := service.NewAuthService(
+		db, userRepo, refreshTokenRepo, userTokenRepo, jwtGen, mail, cfg.App.PublicURL,
+	)
+	streakService := service.NewStreakService(db)
 	rankingService := service.NewRankingService(db, userRepo, categoryRepo)
-	_ = service.NewStreakService(db) // TODO: Use streakService when implementing streak features
 	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	skillService := service.NewSkillService(db, skillRepo, challengeRepo, notificationService)
+	// Legal rules are shared by AI generation and hand authoring
+	legalValidator, err := ai.LoadLegalValidator(cfg.AI.LegalRules)
+	if err != nil {
+		log.Fatalf("Failed to load legal rules: %v", err)
+	}
+	log.Printf("✓ Legal rules version %s loaded", legalValidator.RulesVersion())
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go legalValidator.Watch(watchCtx, cfg.AI.LegalRulesReload)
+
+	challengeService := service.NewChallengeService(
+		db, challengeRepo, userRepo, categoryRepo, rankingService, streakService, skillService,
+		notificationService, legalValidator, jwtGen, service.SessionOptions{
+			GracePeriod:       cfg.Challenge.SessionGracePeriod,
+			TokenTTL:          cfg.Challenge.SessionTokenTTL,
+			AllowMissingToken: cfg.Challenge.AllowMissingSessionToken,
+		},
+	)
+	reviewService := service.NewReviewService(db, challengeRepo, challengeService)
+	reportService := service.NewReportService(
+		db, reportRepo, challengeRepo, challengeService, cfg.Challenge.ReportThreshold,
+	)
+	difficultyService := service.NewDifficultyService(db, challengeRepo, service.DifficultyOptions{
+		MinAttempts: cfg.Difficulty.MinAttempts,
+		Tolerance:   cfg.Difficulty.Tolerance,
+		AutoRetier:  cfg.Difficulty.AutoRetier,
+	})
+	go difficultyService.Run(watchCtx, cfg.Difficulty.Interval)
+
+	// Promote the bootstrap admin (only while no admin exists)
+	if promoted, err := authService.BootstrapAdmin(context.Background(), cfg.App.AdminBootstrapEmail); err != nil {
+		log.Printf("⚠ Admin bootstrap failed: %v", err)
+	} else if promoted {
+		log.Printf("✓ Promoted %s to admin", cfg.App.AdminBootstrapEmail)
+	}
 	
-	// Initialize AI service (only if API key is provided)
+	// Initialize AI service (only if a provider is configured)
+	aiProvider, err := newAIProvider(cfg.AI)
+	if err != nil {
+		log.Fatalf("Failed to initialize AI provider: %v", err)
+	}
+
 	var aiChallengeService *service.AIChallengeService
-	if cfg.AI.AnthropicAPIKey != "" {
+	var generationQueue *service.GenerationQueue
+	if aiProvider != nil {
+		prices, err := ai.ParsePricing(cfg.AI.Pricing)
+		if err != nil {
+			log.Fatalf("Failed to parse AI pricing: %v", err)
+		}
 		aiChallengeService = service.NewAIChallengeService(
-			cfg.AI.AnthropicAPIKey,
+			aiProvider,
+			legalValidator,
+			ai.NewPricing(prices),
 			challengeRepo,
 			categoryRepo,
+			generationLogRepo,
 		)
-		// Wire AI service to challenge service for on-demand generation
-		challengeService.SetAIChallengeService(aiChallengeService)
-		log.Println("✓ AI Challenge Service initialized and connected")
+		log.Printf("✓ AI Challenge Service initialized (%s)", aiProvider.Name())
+
+		// Start the generation worker pool
+		generationQueue = service.NewGenerationQueue(generationJobRepo, aiChallengeService, service.GenerationQueueOptions{
+			Workers:      cfg.Generation.Workers,
+			PollInterval: cfg.Generation.PollInterval,
+			MaxAttempts:  cfg.Generation.MaxAttempts,
+			RetryBackoff: cfg.Generation.RetryBackoff,
+			StaleAfter:   cfg.Generation.StaleAfter,
+		})
+		challengeService.SetGenerationQueue(generationQueue)
+		generationQueue.Start()
+		log.Printf("✓ Generation queue started (%d workers)", cfg.Generation.Workers)
 	} else {
-		log.Println("⚠ AI Challenge Service disabled (no ANTHROPIC_API_KEY)")
+		log.Println("⚠ AI Challenge Service disabled (no AI provider configured)")
 	}
 
 	// Initialize handlers
@@ -84,17 +178,57 @@ func main() {
 	challengeHandler := handler.NewChallengeHandler(challengeService)
 	leaderboardHandler := handler.NewLeaderboardHandler(rankingService)
 	notificationHandler := handler.NewNotificationHandler(notificationService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	reportHandler := handler.NewReportHandler(reportService)
+	difficultyHandler := handler.NewDifficultyHandler(difficultyService)
+	ratingHandler := handler.NewRatingHandler(skillService)
 	
 	// Initialize admin handler (only if AI service is available)
 	var adminHandler *handler.AdminHandler
 	if aiChallengeService != nil {
-		adminHandler = handler.NewAdminHandler(aiChallengeService)
+		adminHandler = handler.NewAdminHandler(aiChallengeService, generationQueue)
 	}
 
+	// Initialize rate limiting
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "redis" {
+		redisClient, err := redis.NewClient(cfg.GetRedisURL())
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		defer redisClient.Close()
+		rateLimitStore = ratelimit.NewRedisStore(redisClient, "ratelimit:")
+		log.Println("✓ Connected to Redis (rate limiting)")
+	}
+	limit := func(name string, rule config.RateLimitRule, key func(c *fiber.Ctx) string) fiber.Handler {
+		if !cfg.RateLimit.Enabled {
+			return func(c *fiber.Ctx) error { return c.Next() }
+		}
+		return middleware.RateLimit(rateLimitStore, middleware.RateLimitPolicy{
+			Name:  name,
+			Limit: ratelimit.Limit{Burst: rule.Requests, Period: rule.Window},
+			Key:   key,
+		})
+	}
+	authLimit := limit("auth", cfg.RateLimit.Auth, middleware.KeyByIP)
+	loginLimit := limit("login", cfg.RateLimit.Login, middleware.KeyByIP)
+	forgotPasswordLimit := limit("forgot_password", cfg.RateLimit.Login, middleware.KeyByIP)
+	challengeFetchLimit := limit("challenge_fetch", cfg.RateLimit.ChallengeFetch, middleware.KeyByUser)
+	challengeSubmitLimit := limit("challenge_submit", cfg.RateLimit.ChallengeSubmit, middleware.KeyByUser)
+	// Every attempt starts its challenge first, so starts get the same allowance in a bucket of their own
+	challengeStartLimit := limit("challenge_start", cfg.RateLimit.ChallengeSubmit, middleware.KeyByUser)
+	adminGenerateLimit := limit("admin_generate", cfg.RateLimit.AdminGenerate, middleware.KeyByUser)
+
 	// Initialize Fiber app
+	// Behind a proxy, c.IP() must come from the forwarded header, or every
+	// client shares the proxy's address and its per-IP rate limits
 	app := fiber.New(fiber.Config{
-		AppName:      cfg.App.Name,
-		ErrorHandler: customErrorHandler,
+		AppName:                 cfg.App.Name,
+		ErrorHandler:            customErrorHandler,
+		ProxyHeader:             cfg.App.ProxyHeader,
+		EnableIPValidation:      cfg.App.ProxyHeader != "",
+		EnableTrustedProxyCheck: len(cfg.App.TrustedProxies) > 0,
+		TrustedProxies:          cfg.App.TrustedProxies,
 	})
 
 	// Global middleware
@@ -106,6 +240,7 @@ func main() {
 		AllowOrigins: "*",
 		AllowMethods: "GET,POST,PUT,PATCH,DELETE,OPTIONS",
 		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+		ExposeHeaders: "RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After",
 	}))
 
 	// Health check endpoint
@@ -131,9 +266,16 @@ func main() {
 
 	// Public authentication routes
 	auth := v1.Group("/auth")
+	auth.Use(authLimit)
 	auth.Post("/register", authHandler.Register)
-	auth.Post("/login", authHandler.Login)
+	auth.Post("/login", loginLimit, authHandler.Login)
 	auth.Post("/refresh", authHandler.RefreshToken)
+	auth.Post("/logout", middleware.AuthMiddleware(authService), authHandler.Logout)
+	auth.Post("/logout-all", middleware.AuthMiddleware(authService), authHandler.LogoutAll)
+	auth.Post("/verify-email", authHandler.VerifyEmail)
+	auth.Post("/verify-email/resend", middleware.AuthMiddleware(authService), authHandler.ResendVerification)
+	auth.Post("/forgot-password", forgotPasswordLimit, authHandler.ForgotPassword)
+	auth.Post("/reset-password", authHandler.ResetPassword)
 
 	// Protected user routes
 	users := v1.Group("/users")
@@ -147,15 +289,24 @@ func main() {
 	categories.Get("/", categoryHandler.GetAll)           // Public
 	categories.Get("/:id", categoryHandler.GetByID)       // Public
 
+	// With REQUIRE_VERIFIED_EMAIL, unverified accounts cannot submit attempts and so never reach the leaderboards
+	requireVerified := func(c *fiber.Ctx) error { return c.Next() }
+	if cfg.App.RequireVerifiedEmail {
+		requireVerified = middleware.RequireVerified(authService)
+	}
+
 	// Protected challenge routes
 	challenges := v1.Group("/challenges")
 	challenges.Use(middleware.AuthMiddleware(authService))
-	challenges.Get("/", challengeHandler.GetChallenges)           // GET /challenges?category_id=xxx&difficulty_tier=1
-	challenges.Post("/:id/attempt", challengeHandler.SubmitChallenge) // POST /challenges/:id/attempt
-	challenges.Get("/stats", challengeHandler.GetUserAttemptStats)    // GET /challenges/stats
+	challenges.Get("/", challengeFetchLimit, challengeHandler.GetChallenges)                                 // GET /challenges?category_id=xxx&difficulty_tier=1
+	challenges.Post("/:id/start", challengeStartLimit, challengeHandler.StartChallenge)                     // POST /challenges/:id/start
+	challenges.Post("/:id/attempt", challengeSubmitLimit, requireVerified, challengeHandler.SubmitChallenge) // POST /challenges/:id/attempt
+	challenges.Get("/stats", challengeHandler.GetUserAttemptStats)                                           // GET /challenges/stats
+	challenges.Post("/:id/report", requireVerified, reportHandler.ReportChallenge)                           // POST /challenges/:id/report
 
-	// Protected leaderboard routes
+	// Public leaderboard routes; a signed-in caller also gets their own rank
 	leaderboards := v1.Group("/leaderboards")
+	leaderboards.Use(middleware.OptionalAuthMiddleware(authService))
 	leaderboards.Get("/global", leaderboardHandler.GetGlobalLeaderboard)        // GET /leaderboards/global?scope=weekly
 	leaderboards.Get("/category/:id", leaderboardHandler.GetCategoryLeaderboard) // GET /leaderboards/category/:id?scope=weekly
 
@@ -167,20 +318,55 @@ func main() {
 	notifications.Post("/read-all", notificationHandler.MarkAllAsRead)        // POST /notifications/read-all
 	notifications.Post("/register-device", notificationHandler.RegisterDevice) // POST /notifications/register-device
 
-	// Admin routes (protected, for AI challenge generation)
+	// Review queue routes (moderator role required)
+	moderation := v1.Group("/moderation")
+	moderation.Use(middleware.AuthMiddleware(authService))
+	moderation.Use(middleware.RequireRole(models.RoleModerator))
+	moderation.Get("/challenges", reviewHandler.ListPending)          // GET /moderation/challenges?limit=50
+	moderation.Get("/backlog", reviewHandler.GetBacklog)              // GET /moderation/backlog
+	moderation.Put("/challenges/:id", reviewHandler.Edit)             // PUT /moderation/challenges/:id
+	moderation.Post("/challenges/:id/approve", reviewHandler.Approve) // POST /moderation/challenges/:id/approve
+	moderation.Post("/challenges/:id/reject", reviewHandler.Reject)   // POST /moderation/challenges/:id/reject
+
+	// Admin routes (admin role required)
+	admin := v1.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(authService))
+	admin.Use(middleware.RequireRole(models.RoleAdmin))
+	admin.Put("/users/:id/role", authHandler.UpdateUserRole)                 // PUT /admin/users/:id/role
+	admin.Post("/predictions", challengeHandler.CreatePrediction)             // POST /admin/predictions
+	admin.Post("/predictions/:id/resolve", challengeHandler.ResolvePrediction) // POST /admin/predictions/:id/resolve
+
+	// AI challenge generation routes
 	if adminHandler != nil {
-		admin := v1.Group("/admin")
-		admin.Use(middleware.AuthMiddleware(authService)) // TODO: Add admin check
-		
-		admin.Post("/challenges/generate", adminHandler.GenerateChallenge)           // POST /admin/challenges/generate
-		admin.Post("/challenges/generate-batch", adminHandler.GenerateBatch)         // POST /admin/challenges/generate-batch
-		admin.Get("/challenges/stats", adminHandler.GetGenerationStats)              // GET /admin/challenges/stats
-		admin.Get("/ai/validate-key", adminHandler.ValidateAPIKey)                   // GET /admin/ai/validate-key
-		admin.Post("/categories/generate", adminHandler.GenerateCategories)          // POST /admin/categories/generate
+		admin.Post("/challenges/generate", adminGenerateLimit, adminHandler.GenerateChallenge)   // POST /admin/challenges/generate
+		admin.Post("/challenges/generate-batch", adminGenerateLimit, adminHandler.GenerateBatch) // POST /admin/challenges/generate-batch
+		admin.Get("/challenges/stats", adminHandler.GetGenerationStats)                           // GET /admin/challenges/stats
+		admin.Get("/ai/validate-key", adminHandler.ValidateAPIKey)                                // GET /admin/ai/validate-key
+		admin.Post("/categories/generate", adminGenerateLimit, adminHandler.GenerateCategories)   // POST /admin/categories/generate
+		admin.Get("/jobs", adminHandler.ListJobs)                                                 // GET /admin/jobs?status=pending
+		admin.Get("/jobs/:id", adminHandler.GetJob)                                               // GET /admin/jobs/:id
 		
 		log.Println("✓ Admin routes registered")
 	}
 
+	// Challenge authoring routes; registered after the static AI routes
+	// above so /challenges/stats is not taken as a challenge ID
+	admin.Get("/challenges", challengeHandler.ListAdminChallenges)                          // GET /admin/challenges?is_active=false
+	admin.Post("/challenges", challengeHandler.AuthorChallenge)                             // POST /admin/challenges
+	admin.Get("/challenges/:id", challengeHandler.GetAdminChallenge)                        // GET /admin/challenges/:id
+	admin.Put("/challenges/:id", challengeHandler.EditChallenge)                            // PUT /admin/challenges/:id
+	admin.Put("/challenges/:id/schedule", challengeHandler.ScheduleChallenge)               // PUT /admin/challenges/:id/schedule
+	admin.Post("/challenges/:id/activate", challengeHandler.ActivateChallenge)              // POST /admin/challenges/:id/activate
+	admin.Post("/challenges/:id/deactivate", challengeHandler.DeactivateChallenge)          // POST /admin/challenges/:id/deactivate
+	admin.Post("/challenges/:id/clone", challengeHandler.CloneChallenge)                    // POST /admin/challenges/:id/clone
+	admin.Get("/challenges/:id/revisions", challengeHandler.GetChallengeRevisions)          // GET /admin/challenges/:id/revisions
+	admin.Get("/challenges/:id/revisions/:revision", challengeHandler.GetChallengeRevision) // GET /admin/challenges/:id/revisions/:revision
+	admin.Get("/challenges/:id/reports", reportHandler.GetChallengeReports)                 // GET /admin/challenges/:id/reports?status=open
+	admin.Post("/challenges/:id/reports/resolve", reportHandler.ResolveReports)             // POST /admin/challenges/:id/reports/resolve
+	admin.Get("/reports", reportHandler.ListReportedChallenges)                             // GET /admin/reports?status=open
+	admin.Post("/challenges/calibrate-difficulty", difficultyHandler.CalibrateDifficulty)   // POST /admin/challenges/calibrate-difficulty
+	admin.Post("/ratings/recalculate", ratingHandler.RecalculateRatings)                    // POST /admin/ratings/recalculate
+
 	// Start server
 	address := fmt.Sprintf("%s:%s", cfg.App.Host, cfg.App.Port)
 	log.Printf("🚀 Starting %s on %s", cfg.App.Name, address)
@@ -209,6 +395,13 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Let generation workers finish what they are making and hand back their jobs
+	if generationQueue != nil {
+		if err := generationQueue.Shutdown(ctx); err != nil {
+			log.Printf("⚠ Generation jobs interrupted: %v", err)
+		}
+	}
+
 	log.Println("✓ Server stopped gracefully")
 }
 
@@ -18,18 +18,29 @@ type AnthropicClient struct {
 	model      string
 }
 
-// NewAnthropicClient creates a new Anthropic API client
-func NewAnthropicClient(apiKey string) *AnthropicClient {
+// DefaultAnthropicModel is used when no model is configured
+const DefaultAnthropicModel = "claude-sonnet-4-20250514"
+
+// NewAnthropicClient creates a new Anthropic API client for model
+func NewAnthropicClient(apiKey, model string) *AnthropicClient {
+	if model == "" {
+		model = DefaultAnthropicModel
+	}
 	return &AnthropicClient{
 		apiKey:  apiKey,
 		baseURL: "https://api.anthropic.com/v1",
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
-		model: "claude-sonnet-4-20250514", // Latest Claude Sonnet
+		model: model,
 	}
 }
 
+// Name identifies the provider
+func (c *AnthropicClient) Name() string {
+	return "anthropic"
+}
+
 // Message represents a message in the conversation
 type Message struct {
 	Role    string `json:"role"`
@@ -64,12 +75,12 @@ type CreateMessageResponse struct {
 	} `json:"usage"`
 }
 
-// GenerateChallenge generates a challenge using Claude
-func (c *AnthropicClient) GenerateChallenge(
+// Complete sends prompt to Claude and returns its reply
+func (c *AnthropicClient) Complete(
 	ctx context.Context,
 	prompt string,
 	systemPrompt string,
-) (string, error) {
+) (*Completion, error) {
 	reqBody := CreateMessageRequest{
 		Model:       c.model,
 		MaxTokens:   2000,
@@ -85,7 +96,7 @@ func (c *AnthropicClient) GenerateChallenge(
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(
@@ -95,7 +106,7 @@ func (c *AnthropicClient) GenerateChallenge(
 		bytes.NewBuffer(jsonData),
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -104,37 +115,40 @@ func (c *AnthropicClient) GenerateChallenge(
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
+		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	var response CreateMessageResponse
 	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	if len(response.Content) == 0 {
-		return "", fmt.Errorf("no content in response")
+		return nil, fmt.Errorf("no content in response")
 	}
 
-	return response.Content[0].Text, nil
+	return &Completion{
+		Text:     response.Content[0].Text,
+		Provider: c.Name(),
+		Model:    response.Model,
+		Usage: Usage{
+			InputTokens:  response.Usage.InputTokens,
+			OutputTokens: response.Usage.OutputTokens,
+		},
+	}, nil
 }
 
 // ValidateAPIKey checks if the API key is valid
 func (c *AnthropicClient) ValidateAPIKey(ctx context.Context) error {
-	_, err := c.GenerateChallenge(
-		ctx,
-		"Say 'API key is valid' if you can read this.",
-		"You are a test assistant.",
-	)
-	return err
+	return ping(ctx, c)
 }
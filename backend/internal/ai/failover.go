@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FailoverProvider sends each request to the first provider that answers.
+// A provider that fails is skipped for a cooldown period so requests go
+// straight to the next one, unless every provider is cooling down.
+type FailoverProvider struct {
+	providers []Provider
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	downUntil map[string]time.Time
+}
+
+// NewFailoverProvider creates a FailoverProvider trying providers in order
+func NewFailoverProvider(cooldown time.Duration, providers ...Provider) *FailoverProvider {
+	return &FailoverProvider{
+		providers: providers,
+		cooldown:  cooldown,
+		downUntil: make(map[string]time.Time),
+	}
+}
+
+// Name lists the wrapped providers in failover order
+func (f *FailoverProvider) Name() string {
+	names := make([]string, len(f.providers))
+	for i, p := range f.providers {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Complete tries each provider in turn until one succeeds. The returned
+// Completion names the provider that served it.
+func (f *FailoverProvider) Complete(ctx context.Context, prompt string, systemPrompt string) (*Completion, error) {
+	var errs []error
+	for _, p := range f.order() {
+		completion, err := p.Complete(ctx, prompt, systemPrompt)
+		if err == nil {
+			f.markUp(p)
+			return completion, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		f.markDown(p)
+		log.Printf("⚠ AI provider %s failed, failing over: %v", p.Name(), err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+
+	return nil, fmt.Errorf("all AI providers failed: %w", errors.Join(errs...))
+}
+
+// ValidateAPIKey checks every provider and reports each one that fails
+func (f *FailoverProvider) ValidateAPIKey(ctx context.Context) error {
+	var errs []error
+	for _, p := range f.providers {
+		if err := p.ValidateAPIKey(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// order returns healthy providers first, then those cooling down
+func (f *FailoverProvider) order() []Provider {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	healthy := make([]Provider, 0, len(f.providers))
+	cooling := []Provider{}
+	for _, p := range f.providers {
+		if now.Before(f.downUntil[p.Name()]) {
+			cooling = append(cooling, p)
+		} else {
+			healthy = append(healthy, p)
+		}
+	}
+	return append(healthy, cooling...)
+}
+
+func (f *FailoverProvider) markDown(p Provider) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.downUntil[p.Name()] = time.Now().Add(f.cooldown)
+}
+
+func (f *FailoverProvider) markUp(p Provider) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.downUntil, p.Name())
+}
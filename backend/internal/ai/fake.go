@@ -0,0 +1,136 @@
+package ai
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+//go:embed fixtures/fake.json
+var defaultFakeFixtures []byte
+
+// FakeFixture scripts the replies to prompts containing Match. An empty
+// Match matches every prompt. Responses are returned in order, wrapping
+// around; if Error is set the call fails with it instead.
+type FakeFixture struct {
+	Match     string            `json:"match"`
+	Responses []json.RawMessage `json:"responses"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// FakeCall records a prompt sent to a FakeProvider
+type FakeCall struct {
+	Prompt       string
+	SystemPrompt string
+}
+
+// FakeProvider replies from fixtures without any network access, so the
+// generation pipeline can run offline and in tests. Given the same
+// sequence of prompts it always gives the same replies.
+type FakeProvider struct {
+	fixtures []FakeFixture
+
+	mu    sync.Mutex
+	next  []int
+	calls []FakeCall
+}
+
+// NewFakeProvider creates a FakeProvider. Fixtures are tried in order and
+// the first whose Match appears in the prompt is used.
+func NewFakeProvider(fixtures []FakeFixture) *FakeProvider {
+	return &FakeProvider{
+		fixtures: fixtures,
+		next:     make([]int, len(fixtures)),
+	}
+}
+
+// LoadFakeProvider creates a FakeProvider from a JSON fixture file of the
+// form {"fixtures": [...]}. An empty path loads the built-in fixtures.
+func LoadFakeProvider(path string) (*FakeProvider, error) {
+	data := defaultFakeFixtures
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read fake AI fixtures: %w", err)
+		}
+	}
+
+	var file struct {
+		Fixtures []FakeFixture `json:"fixtures"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse fake AI fixtures: %w", err)
+	}
+
+	return NewFakeProvider(file.Fixtures), nil
+}
+
+// Name identifies the provider
+func (f *FakeProvider) Name() string {
+	return "fake"
+}
+
+// Complete returns the next scripted reply for the first matching fixture
+func (f *FakeProvider) Complete(ctx context.Context, prompt string, systemPrompt string) (*Completion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeCall{Prompt: prompt, SystemPrompt: systemPrompt})
+
+	for i, fixture := range f.fixtures {
+		if !strings.Contains(prompt, fixture.Match) {
+			continue
+		}
+		if fixture.Error != "" {
+			return nil, fmt.Errorf("%s", fixture.Error)
+		}
+		if len(fixture.Responses) == 0 {
+			return nil, fmt.Errorf("fake fixture %q has no responses", fixture.Match)
+		}
+
+		text := responseText(fixture.Responses[f.next[i]%len(fixture.Responses)])
+		f.next[i]++
+
+		return &Completion{
+			Text:     text,
+			Provider: f.Name(),
+			Model:    "fake",
+			Usage: Usage{
+				InputTokens:  len(strings.Fields(systemPrompt + " " + prompt)),
+				OutputTokens: len(strings.Fields(text)),
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no fake fixture matches prompt")
+}
+
+// ValidateAPIKey always succeeds; the fake has no credentials
+func (f *FakeProvider) ValidateAPIKey(ctx context.Context) error {
+	return nil
+}
+
+// Calls returns the prompts received so far
+func (f *FakeProvider) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// responseText lets fixtures write replies either as JSON strings or, for
+// structured replies, as inline JSON values
+func responseText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return string(raw)
+}
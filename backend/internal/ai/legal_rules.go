@@ -0,0 +1,256 @@
+package ai
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//go:embed rules/legal.json
+var defaultLegalRules []byte
+
+// Rule severities
+const (
+	SeverityBlock  = "block"  // the challenge is rejected
+	SeverityReview = "review" // the challenge is held for human review
+	SeverityWarn   = "warn"   // the match is only reported as a warning
+)
+
+// Rule match kinds. All matching is case-insensitive.
+const (
+	MatchWord   = "word"   // a whole word
+	MatchPhrase = "phrase" // whole words in sequence, separated by any whitespace or hyphens
+	MatchRegex  = "regex"  // an RE2 regular expression
+)
+
+// LegalRule flags challenge text that matches Pattern
+type LegalRule struct {
+	ID       string `json:"id"`
+	Match    string `json:"match"`
+	Pattern  string `json:"pattern"`
+	Severity string `json:"severity"`
+	Message  string `json:"message,omitempty"`
+	// Allow lists contexts, as regular expressions, in which a match is
+	// acceptable, such as "nobel prize" for a rule on "prize". A match is
+	// ignored if an allowed context covers it.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// CategoryRules adjusts the rules for one category
+type CategoryRules struct {
+	Disable  []string          `json:"disable,omitempty"`  // IDs of rules that do not apply
+	Severity map[string]string `json:"severity,omitempty"` // rule ID to the severity used instead
+	Rules    []LegalRule       `json:"rules,omitempty"`    // rules that apply only here
+}
+
+// LegalRuleSet is a versioned set of rules in the JSON form they are
+// loaded from
+type LegalRuleSet struct {
+	Version    string                   `json:"version"`
+	Rules      []LegalRule              `json:"rules"`
+	Categories map[string]CategoryRules `json:"categories,omitempty"` // keyed by category slug
+}
+
+// RuleViolation reports a rule that matched challenge text
+type RuleViolation struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Field    string `json:"field"`
+	Match    string `json:"match"`
+	Message  string `json:"message"`
+}
+
+// textField is a named piece of challenge text to check
+type textField struct {
+	name string
+	text string
+}
+
+type compiledRule struct {
+	LegalRule
+	re    *regexp.Regexp
+	allow []*regexp.Regexp
+}
+
+// compiledRuleSet is a LegalRuleSet ready to match, with the effective
+// rules of each overridden category worked out up front
+type compiledRuleSet struct {
+	version    string
+	rules      []*compiledRule
+	categories map[string][]*compiledRule
+}
+
+// compileLegalRules parses and compiles a JSON rule set
+func compileLegalRules(data []byte) (*compiledRuleSet, error) {
+	var set LegalRuleSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse legal rules: %w", err)
+	}
+	if set.Version == "" {
+		return nil, fmt.Errorf("legal rules have no version")
+	}
+
+	compiled := &compiledRuleSet{
+		version:    set.Version,
+		categories: make(map[string][]*compiledRule),
+	}
+
+	byID := make(map[string]*compiledRule, len(set.Rules))
+	for _, rule := range set.Rules {
+		c, err := compileRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		if byID[rule.ID] != nil {
+			return nil, fmt.Errorf("duplicate legal rule %q", rule.ID)
+		}
+		byID[rule.ID] = c
+		compiled.rules = append(compiled.rules, c)
+	}
+
+	for slug, overrides := range set.Categories {
+		disabled := make(map[string]bool, len(overrides.Disable))
+		for _, id := range overrides.Disable {
+			if byID[id] == nil {
+				return nil, fmt.Errorf("category %q disables unknown legal rule %q", slug, id)
+			}
+			disabled[id] = true
+		}
+		for id, severity := range overrides.Severity {
+			if byID[id] == nil {
+				return nil, fmt.Errorf("category %q overrides unknown legal rule %q", slug, id)
+			}
+			if !validSeverity(severity) {
+				return nil, fmt.Errorf("category %q gives legal rule %q unknown severity %q", slug, id, severity)
+			}
+		}
+
+		var rules []*compiledRule
+		for _, rule := range compiled.rules {
+			if disabled[rule.ID] {
+				continue
+			}
+			if severity, ok := overrides.Severity[rule.ID]; ok {
+				overridden := *rule
+				overridden.Severity = severity
+				rule = &overridden
+			}
+			rules = append(rules, rule)
+		}
+		for _, rule := range overrides.Rules {
+			c, err := compileRule(rule)
+			if err != nil {
+				return nil, fmt.Errorf("category %q: %w", slug, err)
+			}
+			rules = append(rules, c)
+		}
+		compiled.categories[slug] = rules
+	}
+
+	return compiled, nil
+}
+
+func compileRule(rule LegalRule) (*compiledRule, error) {
+	if rule.ID == "" {
+		return nil, fmt.Errorf("legal rule %q has no id", rule.Pattern)
+	}
+	if !validSeverity(rule.Severity) {
+		return nil, fmt.Errorf("legal rule %q has unknown severity %q", rule.ID, rule.Severity)
+	}
+	if strings.TrimSpace(rule.Pattern) == "" {
+		return nil, fmt.Errorf("legal rule %q has no pattern", rule.ID)
+	}
+
+	var expr string
+	switch rule.Match {
+	case MatchWord:
+		expr = `\b` + regexp.QuoteMeta(strings.TrimSpace(rule.Pattern)) + `\b`
+	case MatchPhrase:
+		words := strings.Fields(rule.Pattern)
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		expr = `\b` + strings.Join(words, `[\s-]+`) + `\b`
+	case MatchRegex:
+		expr = rule.Pattern
+	default:
+		return nil, fmt.Errorf("legal rule %q has unknown match %q", rule.ID, rule.Match)
+	}
+
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, fmt.Errorf("legal rule %q: %w", rule.ID, err)
+	}
+
+	c := &compiledRule{LegalRule: rule, re: re}
+	for _, allow := range rule.Allow {
+		allowRe, err := regexp.Compile("(?i)" + allow)
+		if err != nil {
+			return nil, fmt.Errorf("legal rule %q allow %q: %w", rule.ID, allow, err)
+		}
+		c.allow = append(c.allow, allowRe)
+	}
+
+	return c, nil
+}
+
+func validSeverity(severity string) bool {
+	return severity == SeverityBlock || severity == SeverityReview || severity == SeverityWarn
+}
+
+// forCategory returns the rules that apply to a category
+func (s *compiledRuleSet) forCategory(slug string) []*compiledRule {
+	if rules, ok := s.categories[slug]; ok {
+		return rules
+	}
+	return s.rules
+}
+
+// check reports each rule that matches any field, once per rule and field
+func (s *compiledRuleSet) check(fields []textField, category string) []RuleViolation {
+	var violations []RuleViolation
+	for _, rule := range s.forCategory(category) {
+		for _, field := range fields {
+			match := rule.find(field.text)
+			if match == "" {
+				continue
+			}
+
+			message := rule.Message
+			if message == "" {
+				message = "Matches legal rule"
+			}
+			violations = append(violations, RuleViolation{
+				RuleID:   rule.ID,
+				Severity: rule.Severity,
+				Field:    field.name,
+				Match:    match,
+				Message:  message,
+			})
+		}
+	}
+	return violations
+}
+
+// find returns the first match in text not covered by an allowed context
+func (r *compiledRule) find(text string) string {
+	for _, loc := range r.re.FindAllStringIndex(text, -1) {
+		if !r.allowed(text, loc) {
+			return text[loc[0]:loc[1]]
+		}
+	}
+	return ""
+}
+
+func (r *compiledRule) allowed(text string, loc []int) bool {
+	for _, allow := range r.allow {
+		for _, span := range allow.FindAllStringIndex(text, -1) {
+			if span[0] <= loc[0] && loc[1] <= span[1] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func compileSet(t *testing.T, set LegalRuleSet) (*compiledRuleSet, error) {
+	t.Helper()
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal rule set: %v", err)
+	}
+	return compileLegalRules(data)
+}
+
+func TestDefaultLegalRulesCompile(t *testing.T) {
+	if _, err := compileLegalRules(defaultLegalRules); err != nil {
+		t.Fatalf("default legal rules: %v", err)
+	}
+}
+
+func TestLegalRuleMatching(t *testing.T) {
+	tests := []struct {
+		name string
+		rule LegalRule
+		text string
+		want string
+	}{
+		{name: "word", rule: LegalRule{Match: MatchWord, Pattern: "bet"}, text: "Place a bet now", want: "bet"},
+		{name: "word ignores case", rule: LegalRule{Match: MatchWord, Pattern: "bet"}, text: "BET on it", want: "BET"},
+		{name: "word needs boundaries", rule: LegalRule{Match: MatchWord, Pattern: "bet"}, text: "The alphabet and Betty", want: ""},
+		{name: "word next to punctuation", rule: LegalRule{Match: MatchWord, Pattern: "bet"}, text: "Who won the bet?", want: "bet"},
+		{name: "word pattern is literal", rule: LegalRule{Match: MatchWord, Pattern: "a.b"}, text: "axb", want: ""},
+		{name: "phrase", rule: LegalRule{Match: MatchPhrase, Pattern: "cash prize"}, text: "Win a cash prize", want: "cash prize"},
+		{name: "phrase across whitespace", rule: LegalRule{Match: MatchPhrase, Pattern: "cash prize"}, text: "Win a cash\n  prize", want: "cash\n  prize"},
+		{name: "phrase across hyphens", rule: LegalRule{Match: MatchPhrase, Pattern: "cash prize"}, text: "a cash-prize draw", want: "cash-prize"},
+		{name: "phrase needs its words in order", rule: LegalRule{Match: MatchPhrase, Pattern: "cash prize"}, text: "prize cash", want: ""},
+		{name: "phrase needs whole words", rule: LegalRule{Match: MatchPhrase, Pattern: "cash prize"}, text: "cash prizes", want: ""},
+		{name: "regex", rule: LegalRule{Match: MatchRegex, Pattern: `odds? of \d+`}, text: "The odds of 5 to 1", want: "odds of 5"},
+		{
+			name: "allowed context covers the match",
+			rule: LegalRule{Match: MatchWord, Pattern: "prize", Allow: []string{`nobel prize`}},
+			text: "Who won the Nobel Prize in 1921?",
+			want: "",
+		},
+		{
+			name: "match outside the allowed context still counts",
+			rule: LegalRule{Match: MatchWord, Pattern: "prize", Allow: []string{`nobel prize`}},
+			text: "The Nobel Prize winner claims a prize",
+			want: "prize",
+		},
+		{
+			name: "allowed context must cover the whole match",
+			rule: LegalRule{Match: MatchPhrase, Pattern: "prize money", Allow: []string{`nobel prize`}},
+			text: "Nobel prize money",
+			want: "prize money",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.ID = "rule"
+			tt.rule.Severity = SeverityBlock
+			set, err := compileSet(t, LegalRuleSet{Version: "test", Rules: []LegalRule{tt.rule}})
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			if got := set.rules[0].find(tt.text); got != tt.want {
+				t.Errorf("find(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegalRuleCategories(t *testing.T) {
+	set, err := compileSet(t, LegalRuleSet{
+		Version: "test",
+		Rules: []LegalRule{
+			{ID: "bet", Match: MatchWord, Pattern: "bet", Severity: SeverityBlock},
+			{ID: "odds", Match: MatchWord, Pattern: "odds", Severity: SeverityBlock},
+		},
+		Categories: map[string]CategoryRules{
+			"poker": {
+				Disable:  []string{"bet"},
+				Severity: map[string]string{"odds": SeverityWarn},
+				Rules:    []LegalRule{{ID: "rake", Match: MatchWord, Pattern: "rake", Severity: SeverityReview}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	fields := []textField{{name: "question", text: "A bet at long odds, minus the rake"}}
+
+	tests := []struct {
+		category string
+		want     []string // rule:severity
+	}{
+		{category: "football", want: []string{"bet:block", "odds:block"}},
+		{category: "poker", want: []string{"odds:warn", "rake:review"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			var got []string
+			for _, v := range set.check(fields, tt.category) {
+				got = append(got, v.RuleID+":"+v.Severity)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Overriding a category must not change the shared rule
+	if got := set.forCategory("football")[1].Severity; got != SeverityBlock {
+		t.Errorf("shared odds rule severity = %q, want %q", got, SeverityBlock)
+	}
+}
+
+func TestCompileLegalRulesErrors(t *testing.T) {
+	rule := LegalRule{ID: "bet", Match: MatchWord, Pattern: "bet", Severity: SeverityBlock}
+
+	tests := []struct {
+		name string
+		set  LegalRuleSet
+		want string
+	}{
+		{name: "no version", set: LegalRuleSet{Rules: []LegalRule{rule}}, want: "no version"},
+		{name: "no id", set: LegalRuleSet{Version: "v", Rules: []LegalRule{{Match: MatchWord, Pattern: "bet", Severity: SeverityBlock}}}, want: "has no id"},
+		{name: "no pattern", set: LegalRuleSet{Version: "v", Rules: []LegalRule{{ID: "x", Match: MatchWord, Pattern: " ", Severity: SeverityBlock}}}, want: "has no pattern"},
+		{name: "unknown severity", set: LegalRuleSet{Version: "v", Rules: []LegalRule{{ID: "x", Match: MatchWord, Pattern: "bet", Severity: "fatal"}}}, want: "unknown severity"},
+		{name: "unknown match", set: LegalRuleSet{Version: "v", Rules: []LegalRule{{ID: "x", Match: "glob", Pattern: "bet", Severity: SeverityBlock}}}, want: "unknown match"},
+		{name: "bad regex", set: LegalRuleSet{Version: "v", Rules: []LegalRule{{ID: "x", Match: MatchRegex, Pattern: "(", Severity: SeverityBlock}}}, want: `legal rule "x"`},
+		{name: "bad allow", set: LegalRuleSet{Version: "v", Rules: []LegalRule{{ID: "x", Match: MatchWord, Pattern: "bet", Severity: SeverityBlock, Allow: []string{"["}}}}, want: "allow"},
+		{name: "duplicate id", set: LegalRuleSet{Version: "v", Rules: []LegalRule{rule, rule}}, want: "duplicate"},
+		{
+			name: "category disables unknown rule",
+			set:  LegalRuleSet{Version: "v", Rules: []LegalRule{rule}, Categories: map[string]CategoryRules{"c": {Disable: []string{"nope"}}}},
+			want: "disables unknown",
+		},
+		{
+			name: "category overrides unknown rule",
+			set:  LegalRuleSet{Version: "v", Rules: []LegalRule{rule}, Categories: map[string]CategoryRules{"c": {Severity: map[string]string{"nope": SeverityWarn}}}},
+			want: "overrides unknown",
+		},
+		{
+			name: "category override with unknown severity",
+			set:  LegalRuleSet{Version: "v", Rules: []LegalRule{rule}, Categories: map[string]CategoryRules{"c": {Severity: map[string]string{"bet": "fatal"}}}},
+			want: "unknown severity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileSet(t, tt.set)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,99 +1,174 @@
 package ai
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
 // LegalValidator validates generated challenges for legal compliance
+// against a versioned rule set. Rules loaded from a file can be reloaded
+// while the server runs.
 type LegalValidator struct {
-	// Forbidden patterns that violate legal/ethical rules
-	forbiddenPatterns []string
-	// Warning patterns that need human review
-	warningPatterns []string
+	path string // rule file; empty for the built-in rules
+
+	mu      sync.RWMutex
+	rules   *compiledRuleSet
+	modTime time.Time
 }
 
-// NewLegalValidator creates a new legal validator
+// NewLegalValidator creates a legal validator with the built-in rules
 func NewLegalValidator() *LegalValidator {
-	return &LegalValidator{
-		forbiddenPatterns: []string{
-			// Celebrity endorsements
-			"uses", "recommends", "prefers", "loves", "favorite",
-			"endorses", "sponsors", "partners with",
-			
-			// Gambling/prizes
-			"win", "prize", "jackpot", "lottery", "sweepstakes",
-			"cash prize", "money", "payout", "winnings",
-			
-			// Health claims
-			"cures", "treats", "heals", "prevents disease",
-			"medical benefit", "health benefit",
-			
-			// False claims
-			"always", "never fails", "guaranteed", "proven fact",
-			"scientifically proven" + " (without source)",
-		},
-		warningPatterns: []string{
-			// Potentially controversial
-			"political", "religion", "religious", "violence",
-			"drug", "alcohol", "weapon", "crime",
-			
-			// Sensitive topics
-			"death", "tragedy", "scandal", "controversy",
-			"lawsuit", "legal issue", "arrested",
-		},
+	rules, err := compileLegalRules(defaultLegalRules)
+	if err != nil {
+		panic(fmt.Sprintf("built-in legal rules are invalid: %v", err))
+	}
+	return &LegalValidator{rules: rules}
+}
+
+// LoadLegalValidator creates a legal validator from a JSON rule file. An
+// empty path uses the built-in rules.
+func LoadLegalValidator(path string) (*LegalValidator, error) {
+	if path == "" {
+		return NewLegalValidator(), nil
+	}
+
+	v := &LegalValidator{path: path}
+	if _, err := v.Reload(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Reload re-reads the rule file if it changed since it was last loaded.
+// If the new rules are invalid the current ones stay in use.
+func (v *LegalValidator) Reload() (bool, error) {
+	if v.path == "" {
+		return false, nil
+	}
+
+	info, err := os.Stat(v.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat legal rules: %w", err)
+	}
+
+	v.mu.RLock()
+	unchanged := v.rules != nil && info.ModTime().Equal(v.modTime)
+	v.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(v.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read legal rules: %w", err)
+	}
+	rules, err := compileLegalRules(data)
+	if err != nil {
+		return false, err
+	}
+
+	v.mu.Lock()
+	v.rules = rules
+	v.modTime = info.ModTime()
+	v.mu.Unlock()
+
+	return true, nil
+}
+
+// Watch reloads the rule file every interval until ctx is cancelled
+func (v *LegalValidator) Watch(ctx context.Context, interval time.Duration) {
+	if v.path == "" || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := v.Reload()
+			if err != nil {
+				log.Printf("⚠ Failed to reload legal rules, keeping version %s: %v", v.RulesVersion(), err)
+			} else if reloaded {
+				log.Printf("✓ Loaded legal rules version %s", v.RulesVersion())
+			}
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
+// RulesVersion is the version of the rules in use
+func (v *LegalValidator) RulesVersion() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.rules.version
+}
+
 // ValidationResult represents the result of validation
 type ValidationResult struct {
-	IsValid   bool     `json:"is_valid"`
-	Errors    []string `json:"errors,omitempty"`
-	Warnings  []string `json:"warnings,omitempty"`
-	Passed    bool     `json:"passed"`
-	NeedsReview bool   `json:"needs_review"`
+	IsValid      bool            `json:"is_valid"`
+	Errors       []string        `json:"errors,omitempty"`
+	Warnings     []string        `json:"warnings,omitempty"`
+	Passed       bool            `json:"passed"`
+	NeedsReview  bool            `json:"needs_review"`
+	Violations   []RuleViolation `json:"violations,omitempty"` // legal rules that fired
+	RulesVersion string          `json:"rules_version,omitempty"`
 }
 
-// ValidateChallenge validates a generated challenge
-func (v *LegalValidator) ValidateChallenge(challenge *GeneratedChallenge) *ValidationResult {
+// ValidateChallenge validates a generated challenge of the given type
+// using the rules for category, a category slug
+func (v *LegalValidator) ValidateChallenge(challenge *GeneratedChallenge, challengeType string, category string) *ValidationResult {
+	v.mu.RLock()
+	rules := v.rules
+	v.mu.RUnlock()
+
 	result := &ValidationResult{
-		IsValid:  true,
-		Passed:   true,
-		Errors:   []string{},
-		Warnings: []string{},
-	}
-
-	// Combine all text for validation
-	fullText := strings.ToLower(fmt.Sprintf("%s %s %s",
-		challenge.Title,
-		challenge.Description,
-		challenge.Question,
-	))
-	
+		IsValid:      true,
+		Passed:       true,
+		Errors:       []string{},
+		Warnings:     []string{},
+		RulesVersion: rules.version,
+	}
+
+	// Check each piece of text separately so a match can't span fields
+	fields := []textField{
+		{"title", challenge.Title},
+		{"description", challenge.Description},
+		{"question", challenge.Question},
+		{"explanation", challenge.Explanation},
+	}
 	for _, opt := range challenge.Options {
-		fullText += " " + strings.ToLower(opt.Text)
+		fields = append(fields, textField{"option " + opt.ID, opt.Text})
 	}
-	
-	if challenge.Explanation != "" {
-		fullText += " " + strings.ToLower(challenge.Explanation)
+	for i, item := range challenge.Sequence {
+		fields = append(fields, textField{fmt.Sprintf("sequence %d", i+1), item})
 	}
+	for i, source := range challenge.Sources {
+		fields = append(fields, textField{fmt.Sprintf("source %d", i+1), source})
+	}
+
+	result.Violations = rules.check(fields, category)
+	for _, violation := range result.Violations {
+		message := fmt.Sprintf("%s: %s ('%s' in %s)",
+			violation.RuleID, violation.Message, violation.Match, violation.Field)
 
-	// Check forbidden patterns
-	for _, pattern := range v.forbiddenPatterns {
-		if strings.Contains(fullText, strings.ToLower(pattern)) {
+		switch violation.Severity {
+		case SeverityBlock:
 			result.IsValid = false
 			result.Passed = false
-			result.Errors = append(result.Errors, 
-				fmt.Sprintf("Contains forbidden pattern: '%s'", pattern))
-		}
-	}
-
-	// Check warning patterns
-	for _, pattern := range v.warningPatterns {
-		if strings.Contains(fullText, strings.ToLower(pattern)) {
+			result.Errors = append(result.Errors, message)
+		case SeverityReview:
 			result.NeedsReview = true
-			result.Warnings = append(result.Warnings, 
-				fmt.Sprintf("Contains sensitive content: '%s'", pattern))
+			result.Warnings = append(result.Warnings, message)
+		default:
+			result.Warnings = append(result.Warnings, message)
 		}
 	}
 
@@ -122,20 +197,43 @@ func (v *LegalValidator) ValidateChallenge(challenge *GeneratedChallenge) *Valid
 		result.Errors = append(result.Errors, "Correct answer is required")
 	}
 
-	// Validate correct answer exists in options
-	correctAnswerExists := false
-	for _, opt := range challenge.Options {
-		if opt.ID == challenge.CorrectAnswer {
-			correctAnswerExists = true
-			break
+	// Timelines answer with every option in order
+	if challengeType == "timeline" {
+		if len(challenge.Options) < 3 {
+			result.IsValid = false
+			result.Passed = false
+			result.Errors = append(result.Errors, "Timeline needs at least 3 events")
+		}
+		if _, err := ParseTimelineOrder(challenge); err != nil {
+			result.IsValid = false
+			result.Passed = false
+			result.Errors = append(result.Errors, err.Error())
+		}
+	} else {
+		// Patterns must also be well formed for their kind
+		if challengeType == "pattern" {
+			if err := CheckPattern(challenge); err != nil {
+				result.IsValid = false
+				result.Passed = false
+				result.Errors = append(result.Errors, err.Error())
+			}
+		}
+
+		// Validate correct answer exists in options
+		correctAnswerExists := false
+		for _, opt := range challenge.Options {
+			if opt.ID == challenge.CorrectAnswer {
+				correctAnswerExists = true
+				break
+			}
+		}
+
+		if !correctAnswerExists {
+			result.IsValid = false
+			result.Passed = false
+			result.Errors = append(result.Errors,
+				fmt.Sprintf("Correct answer '%s' not found in options", challenge.CorrectAnswer))
 		}
-	}
-	
-	if !correctAnswerExists {
-		result.IsValid = false
-		result.Passed = false
-		result.Errors = append(result.Errors, 
-			fmt.Sprintf("Correct answer '%s' not found in options", challenge.CorrectAnswer))
 	}
 
 	// Validate title length
@@ -205,6 +303,9 @@ func (v *LegalValidator) ValidateQuestionQuality(challenge *GeneratedChallenge)
 	return issues
 }
 
+// maxSources caps the source references kept with a generated challenge
+const maxSources = 5
+
 // SanitizeChallenge removes or replaces problematic content
 func (v *LegalValidator) SanitizeChallenge(challenge *GeneratedChallenge) *GeneratedChallenge {
 	// Create a copy
@@ -221,6 +322,27 @@ func (v *LegalValidator) SanitizeChallenge(challenge *GeneratedChallenge) *Gener
 		sanitized.Options[i].Text = strings.TrimSpace(sanitized.Options[i].Text)
 	}
 
+	// Sanitize pattern items
+	if len(sanitized.Sequence) > 0 {
+		sanitized.Sequence = make([]string, len(challenge.Sequence))
+		for i, item := range challenge.Sequence {
+			sanitized.Sequence[i] = strings.TrimSpace(item)
+		}
+	}
+
+	// Sanitize sources, dropping blank ones
+	if len(sanitized.Sources) > 0 {
+		sanitized.Sources = make([]string, 0, len(challenge.Sources))
+		for _, source := range challenge.Sources {
+			if source = strings.TrimSpace(source); source != "" {
+				sanitized.Sources = append(sanitized.Sources, source)
+			}
+		}
+		if len(sanitized.Sources) > maxSources {
+			sanitized.Sources = sanitized.Sources[:maxSources]
+		}
+	}
+
 	// Truncate if too long
 	if len(sanitized.Title) > 100 {
 		sanitized.Title = sanitized.Title[:97] + "..."
@@ -0,0 +1,149 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultOpenAIBaseURL is the OpenAI API; any server implementing the chat
+// completions endpoint can be used instead
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// DefaultOpenAIModel is used when no model is configured
+const DefaultOpenAIModel = "gpt-4o-mini"
+
+// OpenAIClient talks to OpenAI or an OpenAI-compatible chat completions API
+type OpenAIClient struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	model      string
+}
+
+// NewOpenAIClient creates a new OpenAI-compatible API client. baseURL
+// defaults to the OpenAI API.
+func NewOpenAIClient(apiKey, model, baseURL string) *OpenAIClient {
+	if model == "" {
+		model = DefaultOpenAIModel
+	}
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	return &OpenAIClient{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		model: model,
+	}
+}
+
+// chatCompletionRequest is the request to the chat completions endpoint
+type chatCompletionRequest struct {
+	Model       string    `json:"model"`
+	MaxTokens   int       `json:"max_tokens"`
+	Messages    []Message `json:"messages"`
+	Temperature float64   `json:"temperature,omitempty"`
+}
+
+// chatCompletionResponse is the chat completions endpoint's response
+type chatCompletionResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// Name identifies the provider
+func (c *OpenAIClient) Name() string {
+	return "openai"
+}
+
+// Complete sends prompt to the chat completions endpoint and returns the reply
+func (c *OpenAIClient) Complete(
+	ctx context.Context,
+	prompt string,
+	systemPrompt string,
+) (*Completion, error) {
+	messages := []Message{}
+	if systemPrompt != "" {
+		messages = append(messages, Message{Role: "system", Content: systemPrompt})
+	}
+	messages = append(messages, Message{Role: "user", Content: prompt})
+
+	reqBody := chatCompletionRequest{
+		Model:       c.model,
+		MaxTokens:   2000,
+		Temperature: 0.7,
+		Messages:    messages,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		c.baseURL+"/chat/completions",
+		bytes.NewBuffer(jsonData),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var response chatCompletionResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in response")
+	}
+
+	return &Completion{
+		Text:     response.Choices[0].Message.Content,
+		Provider: c.Name(),
+		Model:    response.Model,
+		Usage: Usage{
+			InputTokens:  response.Usage.PromptTokens,
+			OutputTokens: response.Usage.CompletionTokens,
+		},
+	}, nil
+}
+
+// ValidateAPIKey checks if the API key is valid
+func (c *OpenAIClient) ValidateAPIKey(ctx context.Context) error {
+	return ping(ctx, c)
+}
@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ModelPrice is what a model charges, in US dollars per million tokens
+type ModelPrice struct {
+	InputPerMTok  float64 `json:"input_per_mtok"`
+	OutputPerMTok float64 `json:"output_per_mtok"`
+}
+
+// defaultPrices are list prices of the models the providers are normally
+// configured with. Keys are model name prefixes, so dated snapshots such as
+// "claude-sonnet-4-20250514" are priced by their family.
+var defaultPrices = map[string]ModelPrice{
+	"claude-opus-4":     {InputPerMTok: 15, OutputPerMTok: 75},
+	"claude-sonnet-4":   {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-3-7-sonnet": {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-3-5-sonnet": {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-3-5-haiku":  {InputPerMTok: 0.8, OutputPerMTok: 4},
+	"claude-3-haiku":    {InputPerMTok: 0.25, OutputPerMTok: 1.25},
+	"gpt-4o":            {InputPerMTok: 2.5, OutputPerMTok: 10},
+	"gpt-4o-mini":       {InputPerMTok: 0.15, OutputPerMTok: 0.6},
+	"gpt-4.1":           {InputPerMTok: 2, OutputPerMTok: 8},
+	"gpt-4.1-mini":      {InputPerMTok: 0.4, OutputPerMTok: 1.6},
+	"gpt-4.1-nano":      {InputPerMTok: 0.1, OutputPerMTok: 0.4},
+	"fake":              {},
+}
+
+// Pricing estimates what completions cost
+type Pricing struct {
+	prices map[string]ModelPrice
+}
+
+// NewPricing creates a Pricing from the built-in prices, with overrides
+// replacing or adding to them
+func NewPricing(overrides map[string]ModelPrice) *Pricing {
+	prices := make(map[string]ModelPrice, len(defaultPrices)+len(overrides))
+	for model, price := range defaultPrices {
+		prices[model] = price
+	}
+	for model, price := range overrides {
+		prices[model] = price
+	}
+	return &Pricing{prices: prices}
+}
+
+// ParsePricing parses price overrides written as "model=input/output",
+// in dollars per million tokens (e.g. "gpt-4o-mini=0.15/0.6")
+func ParsePricing(entries []string) (map[string]ModelPrice, error) {
+	prices := make(map[string]ModelPrice, len(entries))
+	for _, entry := range entries {
+		model, rates, ok := strings.Cut(entry, "=")
+		inputStr, outputStr, ok2 := strings.Cut(rates, "/")
+		model = strings.TrimSpace(model)
+		if !ok || !ok2 || model == "" {
+			return nil, fmt.Errorf("invalid AI price %q, want model=input/output", entry)
+		}
+
+		input, err := strconv.ParseFloat(strings.TrimSpace(inputStr), 64)
+		if err != nil || input < 0 {
+			return nil, fmt.Errorf("invalid input price in %q", entry)
+		}
+		output, err := strconv.ParseFloat(strings.TrimSpace(outputStr), 64)
+		if err != nil || output < 0 {
+			return nil, fmt.Errorf("invalid output price in %q", entry)
+		}
+
+		prices[model] = ModelPrice{InputPerMTok: input, OutputPerMTok: output}
+	}
+	return prices, nil
+}
+
+// Estimate returns the cost in US dollars of usage on model, or false if
+// the model has no known price. The longest matching model prefix is used.
+func (p *Pricing) Estimate(model string, usage Usage) (float64, bool) {
+	price, ok := p.lookup(model)
+	if !ok {
+		return 0, false
+	}
+	cost := float64(usage.InputTokens)*price.InputPerMTok/1e6 +
+		float64(usage.OutputTokens)*price.OutputPerMTok/1e6
+	return cost, true
+}
+
+func (p *Pricing) lookup(model string) (ModelPrice, bool) {
+	if price, ok := p.prices[model]; ok {
+		return price, true
+	}
+
+	var best string
+	for prefix := range p.prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return p.prices[best], true
+}
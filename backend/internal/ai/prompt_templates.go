@@ -25,7 +25,7 @@ CRITICAL RULES:
 3. NO medical/health claims about products or people
 4. NO false statements about public figures
 5. FOCUS on cultural knowledge, historical facts, and artistic appreciation
-6. Questions must be factual and verifiable
+6. Questions must be factual and verifiable, and cite where the answer can be checked
 7. Avoid controversial topics (politics, religion, violence)
 8. Keep content family-friendly (suitable for ages 13+)
 
@@ -42,7 +42,8 @@ Respond ONLY with valid JSON in this exact structure:
     {"id": "d", "text": "Option D"}
   ],
   "correct_answer": "a",
-  "explanation": "Why this answer is correct (optional)",
+  "explanation": "Why this answer is correct, shown to players after they answer",
+  "sources": ["Where the answer can be verified, e.g. a publication or official site"],
   "difficulty_justification": "Why this is difficulty X"
 }`
 }
@@ -106,6 +107,7 @@ Requirements:
 - All events must be related to %s
 - Events should span different time periods
 - Events must be factual and verifiable
+- Do NOT put dates or years in the event text; that gives the order away
 - Difficulty appropriate for %s
 
 Output format:
@@ -114,21 +116,86 @@ Output format:
   "description": "Description",
   "question": "Arrange these events in chronological order (earliest to latest)",
   "options": [
-    {"id": "a", "text": "Event 1 (Year)"},
-    {"id": "b", "text": "Event 2 (Year)"},
-    {"id": "c", "text": "Event 3 (Year)"},
-    {"id": "d", "text": "Event 4 (Year)"}
+    {"id": "a", "text": "Event 1"},
+    {"id": "b", "text": "Event 2"},
+    {"id": "c", "text": "Event 3"},
+    {"id": "d", "text": "Event 4"}
   ],
   "correct_answer": "b,a,d,c",
-  "explanation": "Chronological order explanation",
+  "explanation": "The events in order, with their years",
+  "sources": ["Where this can be verified"],
   "difficulty_justification": "Why this difficulty"
 }
 
+"correct_answer" lists every option ID from earliest to latest.
+
 Remember: NO celebrity endorsements, NO prize claims, ONLY factual information.`,
 		categoryName, categoryDescription, difficultyTier, difficultyDesc,
 		categoryName, difficultyDesc)
 }
 
+// BuildPatternPrompt generates a prompt for pattern-recognition challenges
+func (b *ChallengePromptBuilder) BuildPatternPrompt(
+	categoryName string,
+	categoryDescription string,
+	difficultyTier int,
+	existingChallenges []string,
+) string {
+	difficultyDesc := b.getDifficultyDescription(difficultyTier)
+	
+	prompt := fmt.Sprintf(`Generate a pattern challenge for: "%s"
+Category: %s
+Difficulty: Tier %d (%s)
+
+Create ONE of these two kinds of pattern question:
+
+1. "missing_element": a sequence of 4 to 6 items that follow a clear rule,
+   with exactly one item replaced by "?". The options are candidates for
+   the missing item and only one fits the rule.
+2. "odd_one_out": 4 or 5 items where all but one share a clear rule. The
+   options are the items themselves and the answer is the one that does
+   not belong. Leave "sequence" empty.
+
+Requirements:
+- Items must relate to %s (albums in release order, members of a group,
+  stages of a career, tournament hosts, and so on)
+- The rule must be factual and verifiable, with only one defensible answer
+- Do NOT state the rule in the question; explain it in "explanation"
+- Difficulty appropriate for %s
+
+`, categoryName, categoryDescription, difficultyTier, difficultyDesc, categoryName, difficultyDesc)
+
+	if len(existingChallenges) > 0 {
+		prompt += fmt.Sprintf(`DO NOT create questions similar to these existing ones:
+%s
+
+`, strings.Join(existingChallenges, "\n"))
+	}
+
+	prompt += `Output format:
+{
+  "title": "Challenge title",
+  "description": "Description",
+  "question": "Which item completes the sequence?",
+  "pattern_kind": "missing_element",
+  "sequence": ["Item 1", "Item 2", "?", "Item 4"],
+  "options": [
+    {"id": "a", "text": "Candidate A"},
+    {"id": "b", "text": "Candidate B"},
+    {"id": "c", "text": "Candidate C"},
+    {"id": "d", "text": "Candidate D"}
+  ],
+  "correct_answer": "b",
+  "explanation": "The rule behind the pattern",
+  "sources": ["Where this can be verified"],
+  "difficulty_justification": "Why this difficulty"
+}
+
+Remember: NO celebrity endorsements, NO prize claims, ONLY factual information.`
+
+	return prompt
+}
+
 // BuildTrueFalsePrompt generates a prompt for true/false challenges
 func (b *ChallengePromptBuilder) BuildTrueFalsePrompt(
 	categoryName string,
@@ -146,7 +213,7 @@ Create a statement that users must identify as true or false.
 Requirements:
 - Statement must be factual and verifiable
 - Should test knowledge appropriate for %s
-- Include a brief explanation
+- Include a brief explanation and where it can be verified
 
 Output format:
 {
@@ -159,6 +226,7 @@ Output format:
   ],
   "correct_answer": "a",
   "explanation": "Why this is true/false",
+  "sources": ["Where this can be verified"],
   "difficulty_justification": "Why this difficulty"
 }
 
@@ -192,14 +260,100 @@ type GeneratedChallenge struct {
 	Options                 []ChallengeOption `json:"options"`
 	CorrectAnswer           string           `json:"correct_answer"`
 	Explanation             string           `json:"explanation"`
+	Sources                 []string         `json:"sources,omitempty"`
 	DifficultyJustification string           `json:"difficulty_justification"`
+
+	// Pattern challenges only
+	PatternKind string   `json:"pattern_kind,omitempty"`
+	Sequence    []string `json:"sequence,omitempty"`
 }
 
+// Kinds of pattern challenge
+const (
+	// PatternMissingElement asks for the item that fills the gap in Sequence
+	PatternMissingElement = "missing_element"
+	// PatternOddOneOut asks which option does not belong with the others
+	PatternOddOneOut = "odd_one_out"
+)
+
+// PatternGap marks the missing item in a missing-element sequence
+const PatternGap = "?"
+
 type ChallengeOption struct {
 	ID   string `json:"id"`
 	Text string `json:"text"`
 }
 
+// ParseTimelineOrder reads a timeline's correct_answer, a comma-separated
+// list of option IDs from earliest to latest, and checks that it orders
+// every option exactly once
+func ParseTimelineOrder(challenge *GeneratedChallenge) ([]string, error) {
+	ids := make(map[string]bool, len(challenge.Options))
+	for _, opt := range challenge.Options {
+		ids[opt.ID] = true
+	}
+
+	order := strings.Split(challenge.CorrectAnswer, ",")
+	seen := make(map[string]bool, len(order))
+	for i, id := range order {
+		id = strings.TrimSpace(id)
+		if !ids[id] {
+			return nil, fmt.Errorf("timeline order references unknown event '%s'", id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("timeline order lists event '%s' twice", id)
+		}
+		seen[id] = true
+		order[i] = id
+	}
+
+	if len(order) != len(challenge.Options) {
+		return nil, fmt.Errorf("timeline order must include all %d events", len(challenge.Options))
+	}
+
+	return order, nil
+}
+
+// CheckPattern checks that a pattern challenge is well formed for its kind
+func CheckPattern(challenge *GeneratedChallenge) error {
+	switch challenge.PatternKind {
+	case PatternMissingElement:
+		if len(challenge.Sequence) < 3 {
+			return fmt.Errorf("pattern sequence needs at least 3 items")
+		}
+		gaps := 0
+		for _, item := range challenge.Sequence {
+			if strings.TrimSpace(item) == PatternGap {
+				gaps++
+			}
+		}
+		if gaps != 1 {
+			return fmt.Errorf("pattern sequence must have exactly one '%s' gap, found %d", PatternGap, gaps)
+		}
+		for _, opt := range challenge.Options {
+			if opt.ID != challenge.CorrectAnswer {
+				continue
+			}
+			for _, item := range challenge.Sequence {
+				if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(opt.Text)) {
+					return fmt.Errorf("pattern answer '%s' already appears in the sequence", opt.Text)
+				}
+			}
+		}
+	case PatternOddOneOut:
+		if len(challenge.Options) < 4 {
+			return fmt.Errorf("odd-one-out pattern needs at least 4 items")
+		}
+		if len(challenge.Sequence) > 0 {
+			return fmt.Errorf("odd-one-out pattern must not have a sequence")
+		}
+	default:
+		return fmt.Errorf("unknown pattern kind '%s'", challenge.PatternKind)
+	}
+
+	return nil
+}
+
 // CleanJSONResponse removes markdown code blocks from AI response
 func CleanJSONResponse(response string) string {
 	// Remove ```json and ``` markers
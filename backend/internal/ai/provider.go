@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"context"
+)
+
+// Provider is a large language model backend
+type Provider interface {
+	// Name identifies the provider in logs and results
+	Name() string
+	// Complete sends prompt with systemPrompt and returns the model's reply
+	Complete(ctx context.Context, prompt string, systemPrompt string) (*Completion, error)
+	// ValidateAPIKey checks that the provider accepts its credentials
+	ValidateAPIKey(ctx context.Context) error
+}
+
+// Completion is a model's reply to a prompt
+type Completion struct {
+	Text     string `json:"text"`
+	Provider string `json:"provider"` // provider that served the request
+	Model    string `json:"model"`
+	Usage    Usage  `json:"usage"`
+}
+
+// Usage counts the tokens a completion consumed
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// ping sends a minimal prompt to check that p is reachable and authorized
+func ping(ctx context.Context, p Provider) error {
+	_, err := p.Complete(
+		ctx,
+		"Say 'API key is valid' if you can read this.",
+		"You are a test assistant.",
+	)
+	return err
+}
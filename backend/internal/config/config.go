@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,11 +12,16 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	AI       AIConfig
+	App        AppConfig
+	Database   DatabaseConfig
+	Redis      RedisConfig
+	JWT        JWTConfig
+	AI         AIConfig
+	Challenge  ChallengeConfig
+	RateLimit  RateLimitConfig
+	Mail       MailConfig
+	Generation GenerationConfig
+	Difficulty DifficultyConfig
 }
 
 type AppConfig struct {
@@ -23,6 +29,19 @@ type AppConfig struct {
 	Port string
 	Host string
 	Name string
+	// AdminBootstrapEmail is promoted to admin at startup while no admin exists
+	AdminBootstrapEmail string
+	// PublicURL is the base for links sent by email (verification, password reset)
+	PublicURL string
+	// RequireVerifiedEmail keeps unverified accounts from submitting challenges,
+	// and so off the leaderboards
+	RequireVerifiedEmail bool
+	// ProxyHeader names the header a reverse proxy puts the client IP in
+	// (e.g. X-Forwarded-For, Fly-Client-IP); empty uses the connection's address
+	ProxyHeader string
+	// TrustedProxies are the proxy IPs or CIDR ranges ProxyHeader is read
+	// from. Required with ProxyHeader, since any client can set the header.
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -50,10 +69,75 @@ type JWTConfig struct {
 }
 
 type AIConfig struct {
-	AnthropicAPIKey string
-	AnthropicModel  string
-	OpenAIAPIKey    string
-	OpenAIModel     string
+	// Providers lists "anthropic", "openai" and "fake" in failover order
+	Providers        []string
+	AnthropicAPIKey  string
+	AnthropicModel   string
+	OpenAIAPIKey     string
+	OpenAIModel      string
+	OpenAIBaseURL    string        // any OpenAI-compatible chat completions API
+	FakeFixtures     string        // fixture file for the fake provider; built-in fixtures when empty
+	FailoverCooldown time.Duration // how long a failed provider is skipped
+	LegalRules       string        // legal rule file; built-in rules when empty
+	LegalRulesReload time.Duration // how often the legal rule file is checked for changes
+	// Pricing overrides the built-in model prices used to estimate generation
+	// cost, as "model=input/output" in dollars per million tokens
+	Pricing []string
+}
+
+type ChallengeConfig struct {
+	// SessionGracePeriod is added to a challenge's time limit to absorb network latency
+	SessionGracePeriod time.Duration
+	// SessionTokenTTL is how long a started challenge's session token stays valid
+	SessionTokenTTL time.Duration
+	// AllowMissingSessionToken accepts submissions without a session token,
+	// untimed unless the challenge was started, while older app versions
+	// are still in use
+	AllowMissingSessionToken bool
+	// ReportThreshold open reports suspend a challenge; 0 disables suspension
+	ReportThreshold int
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	Store   string // "memory" or "redis"
+
+	Auth            RateLimitRule // all /auth endpoints, per IP
+	Login           RateLimitRule // POST /auth/login, per IP
+	ChallengeFetch  RateLimitRule // GET /challenges, per user
+	ChallengeSubmit RateLimitRule // POST /challenges/:id/attempt, per user
+	AdminGenerate   RateLimitRule // admin AI generation, per user
+}
+
+type MailConfig struct {
+	Driver       string // "log" or "smtp"
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	OutputDir    string // log driver only: also write messages here as .eml files
+}
+
+type GenerationConfig struct {
+	Workers      int           // AI generation jobs run concurrently per process
+	PollInterval time.Duration // how often idle workers check the queue
+	MaxAttempts  int           // attempts before a job is marked failed
+	RetryBackoff time.Duration // delay before the first retry; doubles each attempt
+	StaleAfter   time.Duration // running jobs not updated for this long are requeued
+}
+
+type DifficultyConfig struct {
+	Interval    time.Duration // how often challenge difficulty is recalibrated; 0 disables
+	MinAttempts int           // scored attempts needed before a challenge is assessed
+	Tolerance   float64       // tiers observed difficulty may differ from the tier before it is flagged
+	AutoRetier  bool          // move flagged challenges to their observed tier
+}
+
+// RateLimitRule allows Requests per Window, written as "requests/window" (e.g. "5/15m")
+type RateLimitRule struct {
+	Requests int
+	Window   time.Duration
 }
 
 // Load reads configuration from environment variables
@@ -63,10 +147,15 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		App: AppConfig{
-			Env:  getEnv("APP_ENV", "development"),
-			Port: getEnv("APP_PORT", "8080"),
-			Host: getEnv("APP_HOST", "0.0.0.0"),
-			Name: getEnv("APP_NAME", "Fanmania API"),
+			Env:                  getEnv("APP_ENV", "development"),
+			Port:                 getEnv("APP_PORT", "8080"),
+			Host:                 getEnv("APP_HOST", "0.0.0.0"),
+			Name:                 getEnv("APP_NAME", "Fanmania API"),
+			AdminBootstrapEmail:  getEnv("ADMIN_BOOTSTRAP_EMAIL", ""),
+			PublicURL:            getEnv("APP_PUBLIC_URL", "http://localhost:8080"),
+			RequireVerifiedEmail: getEnvAsBool("REQUIRE_VERIFIED_EMAIL", false),
+			ProxyHeader:          getEnv("APP_PROXY_HEADER", ""),
+			TrustedProxies:       getEnvAsList("APP_TRUSTED_PROXIES", nil),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -90,10 +179,54 @@ func Load() (*Config, error) {
 			RefreshTokenExpiry:  getEnvAsDuration("JWT_REFRESH_TOKEN_EXPIRY", 7*24*time.Hour),
 		},
 		AI: AIConfig{
-			AnthropicAPIKey: getEnv("ANTHROPIC_API_KEY", ""),
-			AnthropicModel:  getEnv("ANTHROPIC_MODEL", "claude-sonnet-4-20250514"),
-			OpenAIAPIKey:    getEnv("OPENAI_API_KEY", ""),
-			OpenAIModel:     getEnv("OPENAI_MODEL", "gpt-4o-mini"),
+			Providers:        getEnvAsList("AI_PROVIDERS", []string{"anthropic"}),
+			AnthropicAPIKey:  getEnv("ANTHROPIC_API_KEY", ""),
+			AnthropicModel:   getEnv("ANTHROPIC_MODEL", "claude-sonnet-4-20250514"),
+			OpenAIAPIKey:     getEnv("OPENAI_API_KEY", ""),
+			OpenAIModel:      getEnv("OPENAI_MODEL", "gpt-4o-mini"),
+			OpenAIBaseURL:    getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+			FakeFixtures:     getEnv("AI_FAKE_FIXTURES", ""),
+			FailoverCooldown: getEnvAsDuration("AI_FAILOVER_COOLDOWN", time.Minute),
+			LegalRules:       getEnv("AI_LEGAL_RULES", ""),
+			LegalRulesReload: getEnvAsDuration("AI_LEGAL_RULES_RELOAD", 30*time.Second),
+			Pricing:          getEnvAsList("AI_PRICING", nil),
+		},
+		Challenge: ChallengeConfig{
+			SessionGracePeriod:       getEnvAsDuration("CHALLENGE_SESSION_GRACE_PERIOD", 5*time.Second),
+			SessionTokenTTL:          getEnvAsDuration("CHALLENGE_SESSION_TOKEN_TTL", 24*time.Hour),
+			AllowMissingSessionToken: getEnvAsBool("CHALLENGE_ALLOW_MISSING_SESSION_TOKEN", false),
+			ReportThreshold:          getEnvAsInt("CHALLENGE_REPORT_THRESHOLD", 5),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:         getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			Store:           getEnv("RATE_LIMIT_STORE", "memory"),
+			Auth:            getEnvAsRule("RATE_LIMIT_AUTH", RateLimitRule{30, 15 * time.Minute}),
+			Login:           getEnvAsRule("RATE_LIMIT_LOGIN", RateLimitRule{5, 15 * time.Minute}),
+			ChallengeFetch:  getEnvAsRule("RATE_LIMIT_CHALLENGE_FETCH", RateLimitRule{60, time.Minute}),
+			ChallengeSubmit: getEnvAsRule("RATE_LIMIT_CHALLENGE_SUBMIT", RateLimitRule{30, time.Hour}),
+			AdminGenerate:   getEnvAsRule("RATE_LIMIT_ADMIN_GENERATE", RateLimitRule{20, time.Hour}),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "Fanmania <no-reply@fanmania.local>"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			OutputDir:    getEnv("MAIL_OUTPUT_DIR", ""),
+		},
+		Generation: GenerationConfig{
+			Workers:      getEnvAsInt("GENERATION_WORKERS", 2),
+			PollInterval: getEnvAsDuration("GENERATION_POLL_INTERVAL", 5*time.Second),
+			MaxAttempts:  getEnvAsInt("GENERATION_MAX_ATTEMPTS", 5),
+			RetryBackoff: getEnvAsDuration("GENERATION_RETRY_BACKOFF", 30*time.Second),
+			StaleAfter:   getEnvAsDuration("GENERATION_STALE_AFTER", 10*time.Minute),
+		},
+		Difficulty: DifficultyConfig{
+			Interval:    getEnvAsDuration("DIFFICULTY_CALIBRATION_INTERVAL", time.Hour),
+			MinAttempts: getEnvAsInt("DIFFICULTY_MIN_ATTEMPTS", 30),
+			Tolerance:   getEnvAsFloat("DIFFICULTY_TOLERANCE", 1),
+			AutoRetier:  getEnvAsBool("DIFFICULTY_AUTO_RETIER", false),
 		},
 	}
 
@@ -102,6 +235,37 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("JWT_SECRET is required")
 	}
 
+	if cfg.App.ProxyHeader != "" && len(cfg.App.TrustedProxies) == 0 {
+		return nil, fmt.Errorf("APP_TRUSTED_PROXIES is required when APP_PROXY_HEADER is set")
+	}
+
+	if cfg.RateLimit.Store != "memory" && cfg.RateLimit.Store != "redis" {
+		return nil, fmt.Errorf("RATE_LIMIT_STORE must be memory or redis")
+	}
+
+	for _, provider := range cfg.AI.Providers {
+		switch provider {
+		case "anthropic", "openai", "fake":
+		default:
+			return nil, fmt.Errorf("AI_PROVIDERS entries must be anthropic, openai or fake, got %q", provider)
+		}
+	}
+
+	switch cfg.Mail.Driver {
+	case "log":
+		// The log driver prints reset and verification links, tokens
+		// included, so it must never run in production
+		if cfg.App.Env == "production" {
+			return nil, fmt.Errorf("MAIL_DRIVER=log is not allowed when APP_ENV=production")
+		}
+	case "smtp":
+		if cfg.Mail.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required when MAIL_DRIVER=smtp")
+		}
+	default:
+		return nil, fmt.Errorf("MAIL_DRIVER must be log or smtp")
+	}
+
 	return cfg, nil
 }
 
@@ -121,6 +285,14 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	valueStr := getEnv(key, "")
 	if value, err := time.ParseDuration(valueStr); err == nil {
@@ -129,6 +301,44 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsList(key string, defaultValue []string) []string {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	values := []string{}
+	for _, value := range strings.Split(valueStr, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvAsRule(key string, defaultValue RateLimitRule) RateLimitRule {
+	requestsStr, windowStr, ok := strings.Cut(getEnv(key, ""), "/")
+	if !ok {
+		return defaultValue
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(requestsStr))
+	if err != nil || requests < 1 {
+		return defaultValue
+	}
+	window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+	if err != nil || window <= 0 {
+		return defaultValue
+	}
+	return RateLimitRule{Requests: requests, Window: window}
+}
+
 // GetDatabaseURL returns the full PostgreSQL connection URL
 func (c *Config) GetDatabaseURL() string {
 	if c.Database.URL != "" {
@@ -32,6 +32,12 @@ var (
 	ErrInvalidToken     = NewAppError("AUTH_002", "Invalid or expired token", http.StatusUnauthorized)
 	ErrInvalidCredentials = NewAppError("AUTH_003", "Invalid credentials", http.StatusUnauthorized)
 	ErrUserNotFound     = NewAppError("AUTH_004", "User not found", http.StatusNotFound)
+	ErrForbidden        = NewAppError("AUTH_005", "Insufficient permissions", http.StatusForbidden)
+	ErrInvalidRole      = NewAppError("AUTH_006", "Invalid role", http.StatusBadRequest)
+	ErrAccountDisabled  = NewAppError("AUTH_007", "Account is disabled", http.StatusForbidden)
+	ErrTokenReused      = NewAppError("AUTH_008", "Refresh token reuse detected; please log in again", http.StatusUnauthorized)
+	ErrInvalidAccountToken = NewAppError("AUTH_009", "Invalid or expired link", http.StatusBadRequest)
+	ErrEmailNotVerified = NewAppError("AUTH_010", "Email address not verified", http.StatusForbidden)
 	
 	// Registration errors
 	ErrUsernameExists   = NewAppError("REG_001", "Username already exists", http.StatusConflict)
@@ -42,10 +48,32 @@ var (
 	ErrChallengeNotFound = NewAppError("CHAL_001", "Challenge not found", http.StatusNotFound)
 	ErrAlreadyAttempted  = NewAppError("CHAL_002", "Challenge already attempted", http.StatusConflict)
 	ErrChallengeExpired  = NewAppError("CHAL_003", "Challenge has expired", http.StatusGone)
+	ErrTimeLimitExceeded = NewAppError("CHAL_004", "Time limit exceeded", http.StatusUnprocessableEntity)
+	ErrInvalidSession    = NewAppError("CHAL_005", "Invalid or missing challenge session", http.StatusBadRequest)
+	ErrInvalidAnswer     = NewAppError("CHAL_006", "Answer does not match the question format", http.StatusBadRequest)
+	ErrChallengeNotOpen  = NewAppError("CHAL_007", "Challenge is not open yet", http.StatusForbidden)
+	ErrNotPrediction     = NewAppError("CHAL_008", "Challenge is not a prediction", http.StatusBadRequest)
+	ErrAlreadyResolved   = NewAppError("CHAL_009", "Prediction has already been resolved", http.StatusConflict)
+	ErrInvalidPredictionWindow = NewAppError("CHAL_010", "Prediction must close in the future and after it opens", http.StatusBadRequest)
+	ErrRevisionNotFound  = NewAppError("CHAL_011", "Challenge revision not found", http.StatusNotFound)
+	ErrContentRejected   = NewAppError("CHAL_012", "Challenge failed content validation", http.StatusUnprocessableEntity)
+	ErrInvalidSchedule   = NewAppError("CHAL_013", "active_until must be after active_from", http.StatusBadRequest)
+	ErrTypeChange        = NewAppError("CHAL_014", "A challenge's type cannot be changed", http.StatusBadRequest)
+	ErrNotPendingReview  = NewAppError("CHAL_015", "Challenge is not awaiting review", http.StatusConflict)
+	ErrReasonRequired    = NewAppError("CHAL_016", "A reason is required to reject a challenge", http.StatusBadRequest)
+	ErrAlreadyReported   = NewAppError("CHAL_017", "You have already reported this challenge", http.StatusConflict)
+	ErrNoOpenReports     = NewAppError("CHAL_018", "Challenge has no open reports", http.StatusConflict)
+	ErrCannotRegrade     = NewAppError("CHAL_019", "Attempts at this challenge cannot be regraded against a new answer", http.StatusBadRequest)
 	
 	// Category errors
 	ErrCategoryNotFound = NewAppError("CAT_001", "Category not found", http.StatusNotFound)
 	
+	// Leaderboard errors
+	ErrNotOnLeaderboard = NewAppError("LEAD_001", "You are not on this leaderboard yet", http.StatusNotFound)
+	
+	// Generation job errors
+	ErrJobNotFound = NewAppError("JOB_001", "Generation job not found", http.StatusNotFound)
+	
 	// Rate limiting errors
 	ErrRateLimitExceeded = NewAppError("RATE_001", "Rate limit exceeded", http.StatusTooManyRequests)
 	
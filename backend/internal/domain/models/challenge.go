@@ -38,28 +38,69 @@ type Challenge struct {
 	Description       *string         `json:"description,omitempty" db:"description"`
 	QuestionData      json.RawMessage `json:"question_data" db:"question_data"`
 	CorrectAnswerHash string          `json:"-" db:"correct_answer_hash"` // Never expose
+	Explanation       *string         `json:"explanation,omitempty" db:"explanation"`
+	Sources           []string        `json:"sources,omitempty" db:"sources"`
 	DifficultyTier    int             `json:"difficulty_tier" db:"difficulty_tier"`
 	BasePoints        int             `json:"base_points" db:"base_points"`
 	TimeLimitSeconds  *int            `json:"time_limit_seconds,omitempty" db:"time_limit_seconds"`
 	ChallengeType     string          `json:"challenge_type" db:"challenge_type"`
 	AIGenerated       bool            `json:"ai_generated" db:"ai_generated"`
 	IsActive          bool            `json:"is_active" db:"is_active"`
+	ActiveFrom        *time.Time      `json:"active_from,omitempty" db:"active_from"`
 	ActiveUntil       *time.Time      `json:"active_until,omitempty" db:"active_until"`
+	ResolvedAt        *time.Time      `json:"resolved_at,omitempty" db:"resolved_at"` // predictions only
 	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
 	UsageCount        int             `json:"-" db:"usage_count"`
+	Revision          int             `json:"revision" db:"revision"` // bumped on every edit
+	EditedBy          *uuid.UUID      `json:"edited_by,omitempty" db:"edited_by"`
+	ReviewStatus      string          `json:"review_status" db:"review_status"`
+	ReviewFlags       []string        `json:"review_flags,omitempty" db:"review_flags"` // why the challenge was held for review
+	ReviewedBy        *uuid.UUID      `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt        *time.Time      `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewReason      *string         `json:"review_reason,omitempty" db:"review_reason"`
+	SuspendedAt       *time.Time      `json:"suspended_at,omitempty" db:"suspended_at"` // taken out of play by reports
+	Difficulty        *ChallengeDifficulty `json:"difficulty,omitempty" db:"-"` // observed from attempts; nil until assessed
+	Rating            float64         `json:"rating" db:"rating"`                   // moves with every attempt
+	RatingAttempts    int             `json:"rating_attempts" db:"rating_attempts"` // attempts that have moved Rating
 }
 
+// Review statuses. Only approved challenges are served to players.
+const (
+	ReviewPending  = "pending_review"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
 // QuestionData represents the structure of challenge questions
 type QuestionData struct {
 	Type     string          `json:"type"` // multiple_choice, timeline, prediction, true_false
 	Question string          `json:"question"`
 	Options  []QuestionOption `json:"options,omitempty"`
+	Events   []TimelineEvent  `json:"events,omitempty"` // timeline only
+	Pattern  *PatternData     `json:"pattern,omitempty"` // pattern only
 }
 
-// QuestionOption represents a single option in a challenge
-type QuestionOption struct {
+// PatternData describes a pattern-recognition question. For a
+// missing_element pattern the options are candidates for the "?" in
+// Sequence; for odd_one_out the options are the items themselves.
+type PatternData struct {
+	Kind     string   `json:"kind"` // missing_element, odd_one_out
+	Sequence []string `json:"sequence,omitempty"`
+}
+
+// TimelineEvent is an event to be put in chronological order
+type TimelineEvent struct {
 	ID   string `json:"id"`
 	Text string `json:"text"`
+	// Position is the event's 1-based place in the correct order.
+	// It is cleared before the question is served.
+	Position int `json:"position,omitempty"`
+}
+
+// QuestionOption represents a single option in a challenge
+type QuestionOption struct {
+	ID   string `json:"id" validate:"required"`
+	Text string `json:"text" validate:"required"`
 }
 
 // ChallengeAttempt represents a user's attempt at a challenge
@@ -68,28 +109,182 @@ type ChallengeAttempt struct {
 	UserID           uuid.UUID  `json:"user_id" db:"user_id"`
 	ChallengeID      uuid.UUID  `json:"challenge_id" db:"challenge_id"`
 	IsCorrect        bool       `json:"is_correct" db:"is_correct"`
+	Score            float64    `json:"score" db:"score"` // 0 to 1; fractional for partial credit
+	Pending          bool       `json:"pending" db:"pending"` // prediction awaiting resolution
+	Voided           bool       `json:"voided" db:"voided"`   // the challenge was voided; the attempt no longer counts
+	ChallengeRevision int       `json:"challenge_revision" db:"challenge_revision"` // revision graded against
 	PointsEarned     int        `json:"points_earned" db:"points_earned"`
 	TimeTakenSeconds *int       `json:"time_taken_seconds,omitempty" db:"time_taken_seconds"`
 	AnswerHash       *string    `json:"-" db:"answer_hash"` // Hashed answer for security
 	AttemptedAt      time.Time  `json:"attempted_at" db:"attempted_at"`
 }
 
+// ChallengeRevision is a snapshot of a challenge as of one of its edits
+type ChallengeRevision struct {
+	ID                uuid.UUID       `json:"id" db:"id"`
+	ChallengeID       uuid.UUID       `json:"challenge_id" db:"challenge_id"`
+	Revision          int             `json:"revision" db:"revision"`
+	CategoryID        uuid.UUID       `json:"category_id" db:"category_id"`
+	Title             string          `json:"title" db:"title"`
+	Description       *string         `json:"description,omitempty" db:"description"`
+	QuestionData      json.RawMessage `json:"question_data" db:"question_data"`
+	CorrectAnswerHash string          `json:"correct_answer_hash" db:"correct_answer_hash"` // admin only; compare with attempt hashes
+	Explanation       *string         `json:"explanation,omitempty" db:"explanation"`
+	Sources           []string        `json:"sources,omitempty" db:"sources"`
+	DifficultyTier    int             `json:"difficulty_tier" db:"difficulty_tier"`
+	BasePoints        int             `json:"base_points" db:"base_points"`
+	TimeLimitSeconds  *int            `json:"time_limit_seconds,omitempty" db:"time_limit_seconds"`
+	ChallengeType     string          `json:"challenge_type" db:"challenge_type"`
+	IsActive          bool            `json:"is_active" db:"is_active"`
+	ActiveFrom        *time.Time      `json:"active_from,omitempty" db:"active_from"`
+	ActiveUntil       *time.Time      `json:"active_until,omitempty" db:"active_until"`
+	EditedBy          *uuid.UUID      `json:"edited_by,omitempty" db:"edited_by"`
+	ReviewStatus      string          `json:"review_status" db:"review_status"`
+	ReviewFlags       []string        `json:"review_flags,omitempty" db:"review_flags"` // why the challenge was held for review
+	ReviewedBy        *uuid.UUID      `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt        *time.Time      `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewReason      *string         `json:"review_reason,omitempty" db:"review_reason"`
+	SuspendedAt       *time.Time      `json:"suspended_at,omitempty" db:"suspended_at"` // taken out of play by reports
+	Difficulty        *ChallengeDifficulty `json:"difficulty,omitempty" db:"-"` // observed from attempts; nil until assessed
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
+}
+
+// ChallengeSession records when a user opened a challenge.
+// Elapsed time for an attempt is measured from StartedAt.
+type ChallengeSession struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	ChallengeID uuid.UUID  `json:"challenge_id" db:"challenge_id"`
+	StartedAt   time.Time  `json:"started_at" db:"started_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"` // NULL for untimed challenges
+}
+
+// StartChallengeResponse is returned when a user opens a challenge. The
+// session token is sent back with the attempt.
+type StartChallengeResponse struct {
+	SessionToken string     `json:"session_token"`
+	StartedAt    time.Time  `json:"started_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // nil for untimed challenges
+}
+
 // SubmitChallengeRequest is the payload for submitting a challenge attempt
 type SubmitChallengeRequest struct {
-	ChallengeID      uuid.UUID `json:"challenge_id" validate:"required"`
-	SelectedAnswer   string    `json:"selected_answer" validate:"required"`
-	TimeTakenSeconds *int      `json:"time_taken_seconds,omitempty" validate:"omitempty,min=1"`
+	ChallengeID    uuid.UUID `json:"challenge_id" validate:"required"`
+	SelectedAnswer string    `json:"selected_answer" validate:"required_without=SelectedOrder"`
+	SelectedOrder  []string  `json:"selected_order,omitempty" validate:"omitempty,max=20,dive,required"` // timeline: event IDs, earliest first
+	SessionToken   string    `json:"session_token,omitempty"`                                            // optional while CHALLENGE_ALLOW_MISSING_SESSION_TOKEN is on
+}
+
+// ChallengeInput is the payload for authoring or editing a challenge by hand.
+// CorrectAnswer is an option ID, or for timelines every option ID from
+// earliest to latest. Predictions are opened with CreatePredictionRequest.
+type ChallengeInput struct {
+	CategoryID       uuid.UUID        `json:"category_id" validate:"required"`
+	Title            string           `json:"title" validate:"required,max=255"`
+	Description      *string          `json:"description,omitempty"`
+	ChallengeType    string           `json:"challenge_type" validate:"required,oneof=multiple_choice timeline true_false pattern"`
+	Question         string           `json:"question" validate:"required"`
+	Options          []QuestionOption `json:"options" validate:"required,min=2,max=20,dive"`
+	Pattern          *PatternData     `json:"pattern,omitempty"`
+	CorrectAnswer    string           `json:"correct_answer" validate:"required"`
+	Explanation      *string          `json:"explanation,omitempty" validate:"omitempty,max=2000"`
+	Sources          []string         `json:"sources,omitempty" validate:"omitempty,max=5,dive,required,max=500"`
+	DifficultyTier   int              `json:"difficulty_tier" validate:"required,min=1,max=5"`
+	BasePoints       int              `json:"base_points" validate:"omitempty,min=1,max=10000"`
+	TimeLimitSeconds *int             `json:"time_limit_seconds,omitempty" validate:"omitempty,min=5,max=3600"`
+	ActiveFrom       *time.Time       `json:"active_from,omitempty"`
+	ActiveUntil      *time.Time       `json:"active_until,omitempty"`
+	IsActive         *bool            `json:"is_active,omitempty"` // defaults to true on create
+}
+
+// ChallengeScheduleRequest sets when a challenge takes attempts. A nil
+// active_until leaves the challenge open indefinitely.
+type ChallengeScheduleRequest struct {
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+}
+
+// CloneChallengeRequest overrides fields of a cloned challenge. Clones
+// start inactive so they can be edited before they go live.
+type CloneChallengeRequest struct {
+	CategoryID     *uuid.UUID `json:"category_id,omitempty"`
+	Title          *string    `json:"title,omitempty" validate:"omitempty,max=255"`
+	DifficultyTier *int       `json:"difficulty_tier,omitempty" validate:"omitempty,min=1,max=5"`
+	ActiveFrom     *time.Time `json:"active_from,omitempty"`
+	ActiveUntil    *time.Time `json:"active_until,omitempty"`
+}
+
+// ChallengeFilter narrows an admin listing of challenges
+type ChallengeFilter struct {
+	CategoryID     *uuid.UUID
+	ChallengeType  string
+	IsActive       *bool
+	ReviewStatus   string
+	DifficultyFlag string
+	Limit          int
+	Offset         int
+}
+
+// ReviewDecisionRequest is the payload for approving or rejecting a
+// challenge held for review. A reason is required to reject.
+type ReviewDecisionRequest struct {
+	Reason string `json:"reason" validate:"max=1000"`
+}
+
+// ReviewBacklog summarizes the review queue
+type ReviewBacklog struct {
+	Pending           int            `json:"pending"`
+	PendingByCategory map[string]int `json:"pending_by_category"`
+	OldestPendingAt   *time.Time     `json:"oldest_pending_at,omitempty"`
+	ApprovedLast24h   int            `json:"approved_last_24h"`
+	RejectedLast24h   int            `json:"rejected_last_24h"`
+	AvgWaitSeconds    float64        `json:"avg_wait_seconds"` // created to reviewed, last 7 days
+}
+
+// CreatePredictionRequest is the payload for opening a prediction challenge
+type CreatePredictionRequest struct {
+	CategoryID     uuid.UUID        `json:"category_id" validate:"required"`
+	Title          string           `json:"title" validate:"required,max=255"`
+	Description    *string          `json:"description,omitempty"`
+	Question       string           `json:"question" validate:"required"`
+	Options        []QuestionOption `json:"options" validate:"required,min=2,max=10,dive"`
+	DifficultyTier int              `json:"difficulty_tier" validate:"required,min=1,max=5"`
+	BasePoints     int              `json:"base_points" validate:"omitempty,min=1"`
+	ActiveFrom     *time.Time       `json:"active_from,omitempty"`
+	ActiveUntil    time.Time        `json:"active_until" validate:"required"`
+}
+
+// ResolvePredictionRequest is the payload for resolving a prediction
+type ResolvePredictionRequest struct {
+	CorrectAnswer string `json:"correct_answer" validate:"required"`
+}
+
+// PredictionResolution summarizes the scoring of a resolved prediction
+type PredictionResolution struct {
+	ChallengeID   uuid.UUID `json:"challenge_id"`
+	CorrectAnswer string    `json:"correct_answer"`
+	ResolvedAt    time.Time `json:"resolved_at"`
+	Attempts      int       `json:"attempts"`
+	Correct       int       `json:"correct"`
+	PointsAwarded int       `json:"points_awarded"` // per correct pick
 }
 
 // ChallengeResult is returned after submitting a challenge
 type ChallengeResult struct {
-	IsCorrect       bool    `json:"is_correct"`
-	PointsEarned    int     `json:"points_earned"`
-	Explanation     *string `json:"explanation,omitempty"`
-	NewTotalPoints  int64   `json:"new_total_points"`
-	NewRank         *int    `json:"new_rank,omitempty"`
-	StreakUpdated   bool    `json:"streak_updated"`
-	StreakDays      int     `json:"streak_days"`
+	IsCorrect         bool         `json:"is_correct"`
+	Score             float64      `json:"score"`             // 0 to 1; below 1 with partial credit
+	Pending           bool         `json:"pending,omitempty"` // prediction; scored when resolved
+	PointsEarned      int          `json:"points_earned"`
+	Explanation       *string      `json:"explanation,omitempty"`        // feedback on a wrong or pending answer
+	CorrectAnswer     *string      `json:"correct_answer,omitempty"`     // correct option ID; not revealed for pending predictions
+	CorrectOrder      []string     `json:"correct_order,omitempty"`      // timelines: event IDs in chronological order
+	AnswerExplanation *string      `json:"answer_explanation,omitempty"` // why the answer is correct
+	Sources           []string     `json:"sources,omitempty"`
+	NewTotalPoints    int64        `json:"new_total_points"`
+	NewRank           *int         `json:"new_rank,omitempty"`
+	StreakUpdated     bool         `json:"streak_updated"`
+	StreakDays        int          `json:"streak_days"`
+	Skill             *SkillUpdate `json:"skill,omitempty"` // nil for predictions
 }
 
 // CategoryRanking represents a user's ranking in a category
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Difficulty flags, set when a challenge's observed difficulty disagrees
+// with its tier
+const (
+	DifficultyTooEasy = "too_easy"
+	DifficultyTooHard = "too_hard"
+)
+
+// ChallengeDifficulty is a challenge's difficulty as observed from player
+// attempts
+type ChallengeDifficulty struct {
+	Empirical       float64   `json:"empirical"`               // on the 1-5 tier scale
+	ObservedTier    int       `json:"observed_tier"`           // Empirical rounded to a tier
+	SolveRate       float64   `json:"solve_rate"`              // mean attempt score
+	MedianTimeRatio float64   `json:"median_time_ratio"`       // median share of the time limit used
+	SampleSize      int       `json:"sample_size"`             // attempts the estimate is based on
+	Flag            *string   `json:"flag,omitempty"`          // too_easy or too_hard
+	OriginalTier    *int      `json:"original_tier,omitempty"` // tier before it was first re-tiered
+	AssessedAt      time.Time `json:"assessed_at"`
+}
+
+// DifficultySample is what players did on one challenge, the input to a
+// difficulty estimate
+type DifficultySample struct {
+	ChallengeID     uuid.UUID
+	ChallengeType   string
+	DifficultyTier  int
+	OptionCount     int
+	Attempts        int
+	SolveRate       float64
+	MedianTimeRatio float64
+}
+
+// DifficultyAssessment is the estimated difficulty of one challenge
+type DifficultyAssessment struct {
+	ChallengeID     uuid.UUID
+	Empirical       float64
+	SolveRate       float64
+	MedianTimeRatio float64
+	SampleSize      int
+	Flag            *string
+}
+
+// CalibrationResult summarizes a difficulty calibration run
+type CalibrationResult struct {
+	Assessed int  `json:"assessed"`
+	Flagged  int  `json:"flagged"`
+	Retiered int  `json:"retiered"`
+	Skipped  bool `json:"skipped,omitempty"` // another process was already calibrating
+}
+
+// NearestTier rounds a difficulty on the tier scale to the nearest tier
+func NearestTier(difficulty float64) int {
+	tier := int(math.Round(difficulty))
+	if tier < 1 {
+		return 1
+	}
+	if tier > 5 {
+		return 5
+	}
+	return tier
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Generation job statuses
+const (
+	GenerationJobPending   = "pending"
+	GenerationJobRunning   = "running"
+	GenerationJobSucceeded = "succeeded"
+	GenerationJobFailed    = "failed"
+)
+
+// Generation job sources
+const (
+	GenerationSourceAdmin = "admin" // requested through the admin API
+	GenerationSourceAuto  = "auto"  // topping up an under-stocked challenge pool
+)
+
+// GenerationJob is a queued request to generate challenges with AI.
+// Progress is recorded as it goes, so a retried job resumes where it
+// stopped rather than starting over.
+type GenerationJob struct {
+	ID             uuid.UUID   `json:"id" db:"id"`
+	CategoryID     uuid.UUID   `json:"category_id" db:"category_id"`
+	DifficultyTier int         `json:"difficulty_tier" db:"difficulty_tier"`
+	ChallengeType  string      `json:"challenge_type" db:"challenge_type"`
+	RequestedCount int         `json:"requested_count" db:"requested_count"`
+	GeneratedCount int         `json:"generated_count" db:"generated_count"`
+	FailedCount    int         `json:"failed_count" db:"failed_count"`
+	ChallengeIDs   []uuid.UUID `json:"challenge_ids" db:"challenge_ids"`
+	Status         string      `json:"status" db:"status"`
+	Source         string      `json:"source" db:"source"`
+	RequestedBy    *uuid.UUID  `json:"requested_by,omitempty" db:"requested_by"`
+	Attempts       int         `json:"attempts" db:"attempts"`
+	MaxAttempts    int         `json:"max_attempts" db:"max_attempts"`
+	LastError      *string     `json:"last_error,omitempty" db:"last_error"`
+	RunAt          time.Time   `json:"run_at" db:"run_at"`
+	LockedAt       *time.Time  `json:"locked_at,omitempty" db:"locked_at"`
+	FinishedAt     *time.Time  `json:"finished_at,omitempty" db:"finished_at"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// Remaining is how many more challenges the job still has to produce
+func (j *GenerationJob) Remaining() int {
+	if j.GeneratedCount >= j.RequestedCount {
+		return 0
+	}
+	return j.RequestedCount - j.GeneratedCount
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of AI generation call
+const (
+	GenerationKindChallenge = "challenge"
+	GenerationKindCategory  = "category"
+)
+
+// Outcomes of an AI generation call
+const (
+	GenerationGenerated     = "generated"      // passed validation; returned as a preview without saving
+	GenerationSaved         = "saved"          // passed validation and was saved
+	GenerationProviderError = "provider_error" // the provider call failed
+	GenerationParseError    = "parse_error"    // the reply was not valid JSON
+	GenerationLegalRejected = "legal_rejected" // blocked by the legal validator
+	GenerationInvalid       = "invalid"        // the reply could not be turned into a challenge
+	GenerationDuplicate     = "duplicate"      // too similar to an existing challenge
+	GenerationSaveError     = "save_error"     // passed validation but could not be saved
+)
+
+// GenerationLogEntry records one AI provider call: what it cost and what
+// became of its output
+type GenerationLogEntry struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	Kind             string     `json:"kind" db:"kind"`
+	CategoryID       *uuid.UUID `json:"category_id,omitempty" db:"category_id"`
+	DifficultyTier   *int       `json:"difficulty_tier,omitempty" db:"difficulty_tier"`
+	ChallengeType    *string    `json:"challenge_type,omitempty" db:"challenge_type"`
+	Provider         string     `json:"provider" db:"provider"`
+	Model            *string    `json:"model,omitempty" db:"model"`
+	InputTokens      int        `json:"input_tokens" db:"input_tokens"`
+	OutputTokens     int        `json:"output_tokens" db:"output_tokens"`
+	EstimatedCostUSD *float64   `json:"estimated_cost_usd,omitempty" db:"estimated_cost_usd"` // nil when the model has no known price
+	Outcome          string     `json:"outcome" db:"outcome"`
+	FailureReasons   []string   `json:"failure_reasons,omitempty" db:"failure_reasons"`
+	Error            *string    `json:"error,omitempty" db:"error"`
+	ChallengeID      *uuid.UUID `json:"challenge_id,omitempty" db:"challenge_id"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+// GenerationStatsFilter narrows the generation statistics
+type GenerationStatsFilter struct {
+	CategoryID     *uuid.UUID
+	DifficultyTier *int
+	Since          *time.Time
+}
+
+// GenerationStats summarizes AI challenge generation
+type GenerationStats struct {
+	TotalGenerated int                  `json:"total_generated"` // AI challenges stored
+	TotalActive    int                  `json:"total_active"`
+	PendingReview  int                  `json:"pending_review"`
+	ByCategory     []CategoryGeneration `json:"by_category"`
+	ByDifficulty   map[int]int          `json:"by_difficulty"`
+	Validation     GenerationValidation `json:"validation"`
+	Duplicates     GenerationDuplicates `json:"duplicates"`
+	Usage          GeneratedUsage       `json:"usage"`
+	Providers      []ProviderUsage      `json:"providers"`
+	Cost           GenerationCost       `json:"cost"`
+	Outcomes       map[string]int       `json:"outcomes"`
+}
+
+// CategoryGeneration counts the AI challenges stored for one category and tier
+type CategoryGeneration struct {
+	CategoryID     uuid.UUID `json:"category_id"`
+	CategoryName   string    `json:"category_name"`
+	DifficultyTier int       `json:"difficulty_tier"`
+	Generated      int       `json:"generated"`
+	Active         int       `json:"active"`
+	PendingReview  int       `json:"pending_review"`
+}
+
+// GenerationValidation is how generated challenges fared in validation.
+// Provider errors are not counted; there was nothing to validate.
+type GenerationValidation struct {
+	Checked           int             `json:"checked"`
+	Passed            int             `json:"passed"`
+	Failed            int             `json:"failed"`
+	PassRate          float64         `json:"pass_rate"`
+	TopFailureReasons []FailureReason `json:"top_failure_reasons"`
+}
+
+// FailureReason counts generations that failed for one reason
+type FailureReason struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// GenerationDuplicates is how often valid challenges were rejected as
+// duplicates before saving
+type GenerationDuplicates struct {
+	Checked  int     `json:"checked"`
+	Rejected int     `json:"rejected"`
+	Rate     float64 `json:"rate"`
+}
+
+// GeneratedUsage is how players have used the stored AI challenges
+type GeneratedUsage struct {
+	TotalUsage     int     `json:"total_usage"`
+	AverageUsage   float64 `json:"average_usage"`
+	CorrectCount   int     `json:"correct_count"`
+	IncorrectCount int     `json:"incorrect_count"`
+	CorrectRatio   float64 `json:"correct_ratio"`
+}
+
+// ProviderUsage totals the calls made to one provider model
+type ProviderUsage struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Calls            int     `json:"calls"`
+	FailedCalls      int     `json:"failed_calls"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	UnpricedCalls    int     `json:"unpriced_calls"` // calls to a model with no known price, left out of the cost
+}
+
+// GenerationCost totals the estimated spend on generation. It covers every
+// provider call, including failed generations and category generation.
+type GenerationCost struct {
+	EstimatedUSD         float64  `json:"estimated_usd"`
+	PerSavedChallengeUSD *float64 `json:"per_saved_challenge_usd,omitempty"`
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,15 +18,66 @@ type LeaderboardEntry struct {
 	AvatarURL         *string    `json:"avatar_url,omitempty"`
 	Points            int64      `json:"points"`
 	MasteryPercentage *float64   `json:"mastery_percentage,omitempty"`
+	Rating            *float64   `json:"rating,omitempty"` // rating leaderboards only
+	TiedAt            time.Time  `json:"-"`                // orders users tied on points
+}
+
+// Cursor returns the cursor of the page ending with this entry
+func (e *LeaderboardEntry) Cursor() LeaderboardCursor {
+	cursor := LeaderboardCursor{Points: e.Points, TiedAt: e.TiedAt, UserID: e.UserID}
+	if e.Rating != nil {
+		cursor.Rating = *e.Rating
+	}
+	return cursor
+}
+
+// LeaderboardCursor marks where a page of a leaderboard ended by the
+// entry's place in the board's order: points, then who got there first,
+// then user ID on points boards; rating, then user ID on rating boards.
+// The next page starts after it however ranks have moved in between.
+type LeaderboardCursor struct {
+	Points int64     `json:"p"`
+	Rating float64   `json:"r,omitempty"`
+	TiedAt time.Time `json:"t"`
+	UserID uuid.UUID `json:"u"`
+}
+
+// String encodes the cursor for a client to send back
+func (c LeaderboardCursor) String() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseLeaderboardCursor decodes a cursor made by LeaderboardCursor.String
+func ParseLeaderboardCursor(s string) (*LeaderboardCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var cursor LeaderboardCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return &cursor, nil
 }
 
 // LeaderboardResponse represents a leaderboard with metadata
 type LeaderboardResponse struct {
+	Mode       string              `json:"mode"`  // points, rating
 	Scope      string              `json:"scope"` // daily, weekly, monthly, all_time
 	CategoryID *uuid.UUID          `json:"category_id,omitempty"`
 	Entries    []LeaderboardEntry  `json:"entries"`
-	UserRank   *int                `json:"user_rank,omitempty"`
+	UserRank   *int                `json:"user_rank,omitempty"`   // the caller's, if signed in and on the board
+	UserPoints *int64              `json:"user_points,omitempty"` // the caller's, as counted by the board
 	TotalUsers int                 `json:"total_users"`
+	NextCursor *string             `json:"next_cursor,omitempty"` // pass as cursor for the next page
+}
+
+// LeaderboardQuery identifies a leaderboard
+type LeaderboardQuery struct {
+	CategoryID *uuid.UUID // nil for the global leaderboard
+	Mode       string     // points, rating
+	Scope      string     // daily, weekly, monthly, all_time; rating leaderboards are all_time
 }
 
 // LeaderboardSnapshot represents a saved leaderboard state
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLeaderboardCursorRoundTrip(t *testing.T) {
+	userID := uuid.MustParse("7d3f8a52-1c4e-4b8e-9a61-2f0c5d9e7b14")
+	tiedAt := time.Date(2026, 5, 17, 9, 30, 15, 123456789, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor LeaderboardCursor
+	}{
+		{name: "points board", cursor: LeaderboardCursor{Points: 12500, TiedAt: tiedAt, UserID: userID}},
+		{name: "rating board", cursor: LeaderboardCursor{Points: 300, Rating: 1642.375, TiedAt: tiedAt, UserID: userID}},
+		{name: "negative points", cursor: LeaderboardCursor{Points: -60, TiedAt: tiedAt, UserID: userID}},
+		{name: "other time zone", cursor: LeaderboardCursor{Points: 1, TiedAt: tiedAt.In(time.FixedZone("UTC+5", 5*3600)), UserID: userID}},
+		{name: "zero", cursor: LeaderboardCursor{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.cursor.String()
+			for _, r := range encoded {
+				if r == '+' || r == '/' || r == '=' {
+					t.Fatalf("cursor %q is not URL safe", encoded)
+				}
+			}
+
+			got, err := ParseLeaderboardCursor(encoded)
+			if err != nil {
+				t.Fatalf("ParseLeaderboardCursor: %v", err)
+			}
+			if got.Points != tt.cursor.Points || got.Rating != tt.cursor.Rating || got.UserID != tt.cursor.UserID {
+				t.Errorf("cursor = %+v, want %+v", *got, tt.cursor)
+			}
+			if !got.TiedAt.Equal(tt.cursor.TiedAt) {
+				t.Errorf("TiedAt = %v, want %v", got.TiedAt, tt.cursor.TiedAt)
+			}
+		})
+	}
+}
+
+func TestParseLeaderboardCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "not base64", input: "not a cursor!"},
+		{name: "padded base64", input: base64.URLEncoding.EncodeToString([]byte(`{"p":1}`)) + "="},
+		{name: "not JSON", input: base64.RawURLEncoding.EncodeToString([]byte("points=1"))},
+		{name: "wrong field type", input: base64.RawURLEncoding.EncodeToString([]byte(`{"p":"lots"}`))},
+		{name: "bad user ID", input: base64.RawURLEncoding.EncodeToString([]byte(`{"p":1,"u":"someone"}`))},
+		{name: "bad time", input: base64.RawURLEncoding.EncodeToString([]byte(`{"p":1,"t":"yesterday"}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if cursor, err := ParseLeaderboardCursor(tt.input); err == nil {
+				t.Errorf("ParseLeaderboardCursor(%q) = %+v, want an error", tt.input, *cursor)
+			}
+		})
+	}
+}
+
+func TestLeaderboardEntryCursor(t *testing.T) {
+	userID := uuid.New()
+	tiedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rating := 1550.5
+
+	tests := []struct {
+		name  string
+		entry LeaderboardEntry
+		want  LeaderboardCursor
+	}{
+		{
+			name:  "points entry",
+			entry: LeaderboardEntry{Rank: 4, UserID: userID, Points: 900, TiedAt: tiedAt},
+			want:  LeaderboardCursor{Points: 900, TiedAt: tiedAt, UserID: userID},
+		},
+		{
+			name:  "rating entry",
+			entry: LeaderboardEntry{Rank: 2, UserID: userID, Points: 900, Rating: &rating, TiedAt: tiedAt},
+			want:  LeaderboardCursor{Points: 900, Rating: rating, TiedAt: tiedAt, UserID: userID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.Cursor(); got != tt.want {
+				t.Errorf("Cursor = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report reasons
+const (
+	ReportWrongAnswer = "wrong_answer"
+	ReportOffensive   = "offensive"
+	ReportAmbiguous   = "ambiguous"
+	ReportDuplicate   = "duplicate"
+)
+
+// Report statuses
+const (
+	ReportOpen      = "open"
+	ReportUpheld    = "upheld"
+	ReportDismissed = "dismissed"
+)
+
+// Outcomes of resolving a challenge's reports
+const (
+	ReportOutcomeDismissed = "dismissed" // the challenge stands; a suspended challenge goes back into play
+	ReportOutcomeCorrected = "corrected" // the answer was wrong; attempts are regraded against the new one
+	ReportOutcomeVoided    = "voided"    // the challenge is withdrawn; its points are taken back
+)
+
+// ChallengeReport is a player's complaint about a challenge
+type ChallengeReport struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	ChallengeID     uuid.UUID  `json:"challenge_id" db:"challenge_id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	Reason          string     `json:"reason" db:"reason"`
+	Details         *string    `json:"details,omitempty" db:"details"`
+	Status          string     `json:"status" db:"status"`
+	ResolvedBy      *uuid.UUID `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolutionNotes *string    `json:"resolution_notes,omitempty" db:"resolution_notes"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ReportChallengeRequest is the payload for reporting a challenge
+type ReportChallengeRequest struct {
+	Reason  string  `json:"reason" validate:"required,oneof=wrong_answer offensive ambiguous duplicate"`
+	Details *string `json:"details,omitempty" validate:"omitempty,max=1000"`
+}
+
+// ReportSummary aggregates the reports against one challenge
+type ReportSummary struct {
+	ChallengeID     uuid.UUID      `json:"challenge_id"`
+	Title           string         `json:"title"`
+	ChallengeType   string         `json:"challenge_type"`
+	IsActive        bool           `json:"is_active"`
+	SuspendedAt     *time.Time     `json:"suspended_at,omitempty"`
+	Total           int            `json:"total"`
+	ByReason        map[string]int `json:"by_reason"`
+	FirstReportedAt time.Time      `json:"first_reported_at"`
+	LastReportedAt  time.Time      `json:"last_reported_at"`
+}
+
+// ResolveReportsRequest is the payload for resolving a challenge's open
+// reports. CorrectAnswer is required when the outcome is corrected.
+type ResolveReportsRequest struct {
+	Outcome       string `json:"outcome" validate:"required,oneof=dismissed corrected voided"`
+	CorrectAnswer string `json:"correct_answer,omitempty"`
+	Notes         string `json:"notes,omitempty" validate:"max=1000"`
+}
+
+// ReportResolution is the result of resolving a challenge's reports
+type ReportResolution struct {
+	ChallengeID     uuid.UUID `json:"challenge_id"`
+	Outcome         string    `json:"outcome"`
+	ReportsResolved int       `json:"reports_resolved"`
+	Regrade         *Regrade  `json:"regrade,omitempty"`
+}
+
+// AttemptRegrade is the change to one attempt when a challenge is regraded
+type AttemptRegrade struct {
+	AttemptID     uuid.UUID
+	UserID        uuid.UUID
+	Ranked        bool // the attempt had counted towards rankings
+	PointsBefore  int
+	PointsAfter   int
+	CorrectBefore bool
+	CorrectAfter  bool
+	ScoreAfter    float64
+	Voided        bool
+	AttemptedAt   time.Time // changes in points count towards the day of the attempt
+}
+
+// Regrade summarizes the effect of regrading a challenge's attempts
+type Regrade struct {
+	Attempts    int `json:"attempts"`
+	Changed     int `json:"changed"`
+	PointsDelta int `json:"points_delta"` // net change to points across all users
+}
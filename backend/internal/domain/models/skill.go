@@ -0,0 +1,109 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ratings share a scale with challenge difficulty: tier 1 sits at
+// TierOneRating and each tier above it adds TierRatingStep, so a user
+// rated at a challenge's difficulty is expected to answer it correctly
+// half the time
+const (
+	TierOneRating         = 1000.0
+	TierRatingStep        = 200.0
+	SkillInitialRating    = 1100.0 // new players start just above tier 1
+	SkillInitialDeviation = 350.0  // and with their rating wholly uncertain
+)
+
+// Ratings resting on fewer attempts than these are provisional.
+// Provisional users are left off rating leaderboards and do not move
+// challenge ratings; provisional challenges move faster.
+const (
+	SkillProvisionalAttempts     = 10
+	ChallengeProvisionalAttempts = 20
+)
+
+// SkillRating is a Glicko style rating and the attempts it rests on
+type SkillRating struct {
+	Rating    float64   `json:"rating" db:"rating"`
+	Deviation float64   `json:"rating_deviation" db:"rating_deviation"` // uncertainty in Rating
+	Attempts  int       `json:"attempts" db:"attempts"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NewSkillRating is the rating of a user who has not played yet
+func NewSkillRating() SkillRating {
+	return SkillRating{
+		Rating:    SkillInitialRating,
+		Deviation: SkillInitialDeviation,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Provisional reports whether the rating rests on too few attempts to
+// be ranked
+func (r *SkillRating) Provisional() bool {
+	return r.Attempts < SkillProvisionalAttempts
+}
+
+// UserSkill is a user's skill rating in one category
+type UserSkill struct {
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	CategoryID uuid.UUID `json:"category_id" db:"category_id"`
+	SkillRating
+	UnlockedTier int `json:"unlocked_tier" db:"unlocked_tier"`
+}
+
+// NewUserSkill is the skill of a user who has not played a category yet
+func NewUserSkill(userID, categoryID uuid.UUID) *UserSkill {
+	return &UserSkill{
+		UserID:       userID,
+		CategoryID:   categoryID,
+		SkillRating:  NewSkillRating(),
+		UnlockedTier: 1,
+	}
+}
+
+// SkillUpdate is how an attempt changed the user's ratings
+type SkillUpdate struct {
+	CategoryID   uuid.UUID `json:"category_id"`
+	Rating       float64   `json:"rating"` // in the category
+	RatingChange float64   `json:"rating_change"`
+	GlobalRating float64   `json:"global_rating"`
+	Provisional  bool      `json:"provisional,omitempty"`   // too few attempts in the category to be ranked
+	Tier         int       `json:"tier"`                    // tier the user will be served next
+	UnlockedTier *int      `json:"unlocked_tier,omitempty"` // set when the attempt unlocked a new tier
+}
+
+// RatedAttempt is a scored attempt as replayed when ratings are
+// recalculated
+type RatedAttempt struct {
+	UserID         uuid.UUID
+	ChallengeID    uuid.UUID
+	CategoryID     uuid.UUID
+	DifficultyTier int // the challenge's tier when the attempt was graded
+	Score          float64
+	AttemptedAt    time.Time
+}
+
+// RatingRecalculation summarizes a recalculation of every rating
+type RatingRecalculation struct {
+	Attempts   int `json:"attempts"`   // attempts replayed
+	Users      int `json:"users"`      // users with a rating
+	Challenges int `json:"challenges"` // challenges with a rating moved by attempts
+}
+
+// DifficultyRating places a difficulty on the 1-5 tier scale on the
+// rating scale
+func DifficultyRating(difficulty float64) float64 {
+	return TierOneRating + (difficulty-1)*TierRatingStep
+}
+
+// RatingDifficulty places a rating on the 1-5 tier scale
+func RatingDifficulty(rating float64) float64 {
+	difficulty := 1 + (rating-TierOneRating)/TierRatingStep
+	return math.Max(1, math.Min(5, difficulty))
+}
@@ -21,6 +21,36 @@ type User struct {
 	LastActive   *time.Time `json:"last_active,omitempty" db:"last_active"`
 	IsActive     bool       `json:"is_active" db:"is_active"`
 	IsVerified   bool       `json:"is_verified" db:"is_verified"`
+	Role         string     `json:"role" db:"role"`
+}
+
+// User roles, from least to most privileged
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// roleLevels orders roles so a higher role satisfies a lower requirement
+var roleLevels = map[string]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+// IsValidRole reports whether role is a known role
+func IsValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// RoleAtLeast reports whether role grants at least the permissions of minRole
+func RoleAtLeast(role, minRole string) bool {
+	level, ok := roleLevels[role]
+	if !ok {
+		return false
+	}
+	return level >= roleLevels[minRole]
 }
 
 // RegisterRequest is the payload for user registration
@@ -45,12 +75,51 @@ type AuthResponse struct {
 	ExpiresIn    int64  `json:"expires_in"` // seconds
 }
 
+// RefreshToken is the server-side record of an issued refresh token.
+// Tokens in the same family descend from one login and are rotated on use.
+type RefreshToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"` // jti
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	FamilyID   uuid.UUID  `json:"family_id" db:"family_id"` // sid
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty" db:"used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	ReplacedBy *uuid.UUID `json:"replaced_by,omitempty" db:"replaced_by"`
+}
+
+// RefreshTokenRequest is the payload for refreshing tokens
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// VerifyEmailRequest is the payload for confirming an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ForgotPasswordRequest is the payload for requesting a password reset
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest is the payload for setting a new password
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
 // UpdateUserRequest is the payload for updating user profile
 type UpdateUserRequest struct {
 	DisplayName *string `json:"display_name,omitempty" validate:"omitempty,max=50"`
 	AvatarURL   *string `json:"avatar_url,omitempty" validate:"omitempty,url"`
 }
 
+// UpdateRoleRequest is the payload for changing a user's role
+type UpdateRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user moderator admin"`
+}
+
 // UserStats represents user statistics
 type UserStats struct {
 	TotalPoints         int64   `json:"total_points"`
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
 	"github.com/fanmania/backend/internal/middleware"
 	"github.com/fanmania/backend/internal/service"
 	"github.com/go-playground/validator/v10"
@@ -14,37 +16,31 @@ import (
 // AdminHandler handles admin operations
 type AdminHandler struct {
 	aiChallengeService *service.AIChallengeService
+	generationQueue    *service.GenerationQueue
 	validate           *validator.Validate
 }
 
 // NewAdminHandler creates a new AdminHandler
-func NewAdminHandler(aiChallengeService *service.AIChallengeService) *AdminHandler {
+func NewAdminHandler(
+	aiChallengeService *service.AIChallengeService,
+	generationQueue *service.GenerationQueue,
+) *AdminHandler {
 	return &AdminHandler{
 		aiChallengeService: aiChallengeService,
+		generationQueue:    generationQueue,
 		validate:           validator.New(),
 	}
 }
 
-// GenerateChallenge generates a single challenge using AI
+// GenerateChallenge generates a single challenge using AI. Without
+// save_to_database the challenge is generated inline as a preview; with
+// it, a generation job is queued and returned for polling.
 // POST /admin/challenges/generate
 func (h *AdminHandler) GenerateChallenge(c *fiber.Ctx) error {
-	// For now, we'll skip admin authentication
-	// In production, add admin middleware check
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
-			"error": errors.ErrUnauthorized.Message,
-			"code":  errors.ErrUnauthorized.Code,
-		})
-	}
-
-	// TODO: Check if user is admin
-	_ = userID
-
 	var req struct {
 		CategoryID     string `json:"category_id" validate:"required,uuid"`
 		DifficultyTier int    `json:"difficulty_tier" validate:"required,min=1,max=5"`
-		ChallengeType  string `json:"challenge_type" validate:"required,oneof=multiple_choice timeline true_false"`
+		ChallengeType  string `json:"challenge_type" validate:"required,oneof=multiple_choice timeline true_false pattern"`
 		SaveToDatabase bool   `json:"save_to_database"`
 	}
 
@@ -70,24 +66,17 @@ func (h *AdminHandler) GenerateChallenge(c *fiber.Ctx) error {
 		})
 	}
 
-	// Generate challenge
-	var result *service.GenerateChallengeResult
 	if req.SaveToDatabase {
-		result, err = h.aiChallengeService.GenerateAndSaveChallenge(
-			c.Context(),
-			categoryID,
-			req.DifficultyTier,
-			req.ChallengeType,
-		)
-	} else {
-		result, err = h.aiChallengeService.GenerateChallenge(
-			c.Context(),
-			categoryID,
-			req.DifficultyTier,
-			req.ChallengeType,
-		)
+		return h.enqueue(c, categoryID, []int{req.DifficultyTier}, req.ChallengeType, 1)
 	}
 
+	// Generate a preview
+	result, err := h.aiChallengeService.GenerateChallenge(
+		c.Context(),
+		categoryID,
+		req.DifficultyTier,
+		req.ChallengeType,
+	)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -98,24 +87,13 @@ func (h *AdminHandler) GenerateChallenge(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(result)
 }
 
-// GenerateBatch generates multiple challenges at once
+// GenerateBatch queues generation of multiple challenges, one job per tier
 // POST /admin/challenges/generate-batch
 func (h *AdminHandler) GenerateBatch(c *fiber.Ctx) error {
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
-			"error": errors.ErrUnauthorized.Message,
-			"code":  errors.ErrUnauthorized.Code,
-		})
-	}
-
-	// TODO: Check if user is admin
-	_ = userID
-
 	var req struct {
 		CategoryID      string `json:"category_id" validate:"required,uuid"`
 		DifficultyTiers []int  `json:"difficulty_tiers" validate:"required,min=1"`
-		ChallengeType   string `json:"challenge_type" validate:"required,oneof=multiple_choice timeline true_false"`
+		ChallengeType   string `json:"challenge_type" validate:"required,oneof=multiple_choice timeline true_false pattern"`
 		CountPerTier    int    `json:"count_per_tier" validate:"required,min=1,max=10"`
 	}
 
@@ -151,83 +129,142 @@ func (h *AdminHandler) GenerateBatch(c *fiber.Ctx) error {
 		})
 	}
 
-	// Generate batch
-	results, err := h.aiChallengeService.GenerateBatch(
-		c.Context(),
-		categoryID,
-		req.DifficultyTiers,
-		req.ChallengeType,
-		req.CountPerTier,
-	)
+	return h.enqueue(c, categoryID, req.DifficultyTiers, req.ChallengeType, req.CountPerTier)
+}
 
+// enqueue queues count challenges for each tier and responds with the jobs
+func (h *AdminHandler) enqueue(c *fiber.Ctx, categoryID uuid.UUID, tiers []int, challengeType string, count int) error {
+	var requestedBy *uuid.UUID
+	if userID, err := middleware.GetUserID(c); err == nil {
+		requestedBy = &userID
+	}
+
+	jobs := []*models.GenerationJob{}
+	seen := map[int]bool{}
+	for _, tier := range tiers {
+		if seen[tier] {
+			continue
+		}
+		seen[tier] = true
+
+		job, _, err := h.generationQueue.Enqueue(c.Context(), service.GenerationRequest{
+			CategoryID:     categoryID,
+			DifficultyTier: tier,
+			ChallengeType:  challengeType,
+			Count:          count,
+			Source:         models.GenerationSourceAdmin,
+			RequestedBy:    requestedBy,
+		})
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				return c.Status(appErr.StatusCode).JSON(fiber.Map{
+					"error": appErr.Message,
+					"code":  appErr.Code,
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to queue generation",
+				"code":  errors.ErrInternalServer.Code,
+			})
+		}
+		jobs = append(jobs, job)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"jobs": jobs,
+	})
+}
+
+// GetJob returns a generation job so its progress can be polled
+// GET /admin/jobs/:id
+func (h *AdminHandler) GetJob(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	job, err := h.generationQueue.GetJob(c.Context(), jobID)
 	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
+			"error": "Failed to get job",
 			"code":  errors.ErrInternalServer.Code,
 		})
 	}
 
-	// Count successes and failures
-	successCount := 0
-	failureCount := 0
-	for _, r := range results {
-		if r.Success {
-			successCount++
-		} else {
-			failureCount++
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+// ListJobs returns recent generation jobs
+// GET /admin/jobs?status=pending&limit=50
+func (h *AdminHandler) ListJobs(c *fiber.Ctx) error {
+	status := c.Query("status")
+	switch status {
+	case "", models.GenerationJobPending, models.GenerationJobRunning,
+		models.GenerationJobSucceeded, models.GenerationJobFailed:
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid status",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	// Parse limit (optional, default 50, max 200)
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit < 1 || parsedLimit > 200 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and 200",
+				"code":  "INVALID_REQUEST",
+			})
 		}
+		limit = parsedLimit
+	}
+
+	jobs, err := h.generationQueue.ListJobs(c.Context(), status, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list jobs",
+			"code":  errors.ErrInternalServer.Code,
+		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"results":       results,
-		"total":         len(results),
-		"success_count": successCount,
-		"failure_count": failureCount,
+		"jobs": jobs,
 	})
 }
 
-// ValidateAPIKey validates the Anthropic API key
+// ValidateAPIKey validates the configured AI provider's API keys
 // GET /admin/ai/validate-key
 func (h *AdminHandler) ValidateAPIKey(c *fiber.Ctx) error {
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
-			"error": errors.ErrUnauthorized.Message,
-			"code":  errors.ErrUnauthorized.Code,
-		})
-	}
-
-	// TODO: Check if user is admin
-	_ = userID
-
-	err = h.aiChallengeService.ValidateAPIKey(c.Context())
+	err := h.aiChallengeService.ValidateAPIKey(c.Context())
 	if err != nil {
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"valid": false,
-			"error": err.Error(),
+			"valid":    false,
+			"provider": h.aiChallengeService.ProviderName(),
+			"error":    err.Error(),
 		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"valid":   true,
-		"message": "API key is valid",
+		"valid":    true,
+		"provider": h.aiChallengeService.ProviderName(),
+		"message":  "API key is valid",
 	})
 }
 
 // GenerateCategories generates new category ideas using AI
 // POST /admin/categories/generate
 func (h *AdminHandler) GenerateCategories(c *fiber.Ctx) error {
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
-			"error": errors.ErrUnauthorized.Message,
-			"code":  errors.ErrUnauthorized.Code,
-		})
-	}
-
-	// TODO: Check if user is admin
-	_ = userID
-
 	var req struct {
 		Count          int  `json:"count" validate:"required,min=1,max=10"`
 		SaveToDatabase bool `json:"save_to_database"`
@@ -246,6 +283,7 @@ func (h *AdminHandler) GenerateCategories(c *fiber.Ctx) error {
 
 	// Generate categories
 	var result *service.GenerateCategoryResult
+	var err error
 	if req.SaveToDatabase {
 		result, err = h.aiChallengeService.GenerateAndSaveCategories(c.Context(), req.Count)
 	} else {
@@ -262,51 +300,53 @@ func (h *AdminHandler) GenerateCategories(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(result)
 }
 
-// GetGenerationStats returns statistics about AI-generated challenges
-// GET /admin/challenges/stats
+// GetGenerationStats returns statistics about AI-generated challenges:
+// counts per category and tier, validation and duplicate rates, player
+// usage, and provider token usage and estimated cost
+// GET /admin/challenges/stats?category_id=xxx&difficulty_tier=1&since=2024-01-01T00:00:00Z
 func (h *AdminHandler) GetGenerationStats(c *fiber.Ctx) error {
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
-			"error": errors.ErrUnauthorized.Message,
-			"code":  errors.ErrUnauthorized.Code,
-		})
-	}
+	var filter models.GenerationStatsFilter
 
-	// TODO: Check if user is admin
-	_ = userID
-
-	// Parse optional filters
-	categoryIDStr := c.Query("category_id")
-	var categoryID *uuid.UUID
-	if categoryIDStr != "" {
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
 		parsed, err := uuid.Parse(categoryIDStr)
-		if err == nil {
-			categoryID = &parsed
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid category ID",
+				"code":  "INVALID_ID",
+			})
 		}
+		filter.CategoryID = &parsed
 	}
 
-	difficultyStr := c.Query("difficulty_tier")
-	var difficulty *int
-	if difficultyStr != "" {
+	if difficultyStr := c.Query("difficulty_tier"); difficultyStr != "" {
 		parsed, err := strconv.Atoi(difficultyStr)
-		if err == nil && parsed >= 1 && parsed <= 5 {
-			difficulty = &parsed
+		if err != nil || parsed < 1 || parsed > 5 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "difficulty_tier must be between 1 and 5",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		filter.DifficultyTier = &parsed
+	}
+
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "since must be an RFC 3339 timestamp",
+				"code":  "INVALID_REQUEST",
+			})
 		}
+		filter.Since = &parsed
 	}
 
-	// TODO: Implement actual stats query
-	// For now, return placeholder
-	_ = categoryID
-	_ = difficulty
+	stats, err := h.aiChallengeService.GetGenerationStats(c.Context(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get generation stats",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"total_generated":    0,
-		"total_active":       0,
-		"by_difficulty":      map[int]int{},
-		"by_category":        map[string]int{},
-		"average_usage":      0,
-		"validation_rate":    0,
-		"message":            "Stats endpoint placeholder - implement actual queries",
-	})
+	return c.Status(fiber.StatusOK).JSON(stats)
 }
@@ -3,9 +3,11 @@ package handler
 import (
 	"github.com/fanmania/backend/internal/domain/errors"
 	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/fanmania/backend/internal/middleware"
 	"github.com/fanmania/backend/internal/service"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // AuthHandler handles authentication HTTP requests
@@ -99,10 +101,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 // RefreshToken handles token refresh
 // POST /auth/refresh
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
-	var req struct {
-		RefreshToken string `json:"refresh_token" validate:"required"`
-	}
-
+	var req models.RefreshTokenRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
@@ -135,3 +134,243 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
+
+// Logout revokes the current login session
+// POST /auth/logout
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	// Tokens issued before sessions existed cannot be tied to one
+	sessionID, err := middleware.GetSessionID(c)
+	if err != nil {
+		return c.Status(errors.ErrInvalidToken.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrInvalidToken.Message,
+			"code":  errors.ErrInvalidToken.Code,
+		})
+	}
+
+	if err := h.authService.Logout(c.Context(), userID, sessionID); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to logout",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// LogoutAll revokes every login session of the current user
+// POST /auth/logout-all
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	if err := h.authService.LogoutAll(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to logout",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// VerifyEmail confirms an email address from an emailed token
+// POST /auth/verify-email
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req models.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	if err := h.authService.VerifyEmail(c.Context(), req.Token); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to verify email",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Email verified",
+	})
+}
+
+// ResendVerification emails a new verification link to the current user
+// POST /auth/verify-email/resend
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	if err := h.authService.ResendVerificationEmail(c.Context(), userID); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to send verification email",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Verification email sent",
+	})
+}
+
+// ForgotPassword emails a password reset link
+// POST /auth/forgot-password
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req models.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	if err := h.authService.ForgotPassword(c.Context(), req.Email); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to request password reset",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	// Same response whether or not the address is registered
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If that email is registered, a reset link is on its way",
+	})
+}
+
+// ResetPassword sets a new password from an emailed token
+// POST /auth/reset-password
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req models.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	if err := h.authService.ResetPassword(c.Context(), req.Token, req.NewPassword); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to reset password",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Password updated; please log in again",
+	})
+}
+
+// UpdateUserRole changes another user's role (admin only)
+// PUT /admin/users/:id/role
+func (h *AuthHandler) UpdateUserRole(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.UpdateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	user, err := h.authService.UpdateUserRole(c.Context(), actorID, userID, req.Role)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update role",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(user)
+}
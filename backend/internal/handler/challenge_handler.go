@@ -111,6 +111,42 @@ func (h *ChallengeHandler) GetChallenges(c *fiber.Ctx) error {
 	})
 }
 
+// StartChallenge starts the clock on a challenge the user has opened
+// POST /challenges/:id/start
+func (h *ChallengeHandler) StartChallenge(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	session, err := h.challengeService.StartChallenge(c.Context(), userID, challengeID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(session)
+}
+
 // SubmitChallenge handles challenge submission
 // POST /challenges/:id/attempt
 func (h *ChallengeHandler) SubmitChallenge(c *fiber.Ctx) error {
@@ -134,8 +170,9 @@ func (h *ChallengeHandler) SubmitChallenge(c *fiber.Ctx) error {
 
 	// Parse request body
 	var req struct {
-		SelectedAnswer   string `json:"selected_answer" validate:"required"`
-		TimeTakenSeconds *int   `json:"time_taken_seconds,omitempty" validate:"omitempty,min=1"`
+		SelectedAnswer string   `json:"selected_answer" validate:"required_without=SelectedOrder"`
+		SelectedOrder  []string `json:"selected_order" validate:"omitempty,max=20,dive,required"`
+		SessionToken   string   `json:"session_token"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -155,9 +192,10 @@ func (h *ChallengeHandler) SubmitChallenge(c *fiber.Ctx) error {
 
 	// Create submission request
 	submission := &models.SubmitChallengeRequest{
-		ChallengeID:      challengeID,
-		SelectedAnswer:   req.SelectedAnswer,
-		TimeTakenSeconds: req.TimeTakenSeconds,
+		ChallengeID:    challengeID,
+		SelectedAnswer: req.SelectedAnswer,
+		SelectedOrder:  req.SelectedOrder,
+		SessionToken:   req.SessionToken,
 	}
 
 	// Submit attempt
@@ -203,3 +241,525 @@ func (h *ChallengeHandler) GetUserAttemptStats(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(stats)
 }
+
+// CreatePrediction opens a prediction challenge (admin only)
+// POST /admin/predictions
+func (h *ChallengeHandler) CreatePrediction(c *fiber.Ctx) error {
+	var req models.CreatePredictionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	editorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challenge, err := h.challengeService.CreatePrediction(c.Context(), editorID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create prediction",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(challenge)
+}
+
+// ResolvePrediction records a prediction's outcome and scores its picks (admin only)
+// POST /admin/predictions/:id/resolve
+func (h *ChallengeHandler) ResolvePrediction(c *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.ResolvePredictionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	editorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	resolution, err := h.challengeService.ResolvePrediction(c.Context(), editorID, challengeID, req.CorrectAnswer)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to resolve prediction",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resolution)
+}
+
+// ListAdminChallenges lists challenges, including inactive ones (admin only)
+// GET /admin/challenges?category_id=xxx&challenge_type=timeline&is_active=false&review_status=rejected&difficulty_flag=too_easy&limit=50&offset=0
+func (h *ChallengeHandler) ListAdminChallenges(c *fiber.Ctx) error {
+	filter := models.ChallengeFilter{
+		ChallengeType:  c.Query("challenge_type"),
+		ReviewStatus:   c.Query("review_status"),
+		DifficultyFlag: c.Query("difficulty_flag"),
+		Limit:          50,
+	}
+
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		categoryID, err := uuid.Parse(categoryIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid category_id format",
+				"code":  "INVALID_ID",
+			})
+		}
+		filter.CategoryID = &categoryID
+	}
+
+	if activeStr := c.Query("is_active"); activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "is_active must be true or false",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		filter.IsActive = &active
+	}
+
+	// Parse limit (optional, default 50, max 200)
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 200 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and 200",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		filter.Limit = limit
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "offset must be a non-negative integer",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		filter.Offset = offset
+	}
+
+	challenges, err := h.challengeService.ListChallengesForEditor(c.Context(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list challenges",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"challenges": challenges,
+		"count":      len(challenges),
+	})
+}
+
+// GetAdminChallenge retrieves a challenge, active or not (admin only)
+// GET /admin/challenges/:id
+func (h *ChallengeHandler) GetAdminChallenge(c *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	challenge, err := h.challengeService.GetChallengeForEditor(c.Context(), challengeID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(challenge)
+}
+
+// AuthorChallenge creates a hand-written challenge (admin only)
+// POST /admin/challenges
+func (h *ChallengeHandler) AuthorChallenge(c *fiber.Ctx) error {
+	editorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	var req models.ChallengeInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	challenge, validation, err := h.challengeService.AuthorChallenge(c.Context(), editorID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			if validation != nil && !validation.Passed {
+				return c.Status(appErr.StatusCode).JSON(fiber.Map{
+					"error":      appErr.Message,
+					"code":       appErr.Code,
+					"validation": validation,
+				})
+			}
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"challenge":  challenge,
+		"validation": validation,
+	})
+}
+
+// EditChallenge replaces a challenge's content as a new revision (admin only)
+// PUT /admin/challenges/:id
+func (h *ChallengeHandler) EditChallenge(c *fiber.Ctx) error {
+	editorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.ChallengeInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	challenge, validation, err := h.challengeService.EditChallenge(c.Context(), editorID, challengeID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			if validation != nil && !validation.Passed {
+				return c.Status(appErr.StatusCode).JSON(fiber.Map{
+					"error":      appErr.Message,
+					"code":       appErr.Code,
+					"validation": validation,
+				})
+			}
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"challenge":  challenge,
+		"validation": validation,
+	})
+}
+
+// ScheduleChallenge sets when a challenge takes attempts (admin only)
+// PUT /admin/challenges/:id/schedule
+func (h *ChallengeHandler) ScheduleChallenge(c *fiber.Ctx) error {
+	editorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.ChallengeScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	challenge, err := h.challengeService.ScheduleChallenge(c.Context(), editorID, challengeID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to schedule challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(challenge)
+}
+
+// ActivateChallenge puts a challenge live (admin only)
+// POST /admin/challenges/:id/activate
+func (h *ChallengeHandler) ActivateChallenge(c *fiber.Ctx) error {
+	return h.setChallengeActive(c, true)
+}
+
+// DeactivateChallenge takes a challenge out of play (admin only)
+// POST /admin/challenges/:id/deactivate
+func (h *ChallengeHandler) DeactivateChallenge(c *fiber.Ctx) error {
+	return h.setChallengeActive(c, false)
+}
+
+func (h *ChallengeHandler) setChallengeActive(c *fiber.Ctx, active bool) error {
+	editorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	challenge, err := h.challengeService.SetChallengeActive(c.Context(), editorID, challengeID, active)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(challenge)
+}
+
+// CloneChallenge copies a challenge into a new inactive challenge (admin only)
+// POST /admin/challenges/:id/clone
+func (h *ChallengeHandler) CloneChallenge(c *fiber.Ctx) error {
+	editorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.CloneChallengeRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	challenge, err := h.challengeService.CloneChallenge(c.Context(), editorID, challengeID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to clone challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(challenge)
+}
+
+// GetChallengeRevisions lists a challenge's revisions, latest first (admin only)
+// GET /admin/challenges/:id/revisions
+func (h *ChallengeHandler) GetChallengeRevisions(c *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	revisions, err := h.challengeService.GetRevisions(c.Context(), challengeID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get revisions",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"revisions": revisions,
+		"count":     len(revisions),
+	})
+}
+
+// GetChallengeRevision retrieves one revision of a challenge (admin only)
+// GET /admin/challenges/:id/revisions/:revision
+func (h *ChallengeHandler) GetChallengeRevision(c *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	revision, err := strconv.Atoi(c.Params("revision"))
+	if err != nil || revision < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid revision",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	challengeRevision, err := h.challengeService.GetRevision(c.Context(), challengeID, revision)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get revision",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(challengeRevision)
+}
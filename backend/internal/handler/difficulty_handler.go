@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DifficultyHandler handles challenge difficulty calibration HTTP requests
+type DifficultyHandler struct {
+	difficultyService *service.DifficultyService
+}
+
+// NewDifficultyHandler creates a new DifficultyHandler
+func NewDifficultyHandler(difficultyService *service.DifficultyService) *DifficultyHandler {
+	return &DifficultyHandler{
+		difficultyService: difficultyService,
+	}
+}
+
+// CalibrateDifficulty recalibrates challenge difficulty now rather than
+// waiting for the next scheduled run (admin only)
+// POST /admin/challenges/calibrate-difficulty
+func (h *DifficultyHandler) CalibrateDifficulty(c *fiber.Ctx) error {
+	result, err := h.difficultyService.Calibrate(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to calibrate difficulty",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	if result.Skipped {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Difficulty calibration is already running",
+			"code":  "CALIBRATION_IN_PROGRESS",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"fmt"
+	"log"
 	"strconv"
 
 	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
 	"github.com/fanmania/backend/internal/middleware"
 	"github.com/fanmania/backend/internal/service"
 	"github.com/gofiber/fiber/v2"
@@ -22,64 +25,18 @@ func NewLeaderboardHandler(rankingService *service.RankingService) *LeaderboardH
 	}
 }
 
+// Entries returned when around=me is given without a limit, on each side
+// of the caller
+const defaultAroundLimit = 10
+
 // GetGlobalLeaderboard retrieves the global leaderboard
-// GET /leaderboards/global?scope=weekly&limit=100
+// GET /leaderboards/global?mode=points&scope=weekly&limit=100&cursor=...&around=me
 func (h *LeaderboardHandler) GetGlobalLeaderboard(c *fiber.Ctx) error {
-	// Parse scope (optional, default: weekly)
-	scope := c.Query("scope", "weekly")
-	validScopes := map[string]bool{
-		"daily":    true,
-		"weekly":   true,
-		"monthly":  true,
-		"all_time": true,
-	}
-	if !validScopes[scope] {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid scope. Must be: daily, weekly, monthly, or all_time",
-			"code":  "INVALID_REQUEST",
-		})
-	}
-
-	// Parse limit (optional, default 100, max 500)
-	limit := 100
-	if limitStr := c.Query("limit"); limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit < 1 || parsedLimit > 500 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "limit must be between 1 and 500",
-				"code":  "INVALID_REQUEST",
-			})
-		}
-		limit = parsedLimit
-	}
-
-	// Get leaderboard
-	leaderboard, err := h.rankingService.GetLeaderboard(
-		c.Context(),
-		nil, // nil = global leaderboard
-		scope,
-		limit,
-	)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get leaderboard",
-			"code":  errors.ErrInternalServer.Code,
-		})
-	}
-
-	// Get current user's rank (if authenticated)
-	if userID, err := middleware.GetUserID(c); err == nil {
-		// Recalculate ranks to ensure they're up to date
-		_ = h.rankingService.RecalculateGlobalRanks(c.Context())
-		// TODO: Get user's actual rank after recalculation
-		_ = userID
-	}
-
-	return c.Status(fiber.StatusOK).JSON(leaderboard)
+	return h.getLeaderboard(c, nil)
 }
 
 // GetCategoryLeaderboard retrieves leaderboard for a specific category
-// GET /leaderboards/category/:id?scope=weekly&limit=100
+// GET /leaderboards/category/:id?mode=points&scope=weekly&limit=100&cursor=...&around=me
 func (h *LeaderboardHandler) GetCategoryLeaderboard(c *fiber.Ctx) error {
 	// Parse category ID
 	categoryIDStr := c.Params("id")
@@ -91,8 +48,22 @@ func (h *LeaderboardHandler) GetCategoryLeaderboard(c *fiber.Ctx) error {
 		})
 	}
 
-	// Parse scope
-	scope := c.Query("scope", "weekly")
+	return h.getLeaderboard(c, &categoryID)
+}
+
+// getLeaderboard responds with a page of the leaderboard for a category
+// or, when categoryID is nil, the global one. A signed-in caller also gets
+// their own rank and points, unless looking them up fails. With around=me
+// the page is centred on the caller, limit entries either side of them.
+func (h *LeaderboardHandler) getLeaderboard(c *fiber.Ctx, categoryID *uuid.UUID) error {
+	// Parse mode and scope (optional, default: points, weekly)
+	mode, scope, err := parseLeaderboardMode(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  "INVALID_REQUEST",
+		})
+	}
 	validScopes := map[string]bool{
 		"daily":    true,
 		"weekly":   true,
@@ -106,8 +77,19 @@ func (h *LeaderboardHandler) GetCategoryLeaderboard(c *fiber.Ctx) error {
 		})
 	}
 
-	// Parse limit
+	around := c.Query("around")
+	if around != "" && around != "me" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid around. Must be: me",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	// Parse limit (optional, default 100, or 10 either side around=me, max 500)
 	limit := 100
+	if around != "" {
+		limit = defaultAroundLimit
+	}
 	if limitStr := c.Query("limit"); limitStr != "" {
 		parsedLimit, err := strconv.Atoi(limitStr)
 		if err != nil || parsedLimit < 1 || parsedLimit > 500 {
@@ -119,13 +101,64 @@ func (h *LeaderboardHandler) GetCategoryLeaderboard(c *fiber.Ctx) error {
 		limit = parsedLimit
 	}
 
+	// Parse cursor (optional): next_cursor from the previous page
+	var after *models.LeaderboardCursor
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		if around != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "cursor cannot be combined with around",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		if after, err = models.ParseLeaderboardCursor(cursorStr); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid cursor",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+	}
+
+	query := models.LeaderboardQuery{
+		CategoryID: categoryID,
+		Mode:       mode,
+		Scope:      scope,
+	}
+
+	// Get current user's entry (if authenticated). The board is public, so
+	// it is still served if this fails, just without the caller's rank.
+	var me *models.LeaderboardEntry
+	userID, authErr := middleware.GetUserID(c)
+	if authErr == nil {
+		if me, err = h.rankingService.GetLeaderboardEntry(c.Context(), query, userID); err != nil {
+			log.Printf("⚠ Failed to get leaderboard entry for user %s: %v", userID, err)
+			if around != "" {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to get leaderboard",
+					"code":  errors.ErrInternalServer.Code,
+				})
+			}
+		}
+	}
+
 	// Get leaderboard
-	leaderboard, err := h.rankingService.GetLeaderboard(
-		c.Context(),
-		&categoryID,
-		scope,
-		limit,
-	)
+	var leaderboard *models.LeaderboardResponse
+	if around != "" {
+		if authErr != nil {
+			return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+				"error": errors.ErrUnauthorized.Message,
+				"code":  errors.ErrUnauthorized.Code,
+			})
+		}
+		if me == nil {
+			return c.Status(errors.ErrNotOnLeaderboard.StatusCode).JSON(fiber.Map{
+				"error": errors.ErrNotOnLeaderboard.Message,
+				"code":  errors.ErrNotOnLeaderboard.Code,
+			})
+		}
+		leaderboard, err = h.rankingService.GetLeaderboardAround(c.Context(), query, me, limit)
+	} else {
+		leaderboard, err = h.rankingService.GetLeaderboard(c.Context(), query, after, limit)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to get leaderboard",
@@ -133,13 +166,29 @@ func (h *LeaderboardHandler) GetCategoryLeaderboard(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get current user's rank in this category (if authenticated)
-	if userID, err := middleware.GetUserID(c); err == nil {
-		userRank, err := h.rankingService.GetUserRankInCategory(c.Context(), userID, categoryID)
-		if err == nil {
-			leaderboard.UserRank = userRank
-		}
+	if me != nil {
+		leaderboard.UserRank = &me.Rank
+		leaderboard.UserPoints = &me.Points
 	}
 
 	return c.Status(fiber.StatusOK).JSON(leaderboard)
 }
+
+// parseLeaderboardMode reads the mode and scope query parameters. Points
+// leaderboards default to the weekly scope; rating leaderboards only
+// exist for all time, since a rating is not earned per period.
+func parseLeaderboardMode(c *fiber.Ctx) (mode, scope string, err error) {
+	mode = c.Query("mode", "points")
+	switch mode {
+	case "points":
+		return mode, c.Query("scope", "weekly"), nil
+	case "rating":
+		scope = c.Query("scope", "all_time")
+		if scope != "all_time" {
+			return "", "", fmt.Errorf("rating leaderboards only support the all_time scope")
+		}
+		return mode, scope, nil
+	default:
+		return "", "", fmt.Errorf("Invalid mode. Must be: points or rating")
+	}
+}
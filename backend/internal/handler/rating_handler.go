@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RatingHandler handles skill rating maintenance HTTP requests
+type RatingHandler struct {
+	skillService *service.SkillService
+}
+
+// NewRatingHandler creates a new RatingHandler
+func NewRatingHandler(skillService *service.SkillService) *RatingHandler {
+	return &RatingHandler{
+		skillService: skillService,
+	}
+}
+
+// RecalculateRatings rebuilds every user and challenge rating from the
+// attempt history (admin only)
+// POST /admin/ratings/recalculate
+func (h *RatingHandler) RecalculateRatings(c *fiber.Ctx) error {
+	result, err := h.skillService.RecalculateRatings(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to recalculate ratings",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/fanmania/backend/internal/middleware"
+	"github.com/fanmania/backend/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ReportHandler handles challenge report HTTP requests
+type ReportHandler struct {
+	reportService *service.ReportService
+	validate      *validator.Validate
+}
+
+// NewReportHandler creates a new ReportHandler
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+		validate:      validator.New(),
+	}
+}
+
+// ReportChallenge records the current user's report against a challenge
+// POST /challenges/:id/report
+func (h *ReportHandler) ReportChallenge(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.ReportChallengeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	report, err := h.reportService.Report(c.Context(), userID, challengeID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to report challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
+
+// ListReportedChallenges aggregates reports per challenge, most reported first (admin only)
+// GET /admin/reports?status=open&limit=50&offset=0
+func (h *ReportHandler) ListReportedChallenges(c *fiber.Ctx) error {
+	status := c.Query("status", models.ReportOpen)
+	if status != models.ReportOpen && status != models.ReportUpheld && status != models.ReportDismissed {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "status must be open, upheld or dismissed",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	// Parse limit (optional, default 50, max 200)
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit < 1 || parsedLimit > 200 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and 200",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		limit = parsedLimit
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		parsedOffset, err := strconv.Atoi(offsetStr)
+		if err != nil || parsedOffset < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "offset must be a non-negative integer",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		offset = parsedOffset
+	}
+
+	summaries, err := h.reportService.ListSummaries(c.Context(), status, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list reports",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"challenges": summaries,
+		"count":      len(summaries),
+	})
+}
+
+// GetChallengeReports lists the reports against a challenge (admin only)
+// GET /admin/challenges/:id/reports?status=open
+func (h *ReportHandler) GetChallengeReports(c *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	reports, err := h.reportService.ListReports(c.Context(), challengeID, c.Query("status"))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get reports",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"reports": reports,
+		"count":   len(reports),
+	})
+}
+
+// ResolveReports closes a challenge's open reports, regrading or voiding
+// the challenge if they are upheld (admin only)
+// POST /admin/challenges/:id/reports/resolve
+func (h *ReportHandler) ResolveReports(c *fiber.Ctx) error {
+	editorID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.ResolveReportsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	resolution, err := h.reportService.Resolve(c.Context(), editorID, challengeID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to resolve reports",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resolution)
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/fanmania/backend/internal/middleware"
+	"github.com/fanmania/backend/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ReviewHandler handles the challenge review queue for moderators
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+	validate      *validator.Validate
+}
+
+// NewReviewHandler creates a new ReviewHandler
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+		validate:      validator.New(),
+	}
+}
+
+// ListPending lists challenges awaiting review, oldest first
+// GET /moderation/challenges?limit=50&offset=0
+func (h *ReviewHandler) ListPending(c *fiber.Ctx) error {
+	// Parse limit (optional, default 50, max 200)
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit < 1 || parsedLimit > 200 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and 200",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		limit = parsedLimit
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		parsedOffset, err := strconv.Atoi(offsetStr)
+		if err != nil || parsedOffset < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "offset must be a non-negative integer",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+		offset = parsedOffset
+	}
+
+	challenges, err := h.reviewService.ListPending(c.Context(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list review queue",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"challenges": challenges,
+		"count":      len(challenges),
+	})
+}
+
+// GetBacklog reports the size and age of the review queue
+// GET /moderation/backlog
+func (h *ReviewHandler) GetBacklog(c *fiber.Ctx) error {
+	backlog, err := h.reviewService.GetBacklog(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get review backlog",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(backlog)
+}
+
+// Approve publishes a challenge awaiting review
+// POST /moderation/challenges/:id/approve
+func (h *ReviewHandler) Approve(c *fiber.Ctx) error {
+	return h.decide(c, h.reviewService.Approve)
+}
+
+// Reject keeps a challenge awaiting review out of play
+// POST /moderation/challenges/:id/reject
+func (h *ReviewHandler) Reject(c *fiber.Ctx) error {
+	return h.decide(c, h.reviewService.Reject)
+}
+
+func (h *ReviewHandler) decide(
+	c *fiber.Ctx,
+	decision func(ctx context.Context, reviewerID, challengeID uuid.UUID, reason string) (*models.Challenge, error),
+) error {
+	reviewerID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.ReviewDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+				"code":  "INVALID_REQUEST",
+			})
+		}
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	challenge, err := decision(c.Context(), reviewerID, challengeID, req.Reason)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record review",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(challenge)
+}
+
+// Edit corrects a challenge awaiting review; it stays queued until approved
+// PUT /moderation/challenges/:id
+func (h *ReviewHandler) Edit(c *fiber.Ctx) error {
+	reviewerID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+			"error": errors.ErrUnauthorized.Message,
+			"code":  errors.ErrUnauthorized.Code,
+		})
+	}
+
+	challengeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid challenge ID",
+			"code":  "INVALID_ID",
+		})
+	}
+
+	var req models.ChallengeInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+			"code":  "INVALID_REQUEST",
+		})
+	}
+
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+			"code":  errors.ErrInvalidInput.Code,
+		})
+	}
+
+	challenge, validation, err := h.reviewService.Edit(c.Context(), reviewerID, challengeID, &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			if validation != nil && !validation.Passed {
+				return c.Status(appErr.StatusCode).JSON(fiber.Map{
+					"error":      appErr.Message,
+					"code":       appErr.Code,
+					"validation": validation,
+				})
+			}
+			return c.Status(appErr.StatusCode).JSON(fiber.Map{
+				"error": appErr.Message,
+				"code":  appErr.Code,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update challenge",
+			"code":  errors.ErrInternalServer.Code,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"challenge":  challenge,
+		"validation": validation,
+	})
+}
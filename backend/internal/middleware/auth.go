@@ -33,7 +33,7 @@ func AuthMiddleware(authService *service.AuthService) fiber.Handler {
 		token := parts[1]
 
 		// Validate token
-		userID, err := authService.ValidateToken(token)
+		claims, err := authService.ValidateAccessToken(token)
 		if err != nil {
 			return c.Status(errors.ErrInvalidToken.StatusCode).JSON(fiber.Map{
 				"error": errors.ErrInvalidToken.Message,
@@ -41,13 +41,28 @@ func AuthMiddleware(authService *service.AuthService) fiber.Handler {
 			})
 		}
 
-		// Store user ID in context
-		c.Locals("userID", userID)
+		// Store user ID, role and login session in context
+		c.Locals("userID", claims.UserID)
+		c.Locals("userRole", claims.Role)
+		c.Locals("sessionID", claims.SessionID)
 
 		return c.Next()
 	}
 }
 
+// OptionalAuthMiddleware identifies the caller on public routes. Requests
+// without an Authorization header pass through anonymously; a header that
+// is present must still hold a valid token.
+func OptionalAuthMiddleware(authService *service.AuthService) fiber.Handler {
+	auth := AuthMiddleware(authService)
+	return func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			return c.Next()
+		}
+		return auth(c)
+	}
+}
+
 // GetUserID extracts user ID from context
 func GetUserID(c *fiber.Ctx) (uuid.UUID, error) {
 	userID, ok := c.Locals("userID").(uuid.UUID)
@@ -56,3 +71,12 @@ func GetUserID(c *fiber.Ctx) (uuid.UUID, error) {
 	}
 	return userID, nil
 }
+
+// GetSessionID extracts the login session (refresh token family) from context
+func GetSessionID(c *fiber.Ctx) (uuid.UUID, error) {
+	sessionID, ok := c.Locals("sessionID").(uuid.UUID)
+	if !ok || sessionID == uuid.Nil {
+		return uuid.Nil, errors.ErrInvalidToken
+	}
+	return sessionID, nil
+}
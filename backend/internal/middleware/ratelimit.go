@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/pkg/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitPolicy is a named limit applied to callers identified by Key
+type RateLimitPolicy struct {
+	Name  string
+	Limit ratelimit.Limit
+	Key   func(c *fiber.Ctx) string
+}
+
+// RateLimit enforces policy using store and sets the RateLimit-* headers.
+// If the store is unavailable the request is let through rather than
+// taking the API down with it.
+func RateLimit(store ratelimit.Store, policy RateLimitPolicy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := policy.Name + ":" + policy.Key(c)
+
+		result, err := store.Allow(c.Context(), key, policy.Limit)
+		if err != nil {
+			log.Printf("⚠ Rate limiter unavailable for %s: %v", policy.Name, err)
+			return c.Next()
+		}
+
+		c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			return c.Status(errors.ErrRateLimitExceeded.StatusCode).JSON(fiber.Map{
+				"error": errors.ErrRateLimitExceeded.Message,
+				"code":  errors.ErrRateLimitExceeded.Code,
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// KeyByIP identifies callers by client IP
+func KeyByIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// KeyByUser identifies authenticated callers by user ID, falling back to
+// client IP. It must run after AuthMiddleware to see the user.
+func KeyByUser(c *fiber.Ctx) string {
+	if userID, err := GetUserID(c); err == nil {
+		return "user:" + userID.String()
+	}
+	return KeyByIP(c)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole restricts a route to users holding at least minRole.
+// It must run after AuthMiddleware.
+func RequireRole(minRole string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, err := GetUserRole(c)
+		if err != nil {
+			return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+				"error": errors.ErrUnauthorized.Message,
+				"code":  errors.ErrUnauthorized.Code,
+			})
+		}
+
+		if !models.RoleAtLeast(role, minRole) {
+			return c.Status(errors.ErrForbidden.StatusCode).JSON(fiber.Map{
+				"error": errors.ErrForbidden.Message,
+				"code":  errors.ErrForbidden.Code,
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// GetUserRole extracts the user's role from context
+func GetUserRole(c *fiber.Ctx) (string, error) {
+	role, ok := c.Locals("userRole").(string)
+	if !ok || role == "" {
+		return "", errors.ErrUnauthorized
+	}
+	return role, nil
+}
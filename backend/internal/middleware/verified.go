@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireVerified restricts a route to users with a verified email address.
+// It must run after AuthMiddleware.
+func RequireVerified(authService *service.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := GetUserID(c)
+		if err != nil {
+			return c.Status(errors.ErrUnauthorized.StatusCode).JSON(fiber.Map{
+				"error": errors.ErrUnauthorized.Message,
+				"code":  errors.ErrUnauthorized.Code,
+			})
+		}
+
+		verified, err := authService.IsEmailVerified(c.Context(), userID)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				return c.Status(appErr.StatusCode).JSON(fiber.Map{
+					"error": appErr.Message,
+					"code":  appErr.Code,
+				})
+			}
+			return c.Status(errors.ErrInternalServer.StatusCode).JSON(fiber.Map{
+				"error": errors.ErrInternalServer.Message,
+				"code":  errors.ErrInternalServer.Code,
+			})
+		}
+
+		if !verified {
+			return c.Status(errors.ErrEmailNotVerified.StatusCode).JSON(fiber.Map{
+				"error": errors.ErrEmailNotVerified.Message,
+				"code":  errors.ErrEmailNotVerified.Code,
+			})
+		}
+
+		return c.Next()
+	}
+}
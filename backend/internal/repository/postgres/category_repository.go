@@ -31,7 +31,7 @@ func (r *CategoryRepository) GetAll(ctx context.Context, userID *uuid.UUID) ([]m
 		ORDER BY c.sort_order ASC, c.name ASC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.Conn(ctx).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query categories: %w", err)
 	}
@@ -85,7 +85,7 @@ func (r *CategoryRepository) GetByID(ctx context.Context, id uuid.UUID, userID *
 	`
 
 	var cat models.Category
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.Conn(ctx).QueryRow(ctx, query, id).Scan(
 		&cat.ID,
 		&cat.Name,
 		&cat.Slug,
@@ -127,7 +127,7 @@ func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*model
 	`
 
 	var cat models.Category
-	err := r.db.Pool.QueryRow(ctx, query, slug).Scan(
+	err := r.db.Conn(ctx).QueryRow(ctx, query, slug).Scan(
 		&cat.ID,
 		&cat.Name,
 		&cat.Slug,
@@ -152,18 +152,32 @@ func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*model
 
 // GetUserStats retrieves user statistics for a specific category
 func (r *CategoryRepository) GetUserStats(ctx context.Context, categoryID, userID uuid.UUID) (*models.CategoryUserStats, error) {
+	// Streak days come from the category row of user_streaks so they agree
+	// with the streak reported after each attempt. Rank counts the active
+	// players ahead on points, ties going to the longest-standing user, as
+	// on the category leaderboard.
 	query := `
 		SELECT 
-			COALESCE(points, 0) as points,
-			rank,
-			COALESCE(mastery_percentage, 0) as mastery_percentage,
-			COALESCE(streak_days, 0) as streak_days
-		FROM category_rankings
-		WHERE category_id = $1 AND user_id = $2
+			COALESCE(cr.points, 0) as points,
+			CASE WHEN cr.user_id IS NOT NULL THEN (
+				SELECT COUNT(*) + 1
+				FROM category_rankings ahead
+				JOIN users au ON au.id = ahead.user_id AND au.is_active = true
+				WHERE ahead.category_id = cr.category_id
+				  AND (ahead.points > cr.points
+				       OR (ahead.points = cr.points
+				           AND (au.created_at, au.id) < (u.created_at, u.id)))
+			) END as rank,
+			COALESCE(cr.mastery_percentage, 0) as mastery_percentage,
+			COALESCE(CASE WHEN us.last_activity_date >= $3 THEN us.current_streak END, 0) as streak_days
+		FROM (SELECT $1::uuid AS category_id, $2::uuid AS user_id) k
+		LEFT JOIN category_rankings cr ON cr.category_id = k.category_id AND cr.user_id = k.user_id
+		LEFT JOIN users u ON u.id = k.user_id
+		LEFT JOIN user_streaks us ON us.category_id = k.category_id AND us.user_id = k.user_id
 	`
 
 	var stats models.CategoryUserStats
-	err := r.db.Pool.QueryRow(ctx, query, categoryID, userID).Scan(
+	err := r.db.Conn(ctx).QueryRow(ctx, query, categoryID, userID, streakCutoff()).Scan(
 		&stats.Points,
 		&stats.Rank,
 		&stats.MasteryPercentage,
@@ -171,15 +185,6 @@ func (r *CategoryRepository) GetUserStats(ctx context.Context, categoryID, userI
 	)
 
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			// Return zero stats if no ranking exists yet
-			return &models.CategoryUserStats{
-				Points:            0,
-				Rank:              nil,
-				MasteryPercentage: 0,
-				StreakDays:        0,
-			}, nil
-		}
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
 	}
 
@@ -194,7 +199,7 @@ func (r *CategoryRepository) Create(ctx context.Context, category *models.Catego
 		RETURNING id, created_at, is_active
 	`
 
-	err := r.db.Pool.QueryRow(
+	err := r.db.Conn(ctx).QueryRow(
 		ctx,
 		query,
 		category.Name,
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/fanmania/backend/internal/domain/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // ChallengeRepository handles challenge database operations
@@ -21,18 +23,49 @@ func NewChallengeRepository(db *DB) *ChallengeRepository {
 	return &ChallengeRepository{db: db}
 }
 
-// Create creates a new challenge
+// challengeColumns are the challenge columns read by scanChallenge
+const challengeColumns = `id, category_id, title, description, question_data, correct_answer_hash,
+	       difficulty_tier, base_points, time_limit_seconds, challenge_type,
+	       ai_generated, is_active, active_from, active_until, resolved_at, created_at, usage_count,
+	       revision, edited_by, review_status, review_flags, reviewed_by, reviewed_at, review_reason,
+	       suspended_at, empirical_difficulty, solve_rate, median_time_ratio, difficulty_sample_size,
+	       difficulty_flag, difficulty_assessed_at, original_difficulty_tier, explanation, sources,
+	       rating, rating_attempts`
+
+// revisionSnapshot inserts a challenge_revisions row for each challenge
+// row produced by the CTE named source. Writes go through it so every
+// revision of a challenge is kept.
+const revisionSnapshot = `
+	INSERT INTO challenge_revisions (
+		challenge_id, revision, category_id, title, description, question_data,
+		correct_answer_hash, difficulty_tier, base_points, time_limit_seconds,
+		challenge_type, is_active, active_from, active_until, edited_by, explanation, sources
+	)
+	SELECT id, revision, category_id, title, description, question_data,
+	       correct_answer_hash, difficulty_tier, base_points, time_limit_seconds,
+	       challenge_type, is_active, active_from, active_until, edited_by, explanation, sources
+	FROM %s
+`
+
+// Create creates a new challenge and records it as revision 1
 func (r *ChallengeRepository) Create(ctx context.Context, challenge *models.Challenge) error {
 	query := `
-		INSERT INTO challenges (
-			category_id, title, description, question_data, correct_answer_hash,
-			difficulty_tier, base_points, time_limit_seconds, challenge_type,
-			ai_generated, ai_model_version, generation_prompt_hash, active_until
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-		RETURNING id, is_active, created_at, usage_count
+		WITH created AS (
+			INSERT INTO challenges (
+				category_id, title, description, question_data, correct_answer_hash,
+				difficulty_tier, base_points, time_limit_seconds, challenge_type,
+				ai_generated, ai_model_version, generation_prompt_hash, active_from, active_until,
+				is_active, edited_by, review_status, review_flags, explanation, sources, rating
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, COALESCE($13, CURRENT_TIMESTAMP), $14, $15, $16,
+				COALESCE(NULLIF($17, ''), 'approved'), COALESCE($18, '[]'::jsonb), $19, COALESCE($20, '[]'::jsonb), $21
+			)
+			RETURNING *
+		), snapshot AS (` + fmt.Sprintf(revisionSnapshot, "created") + `)
+		SELECT id, active_from, created_at, usage_count, revision, review_status, rating FROM created
 	`
 
-	err := r.db.Pool.QueryRow(
+	err := r.db.Conn(ctx).QueryRow(
 		ctx,
 		query,
 		challenge.CategoryID,
@@ -47,12 +80,23 @@ func (r *ChallengeRepository) Create(ctx context.Context, challenge *models.Chal
 		challenge.AIGenerated,
 		"", // AI model version (can be populated later)
 		"", // Generation prompt hash (can be populated later)
+		challenge.ActiveFrom,
 		challenge.ActiveUntil,
+		challenge.IsActive,
+		challenge.EditedBy,
+		challenge.ReviewStatus,
+		challenge.ReviewFlags,
+		challenge.Explanation,
+		challenge.Sources,
+		models.DifficultyRating(float64(challenge.DifficultyTier)), // new challenges start at their tier
 	).Scan(
 		&challenge.ID,
-		&challenge.IsActive,
+		&challenge.ActiveFrom,
 		&challenge.CreatedAt,
 		&challenge.UsageCount,
+		&challenge.Revision,
+		&challenge.ReviewStatus,
+		&challenge.Rating,
 	)
 
 	if err != nil {
@@ -62,18 +106,275 @@ func (r *ChallengeRepository) Create(ctx context.Context, challenge *models.Chal
 	return nil
 }
 
+// Update saves an edited challenge as its next revision. Content,
+// schedule and activation are all written from challenge.
+func (r *ChallengeRepository) Update(ctx context.Context, challenge *models.Challenge) error {
+	query := `
+		WITH updated AS (
+			UPDATE challenges
+			SET category_id = $2,
+			    title = $3,
+			    description = $4,
+			    question_data = $5,
+			    correct_answer_hash = $6,
+			    difficulty_tier = $7,
+			    base_points = $8,
+			    time_limit_seconds = $9,
+			    is_active = $10,
+			    suspended_at = CASE WHEN $10 THEN NULL ELSE suspended_at END,
+			    active_from = COALESCE($11, active_from),
+			    active_until = $12,
+			    edited_by = $13,
+			    explanation = $14,
+			    sources = COALESCE($15, '[]'::jsonb),
+			    revision = revision + 1,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING *
+		), snapshot AS (` + fmt.Sprintf(revisionSnapshot, "updated") + `)
+		SELECT active_from, revision FROM updated
+	`
+
+	err := r.db.Conn(ctx).QueryRow(
+		ctx,
+		query,
+		challenge.ID,
+		challenge.CategoryID,
+		challenge.Title,
+		challenge.Description,
+		challenge.QuestionData,
+		challenge.CorrectAnswerHash,
+		challenge.DifficultyTier,
+		challenge.BasePoints,
+		challenge.TimeLimitSeconds,
+		challenge.IsActive,
+		challenge.ActiveFrom,
+		challenge.ActiveUntil,
+		challenge.EditedBy,
+		challenge.Explanation,
+		challenge.Sources,
+	).Scan(&challenge.ActiveFrom, &challenge.Revision)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errors.ErrChallengeNotFound
+		}
+		var pgErr *pgconn.PgError
+		if stderrors.As(err, &pgErr) && pgErr.ConstraintName == "challenges_category_id_fkey" {
+			return errors.ErrCategoryNotFound
+		}
+		return fmt.Errorf("failed to update challenge: %w", err)
+	}
+
+	return nil
+}
+
 // GetByID retrieves a challenge by ID
 func (r *ChallengeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Challenge, error) {
 	query := `
-		SELECT id, category_id, title, description, question_data, correct_answer_hash,
-		       difficulty_tier, base_points, time_limit_seconds, challenge_type,
-		       ai_generated, is_active, active_until, created_at, usage_count
+		SELECT ` + challengeColumns + `
+		FROM challenges
+		WHERE id = $1 AND is_active = true AND review_status = 'approved'
+	`
+
+	var challenge models.Challenge
+	err := scanChallenge(r.db.Conn(ctx).QueryRow(ctx, query, id), &challenge)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// GetByIDForUpdate retrieves a challenge in play, as GetByID does, and
+// locks its row until the caller's transaction ends
+func (r *ChallengeRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Challenge, error) {
+	query := `
+		SELECT ` + challengeColumns + `
 		FROM challenges
-		WHERE id = $1 AND is_active = true
+		WHERE id = $1 AND is_active = true AND review_status = 'approved'
+		FOR UPDATE
 	`
 
 	var challenge models.Challenge
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := scanChallenge(r.db.Conn(ctx).QueryRow(ctx, query, id), &challenge)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// GetForUpdate retrieves a challenge, active or not, and locks its row
+// until the caller's transaction ends
+func (r *ChallengeRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (*models.Challenge, error) {
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var challenge models.Challenge
+	err := scanChallenge(r.db.Conn(ctx).QueryRow(ctx, query, id), &challenge)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// GetAnyByID retrieves a challenge whether or not it is active
+func (r *ChallengeRepository) GetAnyByID(ctx context.Context, id uuid.UUID) (*models.Challenge, error) {
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges
+		WHERE id = $1
+	`
+
+	var challenge models.Challenge
+	err := scanChallenge(r.db.Conn(ctx).QueryRow(ctx, query, id), &challenge)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// List retrieves challenges, active or not, newest first
+func (r *ChallengeRepository) List(ctx context.Context, filter models.ChallengeFilter) ([]models.Challenge, error) {
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges
+		WHERE true
+	`
+
+	args := []interface{}{}
+	argPos := 1
+
+	if filter.CategoryID != nil {
+		query += fmt.Sprintf(" AND category_id = $%d", argPos)
+		args = append(args, *filter.CategoryID)
+		argPos++
+	}
+	if filter.ChallengeType != "" {
+		query += fmt.Sprintf(" AND challenge_type = $%d", argPos)
+		args = append(args, filter.ChallengeType)
+		argPos++
+	}
+	if filter.IsActive != nil {
+		query += fmt.Sprintf(" AND is_active = $%d", argPos)
+		args = append(args, *filter.IsActive)
+		argPos++
+	}
+	if filter.ReviewStatus != "" {
+		query += fmt.Sprintf(" AND review_status = $%d", argPos)
+		args = append(args, filter.ReviewStatus)
+		argPos++
+	}
+	if filter.DifficultyFlag != "" {
+		query += fmt.Sprintf(" AND difficulty_flag = $%d", argPos)
+		args = append(args, filter.DifficultyFlag)
+		argPos++
+	}
+
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []models.Challenge{}
+	for rows.Next() {
+		var challenge models.Challenge
+		err := scanChallenge(rows, &challenge)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, rows.Err()
+}
+
+// ListRevisions retrieves every revision of a challenge, latest first
+func (r *ChallengeRepository) ListRevisions(ctx context.Context, challengeID uuid.UUID) ([]models.ChallengeRevision, error) {
+	query := `
+		SELECT id, challenge_id, revision, category_id, title, description, question_data,
+		       correct_answer_hash, difficulty_tier, base_points, time_limit_seconds,
+		       challenge_type, is_active, active_from, active_until, edited_by, explanation,
+		       sources, created_at
+		FROM challenge_revisions
+		WHERE challenge_id = $1
+		ORDER BY revision DESC
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.ChallengeRevision{}
+	for rows.Next() {
+		var revision models.ChallengeRevision
+		if err := scanChallengeRevision(rows, &revision); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetRevision retrieves one revision of a challenge
+func (r *ChallengeRepository) GetRevision(ctx context.Context, challengeID uuid.UUID, revision int) (*models.ChallengeRevision, error) {
+	query := `
+		SELECT id, challenge_id, revision, category_id, title, description, question_data,
+		       correct_answer_hash, difficulty_tier, base_points, time_limit_seconds,
+		       challenge_type, is_active, active_from, active_until, edited_by, explanation,
+		       sources, created_at
+		FROM challenge_revisions
+		WHERE challenge_id = $1 AND revision = $2
+	`
+
+	var rev models.ChallengeRevision
+	err := scanChallengeRevision(r.db.Conn(ctx).QueryRow(ctx, query, challengeID, revision), &rev)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return &rev, nil
+}
+
+func scanChallenge(row pgx.Row, challenge *models.Challenge) error {
+	var empirical, solveRate, timeRatio *float64
+	var assessedAt *time.Time
+	difficulty := &models.ChallengeDifficulty{}
+
+	err := row.Scan(
 		&challenge.ID,
 		&challenge.CategoryID,
 		&challenge.Title,
@@ -86,19 +387,662 @@ func (r *ChallengeRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 		&challenge.ChallengeType,
 		&challenge.AIGenerated,
 		&challenge.IsActive,
+		&challenge.ActiveFrom,
 		&challenge.ActiveUntil,
+		&challenge.ResolvedAt,
 		&challenge.CreatedAt,
 		&challenge.UsageCount,
+		&challenge.Revision,
+		&challenge.EditedBy,
+		&challenge.ReviewStatus,
+		&challenge.ReviewFlags,
+		&challenge.ReviewedBy,
+		&challenge.ReviewedAt,
+		&challenge.ReviewReason,
+		&challenge.SuspendedAt,
+		&empirical,
+		&solveRate,
+		&timeRatio,
+		&difficulty.SampleSize,
+		&difficulty.Flag,
+		&assessedAt,
+		&difficulty.OriginalTier,
+		&challenge.Explanation,
+		&challenge.Sources,
+		&challenge.Rating,
+		&challenge.RatingAttempts,
+	)
+	if err != nil {
+		return err
+	}
+
+	challenge.Difficulty = nil
+	if assessedAt != nil && empirical != nil && solveRate != nil && timeRatio != nil {
+		difficulty.Empirical = *empirical
+		difficulty.ObservedTier = models.NearestTier(*empirical)
+		difficulty.SolveRate = *solveRate
+		difficulty.MedianTimeRatio = *timeRatio
+		difficulty.AssessedAt = *assessedAt
+		challenge.Difficulty = difficulty
+	}
+
+	return nil
+}
+
+func scanChallengeRevision(row pgx.Row, rev *models.ChallengeRevision) error {
+	return row.Scan(
+		&rev.ID,
+		&rev.ChallengeID,
+		&rev.Revision,
+		&rev.CategoryID,
+		&rev.Title,
+		&rev.Description,
+		&rev.QuestionData,
+		&rev.CorrectAnswerHash,
+		&rev.DifficultyTier,
+		&rev.BasePoints,
+		&rev.TimeLimitSeconds,
+		&rev.ChallengeType,
+		&rev.IsActive,
+		&rev.ActiveFrom,
+		&rev.ActiveUntil,
+		&rev.EditedBy,
+		&rev.Explanation,
+		&rev.Sources,
+		&rev.CreatedAt,
 	)
+}
+
+// Resolve records a prediction's outcome as a new revision and closes it
+// to new picks
+func (r *ChallengeRepository) Resolve(ctx context.Context, id uuid.UUID, answerHash string, editedBy uuid.UUID) (time.Time, error) {
+	query := `
+		WITH resolved AS (
+			UPDATE challenges
+			SET correct_answer_hash = $2,
+			    resolved_at = CURRENT_TIMESTAMP,
+			    active_until = LEAST(COALESCE(active_until, CURRENT_TIMESTAMP), CURRENT_TIMESTAMP),
+			    revision = revision + 1,
+			    edited_by = $3,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND resolved_at IS NULL
+			RETURNING *
+		), snapshot AS (` + fmt.Sprintf(revisionSnapshot, "resolved") + `)
+		SELECT resolved_at FROM resolved
+	`
 
+	var resolvedAt time.Time
+	err := r.db.Conn(ctx).QueryRow(ctx, query, id, answerHash, editedBy).Scan(&resolvedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.ErrChallengeNotFound
+			return time.Time{}, errors.ErrAlreadyResolved
 		}
-		return nil, fmt.Errorf("failed to get challenge: %w", err)
+		return time.Time{}, fmt.Errorf("failed to resolve challenge: %w", err)
 	}
 
-	return &challenge, nil
+	return resolvedAt, nil
+}
+
+// ScorePendingAttempts scores every pending attempt at a challenge against
+// answerHash in one statement. Correct attempts earn points; the rest earn
+// nothing. The scored attempts are returned.
+func (r *ChallengeRepository) ScorePendingAttempts(
+	ctx context.Context,
+	challengeID uuid.UUID,
+	answerHash string,
+	points int,
+) ([]models.ChallengeAttempt, error) {
+	query := `
+		UPDATE user_challenge_attempts
+		SET pending = false,
+		    is_correct = (answer_hash = $2),
+		    score = CASE WHEN answer_hash = $2 THEN 1 ELSE 0 END,
+		    points_earned = CASE WHEN answer_hash = $2 THEN $3 ELSE 0 END,
+		    challenge_revision = (SELECT revision FROM challenges WHERE id = $1)
+		WHERE challenge_id = $1 AND pending
+		RETURNING id, user_id, challenge_id, is_correct, score, points_earned,
+		          time_taken_seconds, challenge_revision, attempted_at
+	`
+
+	conn := r.db.Conn(ctx)
+	rows, err := conn.Query(ctx, query, challengeID, answerHash, points)
+	if err != nil {
+		return nil, fmt.Errorf("failed to score attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []models.ChallengeAttempt
+	correct := 0
+	for rows.Next() {
+		var attempt models.ChallengeAttempt
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.UserID,
+			&attempt.ChallengeID,
+			&attempt.IsCorrect,
+			&attempt.Score,
+			&attempt.PointsEarned,
+			&attempt.TimeTakenSeconds,
+			&attempt.ChallengeRevision,
+			&attempt.AttemptedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attempt: %w", err)
+		}
+		if attempt.IsCorrect {
+			correct++
+		}
+		attempts = append(attempts, attempt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to score attempts: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, `
+		UPDATE challenges
+		SET correct_count = correct_count + $2,
+		    incorrect_count = incorrect_count + $3
+		WHERE id = $1
+	`, challengeID, correct, len(attempts)-correct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update challenge counts: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// Suspend takes a challenge out of play pending a review of its reports,
+// recording the change as a new revision
+func (r *ChallengeRepository) Suspend(ctx context.Context, id uuid.UUID) error {
+	query := `
+		WITH suspended AS (
+			UPDATE challenges
+			SET is_active = false,
+			    suspended_at = CURRENT_TIMESTAMP,
+			    edited_by = NULL,
+			    revision = revision + 1,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND is_active
+			RETURNING *
+		), snapshot AS (` + fmt.Sprintf(revisionSnapshot, "suspended") + `)
+		SELECT id FROM suspended
+	`
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to suspend challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetAttemptsForRegrade retrieves and locks every attempt at a challenge
+func (r *ChallengeRepository) GetAttemptsForRegrade(ctx context.Context, challengeID uuid.UUID) ([]models.ChallengeAttempt, error) {
+	query := `
+		SELECT id, user_id, challenge_id, is_correct, score, pending, voided, points_earned,
+		       time_taken_seconds, answer_hash, challenge_revision, attempted_at
+		FROM user_challenge_attempts
+		WHERE challenge_id = $1
+		ORDER BY attempted_at
+		FOR UPDATE
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []models.ChallengeAttempt
+	for rows.Next() {
+		var attempt models.ChallengeAttempt
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.UserID,
+			&attempt.ChallengeID,
+			&attempt.IsCorrect,
+			&attempt.Score,
+			&attempt.Pending,
+			&attempt.Voided,
+			&attempt.PointsEarned,
+			&attempt.TimeTakenSeconds,
+			&attempt.AnswerHash,
+			&attempt.ChallengeRevision,
+			&attempt.AttemptedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attempt: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+// ApplyRegrade writes regraded attempts, marking them as graded against
+// the challenge's current revision, and recounts the challenge's correct
+// and incorrect answers
+func (r *ChallengeRepository) ApplyRegrade(ctx context.Context, challengeID uuid.UUID, regrades []models.AttemptRegrade) error {
+	ids := make([]uuid.UUID, len(regrades))
+	correct := make([]bool, len(regrades))
+	scores := make([]float64, len(regrades))
+	points := make([]int, len(regrades))
+	voided := make([]bool, len(regrades))
+	for i, regrade := range regrades {
+		ids[i] = regrade.AttemptID
+		correct[i] = regrade.CorrectAfter
+		scores[i] = regrade.ScoreAfter
+		points[i] = regrade.PointsAfter
+		voided[i] = regrade.Voided
+	}
+
+	conn := r.db.Conn(ctx)
+	_, err := conn.Exec(ctx, `
+		UPDATE user_challenge_attempts uca
+		SET is_correct = r.is_correct,
+		    score = r.score,
+		    points_earned = r.points,
+		    pending = false,
+		    voided = r.voided,
+		    challenge_revision = (SELECT revision FROM challenges WHERE id = $1)
+		FROM unnest($2::uuid[], $3::bool[], $4::float8[], $5::int[], $6::bool[])
+		     AS r(id, is_correct, score, points, voided)
+		WHERE uca.id = r.id AND uca.challenge_id = $1
+	`, challengeID, ids, correct, scores, points, voided)
+	if err != nil {
+		return fmt.Errorf("failed to regrade attempts: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, `
+		UPDATE challenges c
+		SET correct_count = counts.correct,
+		    incorrect_count = counts.incorrect
+		FROM (
+			SELECT COUNT(*) FILTER (WHERE is_correct) AS correct,
+			       COUNT(*) FILTER (WHERE NOT is_correct AND NOT pending) AS incorrect
+			FROM user_challenge_attempts
+			WHERE challenge_id = $1 AND NOT voided
+		) counts
+		WHERE c.id = $1
+	`, challengeID)
+	if err != nil {
+		return fmt.Errorf("failed to update challenge counts: %w", err)
+	}
+
+	return nil
+}
+
+// ListPendingReview retrieves challenges awaiting review, oldest first
+func (r *ChallengeRepository) ListPendingReview(ctx context.Context, limit, offset int) ([]models.Challenge, error) {
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges
+		WHERE review_status = 'pending_review'
+		ORDER BY created_at ASC, id
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review queue: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []models.Challenge{}
+	for rows.Next() {
+		var challenge models.Challenge
+		if err := scanChallenge(rows, &challenge); err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, rows.Err()
+}
+
+// SetReviewStatus records a moderator's decision on a challenge awaiting
+// review. It returns errors.ErrNotPendingReview if the challenge has
+// already been reviewed.
+func (r *ChallengeRepository) SetReviewStatus(
+	ctx context.Context,
+	challenge *models.Challenge,
+	status string,
+	reviewerID uuid.UUID,
+	reason *string,
+) error {
+	query := `
+		UPDATE challenges
+		SET review_status = $2,
+		    reviewed_by = $3,
+		    reviewed_at = CURRENT_TIMESTAMP,
+		    review_reason = $4,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND review_status = 'pending_review'
+		RETURNING review_status, reviewed_by, reviewed_at, review_reason
+	`
+
+	err := r.db.Conn(ctx).QueryRow(ctx, query, challenge.ID, status, reviewerID, reason).Scan(
+		&challenge.ReviewStatus,
+		&challenge.ReviewedBy,
+		&challenge.ReviewedAt,
+		&challenge.ReviewReason,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errors.ErrNotPendingReview
+		}
+		return fmt.Errorf("failed to record review: %w", err)
+	}
+
+	return nil
+}
+
+// GetReviewBacklog summarizes the review queue and recent decisions
+func (r *ChallengeRepository) GetReviewBacklog(ctx context.Context) (*models.ReviewBacklog, error) {
+	conn := r.db.Conn(ctx)
+	backlog := &models.ReviewBacklog{PendingByCategory: map[string]int{}}
+
+	err := conn.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE review_status = 'pending_review'),
+		       MIN(created_at) FILTER (WHERE review_status = 'pending_review'),
+		       COUNT(*) FILTER (WHERE review_status = 'approved' AND reviewed_at > CURRENT_TIMESTAMP - INTERVAL '24 hours'),
+		       COUNT(*) FILTER (WHERE review_status = 'rejected' AND reviewed_at > CURRENT_TIMESTAMP - INTERVAL '24 hours'),
+		       COALESCE(EXTRACT(EPOCH FROM AVG(reviewed_at - created_at)
+		           FILTER (WHERE reviewed_at > CURRENT_TIMESTAMP - INTERVAL '7 days')), 0)::float8
+		FROM challenges
+		WHERE review_status = 'pending_review' OR reviewed_at > CURRENT_TIMESTAMP - INTERVAL '7 days'
+	`).Scan(
+		&backlog.Pending,
+		&backlog.OldestPendingAt,
+		&backlog.ApprovedLast24h,
+		&backlog.RejectedLast24h,
+		&backlog.AvgWaitSeconds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review backlog: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, `
+		SELECT cat.slug, COUNT(*)
+		FROM challenges c
+		JOIN categories cat ON cat.id = c.category_id
+		WHERE c.review_status = 'pending_review'
+		GROUP BY cat.slug
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review backlog: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var slug string
+		var count int
+		if err := rows.Scan(&slug, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan review backlog: %w", err)
+		}
+		backlog.PendingByCategory[slug] = count
+	}
+
+	return backlog, rows.Err()
+}
+
+// GetGenerationCounts counts stored AI challenges per category and tier
+func (r *ChallengeRepository) GetGenerationCounts(
+	ctx context.Context,
+	filter models.GenerationStatsFilter,
+) ([]models.CategoryGeneration, error) {
+	where, args := generationStatsWhere(filter, "c")
+	query := `
+		SELECT c.category_id, cat.name, c.difficulty_tier,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE c.is_active = true AND c.review_status = 'approved'),
+		       COUNT(*) FILTER (WHERE c.review_status = 'pending_review')
+		FROM challenges c
+		JOIN categories cat ON cat.id = c.category_id
+		WHERE c.ai_generated = true` + where + `
+		GROUP BY c.category_id, cat.name, c.difficulty_tier
+		ORDER BY cat.name, c.difficulty_tier
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count generated challenges: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.CategoryGeneration{}
+	for rows.Next() {
+		var count models.CategoryGeneration
+		err := rows.Scan(
+			&count.CategoryID,
+			&count.CategoryName,
+			&count.DifficultyTier,
+			&count.Generated,
+			&count.Active,
+			&count.PendingReview,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan generated challenge count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// GetGeneratedUsage totals how players have used stored AI challenges
+func (r *ChallengeRepository) GetGeneratedUsage(
+	ctx context.Context,
+	filter models.GenerationStatsFilter,
+) (*models.GeneratedUsage, error) {
+	where, args := generationStatsWhere(filter, "")
+	query := `
+		SELECT COALESCE(SUM(usage_count), 0),
+		       COALESCE(AVG(usage_count), 0)::float8,
+		       COALESCE(SUM(correct_count), 0),
+		       COALESCE(SUM(incorrect_count), 0)
+		FROM challenges
+		WHERE ai_generated = true` + where
+
+	usage := &models.GeneratedUsage{}
+	err := r.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&usage.TotalUsage,
+		&usage.AverageUsage,
+		&usage.CorrectCount,
+		&usage.IncorrectCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated challenge usage: %w", err)
+	}
+
+	if answered := usage.CorrectCount + usage.IncorrectCount; answered > 0 {
+		usage.CorrectRatio = float64(usage.CorrectCount) / float64(answered)
+	}
+
+	return usage, nil
+}
+
+// difficultyCalibrationLock is the advisory lock key held while difficulty
+// is calibrated, so only one process calibrates at a time
+const difficultyCalibrationLock = 7261001
+
+// TryLockDifficultyCalibration takes the calibration lock for the rest of
+// the current transaction, reporting false if another process holds it
+func (r *ChallengeRepository) TryLockDifficultyCalibration(ctx context.Context) (bool, error) {
+	var locked bool
+	err := r.db.Conn(ctx).QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, difficultyCalibrationLock).Scan(&locked)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock difficulty calibration: %w", err)
+	}
+	return locked, nil
+}
+
+// GetDifficultySamples summarizes the scored attempts at each challenge in
+// play with at least minAttempts of them. Predictions are left out; their
+// outcome says nothing about the challenge's difficulty.
+func (r *ChallengeRepository) GetDifficultySamples(ctx context.Context, minAttempts int) ([]models.DifficultySample, error) {
+	query := `
+		SELECT c.id, c.challenge_type, c.difficulty_tier,
+		       CASE WHEN jsonb_typeof(c.question_data->'options') = 'array'
+		            THEN jsonb_array_length(c.question_data->'options') ELSE 0 END,
+		       COUNT(*),
+		       AVG(a.score)::float8,
+		       COALESCE(percentile_cont(0.5) WITHIN GROUP (
+		           ORDER BY LEAST(a.time_taken_seconds::float8 / COALESCE(NULLIF(c.time_limit_seconds, 0), 60), 1)
+		       ), 0.5)::float8
+		FROM challenges c
+		JOIN user_challenge_attempts a ON a.challenge_id = c.id AND NOT a.pending AND NOT a.voided
+		WHERE c.is_active = true
+		  AND c.review_status = 'approved'
+		  AND c.challenge_type <> 'prediction'
+		GROUP BY c.id
+		HAVING COUNT(*) >= $1
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, minAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query difficulty samples: %w", err)
+	}
+	defer rows.Close()
+
+	samples := []models.DifficultySample{}
+	for rows.Next() {
+		var sample models.DifficultySample
+		err := rows.Scan(
+			&sample.ChallengeID,
+			&sample.ChallengeType,
+			&sample.DifficultyTier,
+			&sample.OptionCount,
+			&sample.Attempts,
+			&sample.SolveRate,
+			&sample.MedianTimeRatio,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan difficulty sample: %w", err)
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples, rows.Err()
+}
+
+// SaveDifficultyAssessments stores estimated difficulties. They describe
+// how players did rather than the challenge itself, so no revision is made.
+func (r *ChallengeRepository) SaveDifficultyAssessments(ctx context.Context, assessments []models.DifficultyAssessment) error {
+	if len(assessments) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(assessments))
+	empirical := make([]float64, len(assessments))
+	solveRates := make([]float64, len(assessments))
+	timeRatios := make([]float64, len(assessments))
+	samples := make([]int, len(assessments))
+	flags := make([]*string, len(assessments))
+	for i, a := range assessments {
+		ids[i] = a.ChallengeID
+		empirical[i] = a.Empirical
+		solveRates[i] = a.SolveRate
+		timeRatios[i] = a.MedianTimeRatio
+		samples[i] = a.SampleSize
+		flags[i] = a.Flag
+	}
+
+	_, err := r.db.Conn(ctx).Exec(ctx, `
+		UPDATE challenges c
+		SET empirical_difficulty = d.empirical,
+		    solve_rate = d.solve_rate,
+		    median_time_ratio = d.time_ratio,
+		    difficulty_sample_size = d.samples,
+		    difficulty_flag = d.flag,
+		    difficulty_assessed_at = CURRENT_TIMESTAMP
+		FROM unnest($1::uuid[], $2::float8[], $3::float8[], $4::float8[], $5::int[], $6::text[])
+			AS d(id, empirical, solve_rate, time_ratio, samples, flag)
+		WHERE c.id = d.id
+	`, ids, empirical, solveRates, timeRatios, samples, flags)
+	if err != nil {
+		return fmt.Errorf("failed to save difficulty assessments: %w", err)
+	}
+
+	return nil
+}
+
+// Retier moves a challenge to the tier its observed difficulty puts it in,
+// recording the change as a new revision. The tier it was created with is
+// kept the first time.
+func (r *ChallengeRepository) Retier(ctx context.Context, id uuid.UUID, tier int) error {
+	query := `
+		WITH retiered AS (
+			UPDATE challenges
+			SET original_difficulty_tier = COALESCE(original_difficulty_tier, difficulty_tier),
+			    difficulty_tier = $2,
+			    difficulty_flag = NULL,
+			    edited_by = NULL,
+			    revision = revision + 1,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND difficulty_tier <> $2
+			RETURNING *
+		), snapshot AS (` + fmt.Sprintf(revisionSnapshot, "retiered") + `)
+		SELECT id FROM retiered
+	`
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, id, tier); err != nil {
+		return fmt.Errorf("failed to re-tier challenge: %w", err)
+	}
+
+	return nil
+}
+
+// AdjustRating moves a challenge's rating by delta after an attempt.
+// Rating is not challenge content, so no revision is recorded.
+func (r *ChallengeRepository) AdjustRating(ctx context.Context, id uuid.UUID, delta float64) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `
+		UPDATE challenges
+		SET rating = rating + $2,
+		    rating_attempts = rating_attempts + 1
+		WHERE id = $1
+	`, id, delta)
+	if err != nil {
+		return fmt.Errorf("failed to adjust challenge rating: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceRatings puts every challenge back at the rating of its tier,
+// then sets the ratings recalculated for the challenges in ratings
+func (r *ChallengeRepository) ReplaceRatings(ctx context.Context, ratings map[uuid.UUID]models.SkillRating) error {
+	conn := r.db.Conn(ctx)
+
+	_, err := conn.Exec(ctx, `
+		UPDATE challenges
+		SET rating = $1 + (difficulty_tier - 1) * $2,
+		    rating_attempts = 0
+	`, models.TierOneRating, models.TierRatingStep)
+	if err != nil {
+		return fmt.Errorf("failed to reset challenge ratings: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(ratings))
+	values := make([]float64, 0, len(ratings))
+	attempts := make([]int, 0, len(ratings))
+	for id, rating := range ratings {
+		ids = append(ids, id)
+		values = append(values, rating.Rating)
+		attempts = append(attempts, rating.Attempts)
+	}
+
+	_, err = conn.Exec(ctx, `
+		UPDATE challenges c
+		SET rating = r.rating,
+		    rating_attempts = r.attempts
+		FROM unnest($1::uuid[], $2::float8[], $3::int[]) AS r(id, rating, attempts)
+		WHERE c.id = r.id
+	`, ids, values, attempts)
+	if err != nil {
+		return fmt.Errorf("failed to save challenge ratings: %w", err)
+	}
+
+	return nil
 }
 
 // GetByCategoryAndDifficulty retrieves challenges by category and difficulty
@@ -109,19 +1053,19 @@ func (r *ChallengeRepository) GetByCategoryAndDifficulty(
 	limit int,
 ) ([]models.Challenge, error) {
 	query := `
-		SELECT id, category_id, title, description, question_data, correct_answer_hash,
-		       difficulty_tier, base_points, time_limit_seconds, challenge_type,
-		       ai_generated, is_active, active_until, created_at, usage_count
+		SELECT ` + challengeColumns + `
 		FROM challenges
 		WHERE category_id = $1 
 		  AND difficulty_tier = $2
 		  AND is_active = true
+		  AND review_status = 'approved'
+		  AND (active_from IS NULL OR active_from <= CURRENT_TIMESTAMP)
 		  AND (active_until IS NULL OR active_until > CURRENT_TIMESTAMP)
 		ORDER BY RANDOM()
 		LIMIT $3
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, categoryID, difficultyTier, limit)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, categoryID, difficultyTier, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query challenges: %w", err)
 	}
@@ -130,23 +1074,7 @@ func (r *ChallengeRepository) GetByCategoryAndDifficulty(
 	var challenges []models.Challenge
 	for rows.Next() {
 		var challenge models.Challenge
-		err := rows.Scan(
-			&challenge.ID,
-			&challenge.CategoryID,
-			&challenge.Title,
-			&challenge.Description,
-			&challenge.QuestionData,
-			&challenge.CorrectAnswerHash,
-			&challenge.DifficultyTier,
-			&challenge.BasePoints,
-			&challenge.TimeLimitSeconds,
-			&challenge.ChallengeType,
-			&challenge.AIGenerated,
-			&challenge.IsActive,
-			&challenge.ActiveUntil,
-			&challenge.CreatedAt,
-			&challenge.UsageCount,
-		)
+		err := scanChallenge(rows, &challenge)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan challenge: %w", err)
 		}
@@ -156,24 +1084,28 @@ func (r *ChallengeRepository) GetByCategoryAndDifficulty(
 	return challenges, nil
 }
 
-// GetAvailableChallengesForUser retrieves challenges user hasn't attempted yet
+// GetAvailableChallengesForUser retrieves challenges user hasn't attempted
+// yet. With a target difficulty, those nearest it come first.
 func (r *ChallengeRepository) GetAvailableChallengesForUser(
 	ctx context.Context,
 	userID uuid.UUID,
 	categoryID uuid.UUID,
 	difficultyTier *int,
+	targetDifficulty *float64,
 	limit int,
 ) ([]models.Challenge, error) {
 	query := `
-		SELECT c.id, c.category_id, c.title, c.description, c.question_data, c.correct_answer_hash,
-		       c.difficulty_tier, c.base_points, c.time_limit_seconds, c.challenge_type,
-		       c.ai_generated, c.is_active, c.active_until, c.created_at, c.usage_count
+		SELECT ` + challengeColumns + `
 		FROM challenges c
-		LEFT JOIN user_challenge_attempts uca ON c.id = uca.challenge_id AND uca.user_id = $1
 		WHERE c.category_id = $2
 		  AND c.is_active = true
+		  AND c.review_status = 'approved'
+		  AND (c.active_from IS NULL OR c.active_from <= CURRENT_TIMESTAMP)
 		  AND (c.active_until IS NULL OR c.active_until > CURRENT_TIMESTAMP)
-		  AND uca.id IS NULL
+		  AND NOT EXISTS (
+		      SELECT 1 FROM user_challenge_attempts uca
+		      WHERE uca.challenge_id = c.id AND uca.user_id = $1
+		  )
 	`
 
 	args := []interface{}{userID, categoryID}
@@ -185,10 +1117,20 @@ func (r *ChallengeRepository) GetAvailableChallengesForUser(
 		argPos++
 	}
 
-	query += fmt.Sprintf(" ORDER BY RANDOM() LIMIT $%d", argPos)
+	orderBy := "RANDOM()"
+	if targetDifficulty != nil {
+		// Stay within a tier of the target, nearest first, using observed
+		// difficulty where it has been assessed
+		query += fmt.Sprintf(" AND ABS(c.difficulty_tier - $%d::float8) <= 1", argPos)
+		orderBy = fmt.Sprintf("ROUND(ABS(COALESCE(c.empirical_difficulty, c.difficulty_tier) - $%d::float8)), RANDOM()", argPos)
+		args = append(args, *targetDifficulty)
+		argPos++
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", orderBy, argPos)
 	args = append(args, limit)
 
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query challenges: %w", err)
 	}
@@ -197,23 +1139,7 @@ func (r *ChallengeRepository) GetAvailableChallengesForUser(
 	var challenges []models.Challenge
 	for rows.Next() {
 		var challenge models.Challenge
-		err := rows.Scan(
-			&challenge.ID,
-			&challenge.CategoryID,
-			&challenge.Title,
-			&challenge.Description,
-			&challenge.QuestionData,
-			&challenge.CorrectAnswerHash,
-			&challenge.DifficultyTier,
-			&challenge.BasePoints,
-			&challenge.TimeLimitSeconds,
-			&challenge.ChallengeType,
-			&challenge.AIGenerated,
-			&challenge.IsActive,
-			&challenge.ActiveUntil,
-			&challenge.CreatedAt,
-			&challenge.UsageCount,
-		)
+		err := scanChallenge(rows, &challenge)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan challenge: %w", err)
 		}
@@ -223,43 +1149,139 @@ func (r *ChallengeRepository) GetAvailableChallengesForUser(
 	return challenges, nil
 }
 
+// StartSession opens an attempt session for a user and challenge.
+// If one is still running it is returned unchanged, so opening a challenge
+// again never restarts the clock; one that ran out unanswered starts
+// over. allowedSeconds is nil for untimed challenges.
+func (r *ChallengeRepository) StartSession(
+	ctx context.Context,
+	userID, challengeID uuid.UUID,
+	allowedSeconds *int,
+) (*models.ChallengeSession, error) {
+	query := `
+		INSERT INTO challenge_sessions (user_id, challenge_id, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP + make_interval(secs => $3::int))
+		ON CONFLICT (user_id, challenge_id) DO UPDATE
+		SET started_at = CASE WHEN challenge_sessions.expires_at < CURRENT_TIMESTAMP
+		                      THEN CURRENT_TIMESTAMP ELSE challenge_sessions.started_at END,
+		    expires_at = CASE WHEN challenge_sessions.expires_at < CURRENT_TIMESTAMP
+		                      THEN EXCLUDED.expires_at ELSE challenge_sessions.expires_at END
+		RETURNING id, user_id, challenge_id, started_at, expires_at
+	`
+
+	var session models.ChallengeSession
+	err := r.db.Conn(ctx).QueryRow(ctx, query, userID, challengeID, allowedSeconds).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.ChallengeID,
+		&session.StartedAt,
+		&session.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start challenge session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// GetSession retrieves an attempt session by ID
+func (r *ChallengeRepository) GetSession(ctx context.Context, id uuid.UUID) (*models.ChallengeSession, error) {
+	query := `
+		SELECT id, user_id, challenge_id, started_at, expires_at
+		FROM challenge_sessions
+		WHERE id = $1
+	`
+
+	var session models.ChallengeSession
+	err := r.db.Conn(ctx).QueryRow(ctx, query, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.ChallengeID,
+		&session.StartedAt,
+		&session.ExpiresAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrInvalidSession
+		}
+		return nil, fmt.Errorf("failed to get challenge session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// GetSessionFor retrieves a user's attempt session for a challenge
+func (r *ChallengeRepository) GetSessionFor(ctx context.Context, userID, challengeID uuid.UUID) (*models.ChallengeSession, error) {
+	query := `
+		SELECT id, user_id, challenge_id, started_at, expires_at
+		FROM challenge_sessions
+		WHERE user_id = $1 AND challenge_id = $2
+	`
+
+	var session models.ChallengeSession
+	err := r.db.Conn(ctx).QueryRow(ctx, query, userID, challengeID).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.ChallengeID,
+		&session.StartedAt,
+		&session.ExpiresAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrInvalidSession
+		}
+		return nil, fmt.Errorf("failed to get challenge session: %w", err)
+	}
+
+	return &session, nil
+}
+
 // RecordAttempt records a user's challenge attempt
 func (r *ChallengeRepository) RecordAttempt(ctx context.Context, attempt *models.ChallengeAttempt) error {
 	query := `
 		INSERT INTO user_challenge_attempts (
-			user_id, challenge_id, is_correct, points_earned, 
-			time_taken_seconds, answer_hash
-		) VALUES ($1, $2, $3, $4, $5, $6)
+			user_id, challenge_id, is_correct, score, points_earned, 
+			time_taken_seconds, answer_hash, pending, challenge_revision
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, attempted_at
 	`
 
-	err := r.db.Pool.QueryRow(
+	conn := r.db.Conn(ctx)
+	err := conn.QueryRow(
 		ctx,
 		query,
 		attempt.UserID,
 		attempt.ChallengeID,
 		attempt.IsCorrect,
+		attempt.Score,
 		attempt.PointsEarned,
 		attempt.TimeTakenSeconds,
 		attempt.AnswerHash,
+		attempt.Pending,
+		attempt.ChallengeRevision,
 	).Scan(&attempt.ID, &attempt.AttemptedAt)
 
 	if err != nil {
 		// Check for duplicate attempt
-		if err.Error() == `duplicate key value violates unique constraint "unique_user_challenge"` {
+		var pgErr *pgconn.PgError
+		if stderrors.As(err, &pgErr) && pgErr.ConstraintName == "unique_user_challenge" {
 			return errors.ErrAlreadyAttempted
 		}
 		return fmt.Errorf("failed to record attempt: %w", err)
 	}
 
-	// Increment usage count
-	_, _ = r.db.Pool.Exec(ctx, `
-		UPDATE challenges 
+	// Count the attempt; pending attempts are counted as correct or
+	// incorrect when they are scored
+	_, err = conn.Exec(ctx, `
+		UPDATE challenges
 		SET usage_count = usage_count + 1,
-		    correct_count = correct_count + CASE WHEN $2 THEN 1 ELSE 0 END,
-		    incorrect_count = incorrect_count + CASE WHEN $2 THEN 0 ELSE 1 END
+		    correct_count = correct_count + CASE WHEN $3 THEN 0 WHEN $2 THEN 1 ELSE 0 END,
+		    incorrect_count = incorrect_count + CASE WHEN $3 OR $2 THEN 0 ELSE 1 END
 		WHERE id = $1
-	`, attempt.ChallengeID, attempt.IsCorrect)
+	`, attempt.ChallengeID, attempt.IsCorrect, attempt.Pending)
+	if err != nil {
+		return fmt.Errorf("failed to update challenge counts: %w", err)
+	}
 
 	return nil
 }
@@ -274,7 +1296,7 @@ func (r *ChallengeRepository) HasUserAttempted(ctx context.Context, userID, chal
 	`
 
 	var exists bool
-	err := r.db.Pool.QueryRow(ctx, query, userID, challengeID).Scan(&exists)
+	err := r.db.Conn(ctx).QueryRow(ctx, query, userID, challengeID).Scan(&exists)
 	return exists, err
 }
 
@@ -286,20 +1308,41 @@ func (r *ChallengeRepository) GetUserAttemptCount(ctx context.Context, userID uu
 	`
 
 	var count int
-	err := r.db.Pool.QueryRow(ctx, query, userID, since).Scan(&count)
+	err := r.db.Conn(ctx).QueryRow(ctx, query, userID, since).Scan(&count)
 	return count, err
 }
 
+// CountPool counts a category tier's challenges that are playable now or
+// waiting for review, the stock automatic generation keeps topped up
+func (r *ChallengeRepository) CountPool(ctx context.Context, categoryID uuid.UUID, tier int) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM challenges
+		WHERE category_id = $1
+		  AND difficulty_tier = $2
+		  AND is_active = true
+		  AND review_status IN ('approved', 'pending_review')
+		  AND (active_until IS NULL OR active_until > CURRENT_TIMESTAMP)
+	`
+
+	var count int
+	err := r.db.Conn(ctx).QueryRow(ctx, query, categoryID, tier).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count challenge pool: %w", err)
+	}
+	return count, nil
+}
+
 // GetAvailableDifficulties returns available difficulty tiers for a category
 func (r *ChallengeRepository) GetAvailableDifficulties(ctx context.Context, categoryID uuid.UUID) ([]int, error) {
 	query := `
 		SELECT DISTINCT difficulty_tier 
 		FROM challenges
-		WHERE category_id = $1 AND is_active = true
+		WHERE category_id = $1 AND is_active = true AND review_status = 'approved'
 		ORDER BY difficulty_tier ASC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, categoryID)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, categoryID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query difficulties: %w", err)
 	}
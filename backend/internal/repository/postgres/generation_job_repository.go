@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// generationJobColumns is the column list scanned by scanGenerationJob
+const generationJobColumns = `
+	id, category_id, difficulty_tier, challenge_type,
+	requested_count, generated_count, failed_count, challenge_ids,
+	status, source, requested_by, attempts, max_attempts, last_error,
+	run_at, locked_at, finished_at, created_at, updated_at
+`
+
+// GenerationJobRepository handles the AI generation job queue
+type GenerationJobRepository struct {
+	db *DB
+}
+
+// NewGenerationJobRepository creates a new GenerationJobRepository
+func NewGenerationJobRepository(db *DB) *GenerationJobRepository {
+	return &GenerationJobRepository{db: db}
+}
+
+// Enqueue adds a job to the queue. If a pending or running job already
+// exists for the same category, tier and type, the request is merged into
+// it instead: its target is raised so that it produces at least
+// job.RequestedCount more challenges. job is populated with the stored row
+// and merged reports whether an existing job was reused.
+func (r *GenerationJobRepository) Enqueue(ctx context.Context, job *models.GenerationJob) (merged bool, err error) {
+	query := `
+		INSERT INTO generation_jobs (
+			category_id, difficulty_tier, challenge_type, requested_count,
+			source, requested_by, max_attempts
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (category_id, difficulty_tier, challenge_type)
+			WHERE status IN ('pending', 'running')
+		DO UPDATE SET requested_count = GREATEST(
+			generation_jobs.requested_count,
+			generation_jobs.generated_count + EXCLUDED.requested_count
+		)
+		RETURNING ` + generationJobColumns + `, (xmax <> 0)
+	`
+
+	row := r.db.Conn(ctx).QueryRow(
+		ctx,
+		query,
+		job.CategoryID,
+		job.DifficultyTier,
+		job.ChallengeType,
+		job.RequestedCount,
+		job.Source,
+		job.RequestedBy,
+		job.MaxAttempts,
+	)
+
+	if err := scanGenerationJob(row, job, &merged); err != nil {
+		var pgErr *pgconn.PgError
+		if stderrors.As(err, &pgErr) && pgErr.ConstraintName == "generation_jobs_category_id_fkey" {
+			return false, errors.ErrCategoryNotFound
+		}
+		return false, fmt.Errorf("failed to enqueue generation job: %w", err)
+	}
+
+	return merged, nil
+}
+
+// ClaimNext marks the oldest runnable pending job as running and returns
+// it, or nil when there is nothing to do. SKIP LOCKED lets several workers
+// and API replicas poll the same queue without handing out a job twice.
+func (r *GenerationJobRepository) ClaimNext(ctx context.Context) (*models.GenerationJob, error) {
+	query := `
+		UPDATE generation_jobs
+		SET status = 'running', attempts = attempts + 1, locked_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM generation_jobs
+			WHERE status = 'pending' AND run_at <= CURRENT_TIMESTAMP
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + generationJobColumns
+
+	var job models.GenerationJob
+	err := scanGenerationJob(r.db.Conn(ctx).QueryRow(ctx, query), &job)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim generation job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// RecordResult records one generation outcome on a running job: a
+// challenge ID on success, nil on failure. It also refreshes the job's
+// lock, and returns the job's current state so a worker sees targets
+// raised by merged requests.
+func (r *GenerationJobRepository) RecordResult(ctx context.Context, id uuid.UUID, challengeID *uuid.UUID) (*models.GenerationJob, error) {
+	query := `
+		UPDATE generation_jobs
+		SET generated_count = generated_count + CASE WHEN $2::uuid IS NULL THEN 0 ELSE 1 END,
+		    failed_count = failed_count + CASE WHEN $2::uuid IS NULL THEN 1 ELSE 0 END,
+		    challenge_ids = CASE WHEN $2::uuid IS NULL THEN challenge_ids ELSE array_append(challenge_ids, $2::uuid) END,
+		    locked_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + generationJobColumns
+
+	var job models.GenerationJob
+	err := scanGenerationJob(r.db.Conn(ctx).QueryRow(ctx, query, id, challengeID), &job)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrJobNotFound
+		}
+		return nil, fmt.Errorf("failed to record generation result: %w", err)
+	}
+
+	return &job, nil
+}
+
+// Complete marks a running job as succeeded. It returns false, leaving the
+// job running, if a merged request has raised its target in the meantime.
+func (r *GenerationJobRepository) Complete(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE generation_jobs
+		SET status = 'succeeded', locked_at = NULL, finished_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND generated_count >= requested_count
+	`
+
+	result, err := r.db.Conn(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete generation job: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// Retry returns a running job to the queue to be picked up again at runAt
+func (r *GenerationJobRepository) Retry(ctx context.Context, id uuid.UUID, lastError string, runAt time.Time) error {
+	query := `
+		UPDATE generation_jobs
+		SET status = 'pending', locked_at = NULL, last_error = $2, run_at = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, id, lastError, runAt); err != nil {
+		return fmt.Errorf("failed to reschedule generation job: %w", err)
+	}
+
+	return nil
+}
+
+// Fail marks a running job as permanently failed
+func (r *GenerationJobRepository) Fail(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE generation_jobs
+		SET status = 'failed', locked_at = NULL, last_error = $2, finished_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, id, lastError); err != nil {
+		return fmt.Errorf("failed to fail generation job: %w", err)
+	}
+
+	return nil
+}
+
+// Release hands a running job back to the queue without counting the
+// attempt, for when a worker stops before finishing it
+func (r *GenerationJobRepository) Release(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE generation_jobs
+		SET status = 'pending', locked_at = NULL, attempts = GREATEST(attempts - 1, 0)
+		WHERE id = $1 AND status = 'running'
+	`
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to release generation job: %w", err)
+	}
+
+	return nil
+}
+
+// RequeueStale returns running jobs whose lock has not been refreshed
+// since before cutoff to the queue. Such jobs belong to a worker that died
+// without releasing them.
+func (r *GenerationJobRepository) RequeueStale(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		UPDATE generation_jobs
+		SET status = 'pending', locked_at = NULL, run_at = CURRENT_TIMESTAMP
+		WHERE status = 'running' AND locked_at < $1
+	`
+
+	result, err := r.db.Conn(ctx).Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale generation jobs: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetByID retrieves a generation job
+func (r *GenerationJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.GenerationJob, error) {
+	query := `SELECT ` + generationJobColumns + ` FROM generation_jobs WHERE id = $1`
+
+	var job models.GenerationJob
+	err := scanGenerationJob(r.db.Conn(ctx).QueryRow(ctx, query, id), &job)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get generation job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// List returns the most recent jobs, optionally filtered by status
+func (r *GenerationJobRepository) List(ctx context.Context, status string, limit int) ([]models.GenerationJob, error) {
+	query := `
+		SELECT ` + generationJobColumns + `
+		FROM generation_jobs
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list generation jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.GenerationJob{}
+	for rows.Next() {
+		var job models.GenerationJob
+		if err := scanGenerationJob(rows, &job); err != nil {
+			return nil, fmt.Errorf("failed to scan generation job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// scanGenerationJob scans generationJobColumns into job, followed by any
+// extra destinations selected after them
+func scanGenerationJob(row pgx.Row, job *models.GenerationJob, extra ...interface{}) error {
+	dest := []interface{}{
+		&job.ID,
+		&job.CategoryID,
+		&job.DifficultyTier,
+		&job.ChallengeType,
+		&job.RequestedCount,
+		&job.GeneratedCount,
+		&job.FailedCount,
+		&job.ChallengeIDs,
+		&job.Status,
+		&job.Source,
+		&job.RequestedBy,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.RunAt,
+		&job.LockedAt,
+		&job.FinishedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
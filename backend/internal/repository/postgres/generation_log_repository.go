@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fanmania/backend/internal/domain/models"
+)
+
+// GenerationLogRepository records AI provider calls for generation statistics
+type GenerationLogRepository struct {
+	db *DB
+}
+
+// NewGenerationLogRepository creates a new GenerationLogRepository
+func NewGenerationLogRepository(db *DB) *GenerationLogRepository {
+	return &GenerationLogRepository{db: db}
+}
+
+// Record logs a provider call
+func (r *GenerationLogRepository) Record(ctx context.Context, entry *models.GenerationLogEntry) error {
+	query := `
+		INSERT INTO generation_log (
+			kind, category_id, difficulty_tier, challenge_type, provider, model,
+			input_tokens, output_tokens, estimated_cost_usd, outcome, failure_reasons,
+			error, challenge_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11, '{}'::text[]), $12, $13)
+		RETURNING id, created_at
+	`
+
+	err := r.db.Conn(ctx).QueryRow(
+		ctx,
+		query,
+		entry.Kind,
+		entry.CategoryID,
+		entry.DifficultyTier,
+		entry.ChallengeType,
+		entry.Provider,
+		entry.Model,
+		entry.InputTokens,
+		entry.OutputTokens,
+		entry.EstimatedCostUSD,
+		entry.Outcome,
+		entry.FailureReasons,
+		entry.Error,
+		entry.ChallengeID,
+	).Scan(&entry.ID, &entry.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to record generation: %w", err)
+	}
+
+	return nil
+}
+
+// GetOutcomeCounts counts challenge generation calls by outcome
+func (r *GenerationLogRepository) GetOutcomeCounts(ctx context.Context, filter models.GenerationStatsFilter) (map[string]int, error) {
+	where, args := generationStatsWhere(filter, "")
+	query := `
+		SELECT outcome, COUNT(*)
+		FROM generation_log
+		WHERE kind = 'challenge'` + where + `
+		GROUP BY outcome
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count generation outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var outcome string
+		var count int
+		if err := rows.Scan(&outcome, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan generation outcome: %w", err)
+		}
+		counts[outcome] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetTopFailureReasons returns the most common reasons challenges failed
+// validation, most frequent first
+func (r *GenerationLogRepository) GetTopFailureReasons(
+	ctx context.Context,
+	filter models.GenerationStatsFilter,
+	limit int,
+) ([]models.FailureReason, error) {
+	where, args := generationStatsWhere(filter, "")
+	query := fmt.Sprintf(`
+		SELECT reason, COUNT(*)
+		FROM generation_log, unnest(failure_reasons) AS reason
+		WHERE kind = 'challenge'
+		  AND outcome IN ('parse_error', 'legal_rejected', 'invalid')%s
+		GROUP BY reason
+		ORDER BY COUNT(*) DESC, reason
+		LIMIT $%d
+	`, where, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generation failure reasons: %w", err)
+	}
+	defer rows.Close()
+
+	reasons := []models.FailureReason{}
+	for rows.Next() {
+		var reason models.FailureReason
+		if err := rows.Scan(&reason.Reason, &reason.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan generation failure reason: %w", err)
+		}
+		reasons = append(reasons, reason)
+	}
+
+	return reasons, rows.Err()
+}
+
+// GetProviderUsage totals calls, tokens and estimated cost per provider
+// model, most expensive first. Category generation calls are included.
+func (r *GenerationLogRepository) GetProviderUsage(ctx context.Context, filter models.GenerationStatsFilter) ([]models.ProviderUsage, error) {
+	where, args := generationStatsWhere(filter, "")
+	query := `
+		SELECT provider,
+		       COALESCE(model, ''),
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE outcome = 'provider_error'),
+		       COALESCE(SUM(input_tokens), 0),
+		       COALESCE(SUM(output_tokens), 0),
+		       COALESCE(SUM(estimated_cost_usd), 0)::float8,
+		       COUNT(*) FILTER (WHERE estimated_cost_usd IS NULL)
+		FROM generation_log
+		WHERE true` + where + `
+		GROUP BY provider, COALESCE(model, '')
+		ORDER BY 7 DESC, provider, 2
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []models.ProviderUsage{}
+	for rows.Next() {
+		var u models.ProviderUsage
+		err := rows.Scan(
+			&u.Provider,
+			&u.Model,
+			&u.Calls,
+			&u.FailedCalls,
+			&u.InputTokens,
+			&u.OutputTokens,
+			&u.EstimatedCostUSD,
+			&u.UnpricedCalls,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan provider usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
+
+// generationStatsWhere builds the " AND ..." conditions for filter on a
+// table aliased as alias (empty for none), numbering parameters from $1
+func generationStatsWhere(filter models.GenerationStatsFilter, alias string) (string, []interface{}) {
+	if alias != "" {
+		alias += "."
+	}
+
+	var conditions strings.Builder
+	args := []interface{}{}
+
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		fmt.Fprintf(&conditions, " AND %scategory_id = $%d", alias, len(args))
+	}
+	if filter.DifficultyTier != nil {
+		args = append(args, *filter.DifficultyTier)
+		fmt.Fprintf(&conditions, " AND %sdifficulty_tier = $%d", alias, len(args))
+	}
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		fmt.Fprintf(&conditions, " AND %screated_at >= $%d", alias, len(args))
+	}
+
+	return conditions.String(), args
+}
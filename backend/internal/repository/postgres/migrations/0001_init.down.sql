@@ -0,0 +1,17 @@
+-- Reverts 0001_init: drops the whole initial schema.
+-- Extensions are left in place since other schemas may use them.
+
+DROP VIEW IF EXISTS category_stats;
+DROP VIEW IF EXISTS active_users_stats;
+
+DROP TABLE IF EXISTS user_streaks;
+DROP TABLE IF EXISTS user_device_tokens;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS leaderboard_snapshots;
+DROP TABLE IF EXISTS category_rankings;
+DROP TABLE IF EXISTS user_challenge_attempts;
+DROP TABLE IF EXISTS challenges;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
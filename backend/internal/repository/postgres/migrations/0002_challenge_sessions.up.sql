@@ -0,0 +1,15 @@
+-- Server-side attempt sessions: elapsed time is measured from when a
+-- challenge was served instead of trusting the client.
+
+CREATE TABLE challenge_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    challenge_id UUID NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE, -- time limit plus grace; NULL for untimed challenges
+
+    CONSTRAINT unique_user_challenge_session UNIQUE(user_id, challenge_id)
+);
+
+CREATE INDEX idx_challenge_sessions_expires ON challenge_sessions(expires_at) WHERE expires_at IS NOT NULL;
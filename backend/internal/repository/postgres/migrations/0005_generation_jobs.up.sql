@@ -0,0 +1,44 @@
+-- Durable queue of AI challenge generation requests.
+-- At most one pending or running job exists per (category, tier, type);
+-- repeat requests are merged into it.
+
+CREATE TABLE generation_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    difficulty_tier INTEGER NOT NULL,
+    challenge_type VARCHAR(50) NOT NULL,
+
+    requested_count INTEGER NOT NULL,
+    generated_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    challenge_ids UUID[] NOT NULL DEFAULT '{}',
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    source VARCHAR(20) NOT NULL,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    last_error TEXT,
+
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_generation_job_tier CHECK (difficulty_tier BETWEEN 1 AND 5),
+    CONSTRAINT valid_generation_job_count CHECK (requested_count > 0),
+    CONSTRAINT valid_generation_job_status CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    CONSTRAINT valid_generation_job_source CHECK (source IN ('admin', 'auto'))
+);
+
+CREATE UNIQUE INDEX idx_generation_jobs_active
+    ON generation_jobs(category_id, difficulty_tier, challenge_type)
+    WHERE status IN ('pending', 'running');
+
+CREATE INDEX idx_generation_jobs_runnable ON generation_jobs(run_at) WHERE status = 'pending';
+CREATE INDEX idx_generation_jobs_created ON generation_jobs(created_at DESC);
+
+CREATE TRIGGER update_generation_jobs_updated_at BEFORE UPDATE ON generation_jobs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
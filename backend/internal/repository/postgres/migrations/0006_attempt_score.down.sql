@@ -0,0 +1 @@
+ALTER TABLE user_challenge_attempts DROP COLUMN IF EXISTS score;
@@ -0,0 +1,10 @@
+-- Fractional score per attempt, so ordering questions can earn partial credit.
+-- 1 is fully correct and 0 is wrong.
+
+ALTER TABLE user_challenge_attempts
+    ADD COLUMN score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+UPDATE user_challenge_attempts SET score = 1 WHERE is_correct;
+
+ALTER TABLE user_challenge_attempts
+    ADD CONSTRAINT valid_attempt_score CHECK (score >= 0 AND score <= 1);
@@ -0,0 +1,15 @@
+DELETE FROM notifications WHERE notification_type = 'prediction_resolved';
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (
+    notification_type IN (
+        'rank_threat', 'challenge_unlock', 'streak_risk', 'difficulty_unlock', 'achievement',
+        'streak_reminder', 'new_challenge', 'difficulty_progress'
+    )
+);
+
+DROP INDEX IF EXISTS idx_attempts_pending;
+
+ALTER TABLE user_challenge_attempts DROP COLUMN IF EXISTS pending;
+
+ALTER TABLE challenges DROP COLUMN IF EXISTS resolved_at;
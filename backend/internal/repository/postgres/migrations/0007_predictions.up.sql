@@ -0,0 +1,19 @@
+-- Prediction challenges take picks before the outcome is known. Their
+-- attempts stay pending until an admin resolves the challenge, which
+-- sets the correct answer and scores them all at once.
+
+ALTER TABLE challenges
+    ADD COLUMN resolved_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE user_challenge_attempts
+    ADD COLUMN pending BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_attempts_pending ON user_challenge_attempts(challenge_id) WHERE pending;
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (
+    notification_type IN (
+        'rank_threat', 'challenge_unlock', 'streak_risk', 'difficulty_unlock', 'achievement',
+        'streak_reminder', 'new_challenge', 'difficulty_progress', 'prediction_resolved'
+    )
+);
@@ -0,0 +1,7 @@
+ALTER TABLE user_challenge_attempts DROP COLUMN IF EXISTS challenge_revision;
+
+DROP TABLE IF EXISTS challenge_revisions;
+
+ALTER TABLE challenges
+    DROP COLUMN IF EXISTS edited_by,
+    DROP COLUMN IF EXISTS revision;
@@ -0,0 +1,45 @@
+-- Every change to a challenge is kept as a numbered revision, and each
+-- attempt records the revision it was graded against.
+
+ALTER TABLE challenges
+    ADD COLUMN revision INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN edited_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS challenge_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    challenge_id UUID NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+
+    -- Snapshot of the challenge as of this revision
+    category_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    question_data JSONB NOT NULL,
+    correct_answer_hash VARCHAR(255) NOT NULL,
+    difficulty_tier INTEGER NOT NULL,
+    base_points INTEGER NOT NULL,
+    time_limit_seconds INTEGER,
+    challenge_type VARCHAR(50) NOT NULL,
+    is_active BOOLEAN NOT NULL,
+    active_from TIMESTAMP WITH TIME ZONE,
+    active_until TIMESTAMP WITH TIME ZONE,
+
+    edited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_challenge_revision UNIQUE(challenge_id, revision)
+);
+
+-- Existing challenges start at revision 1
+INSERT INTO challenge_revisions (
+    challenge_id, revision, category_id, title, description, question_data,
+    correct_answer_hash, difficulty_tier, base_points, time_limit_seconds,
+    challenge_type, is_active, active_from, active_until, created_at
+)
+SELECT id, revision, category_id, title, description, question_data,
+       correct_answer_hash, difficulty_tier, base_points, time_limit_seconds,
+       challenge_type, COALESCE(is_active, true), active_from, active_until, created_at
+FROM challenges;
+
+ALTER TABLE user_challenge_attempts
+    ADD COLUMN challenge_revision INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_challenges_pending_review;
+
+ALTER TABLE challenges
+    DROP CONSTRAINT IF EXISTS valid_review_status,
+    DROP COLUMN IF EXISTS review_reason,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS review_flags,
+    DROP COLUMN IF EXISTS review_status;
@@ -0,0 +1,16 @@
+-- AI challenges the legal validator flags for review are held back from
+-- players until a moderator approves them.
+
+ALTER TABLE challenges
+    ADD COLUMN review_status VARCHAR(20) NOT NULL DEFAULT 'approved',
+    ADD COLUMN review_flags JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN review_reason TEXT,
+    ADD CONSTRAINT valid_review_status CHECK (
+        review_status IN ('pending_review', 'approved', 'rejected')
+    );
+
+-- Review queue, oldest first
+CREATE INDEX idx_challenges_pending_review ON challenges(created_at)
+    WHERE review_status = 'pending_review';
@@ -0,0 +1,3 @@
+ALTER TABLE challenges DROP COLUMN IF EXISTS suspended_at;
+
+DROP TABLE IF EXISTS challenge_reports;
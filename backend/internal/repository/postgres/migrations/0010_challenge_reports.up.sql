@@ -0,0 +1,27 @@
+-- Players can report a challenge they think is wrong. A challenge with
+-- enough open reports is suspended until an admin resolves them.
+
+CREATE TABLE IF NOT EXISTS challenge_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    challenge_id UUID NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(20) NOT NULL,
+    details TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolution_notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_user_report UNIQUE(challenge_id, user_id),
+    CONSTRAINT valid_report_reason CHECK (
+        reason IN ('wrong_answer', 'offensive', 'ambiguous', 'duplicate')
+    ),
+    CONSTRAINT valid_report_status CHECK (status IN ('open', 'upheld', 'dismissed'))
+);
+
+CREATE INDEX idx_challenge_reports_open ON challenge_reports(challenge_id)
+    WHERE status = 'open';
+
+-- Set while a challenge is taken out of play by reports
+ALTER TABLE challenges ADD COLUMN suspended_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,34 @@
+-- One row per AI provider call, recording what it cost and what became of
+-- its output, for the admin generation statistics.
+
+CREATE TABLE IF NOT EXISTS generation_log (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(20) NOT NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    difficulty_tier INTEGER,
+    challenge_type VARCHAR(50),
+
+    provider VARCHAR(100) NOT NULL,
+    model VARCHAR(100),
+    input_tokens INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    -- NULL when the model has no known price
+    estimated_cost_usd NUMERIC(12, 6),
+
+    outcome VARCHAR(30) NOT NULL,
+    failure_reasons TEXT[] NOT NULL DEFAULT '{}',
+    error TEXT,
+    challenge_id UUID REFERENCES challenges(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_generation_log_kind CHECK (kind IN ('challenge', 'category')),
+    CONSTRAINT valid_generation_log_outcome CHECK (
+        outcome IN (
+            'generated', 'saved', 'provider_error', 'parse_error',
+            'legal_rejected', 'invalid', 'duplicate', 'save_error'
+        )
+    )
+);
+
+CREATE INDEX idx_generation_log_created ON generation_log(created_at DESC);
+CREATE INDEX idx_generation_log_category ON generation_log(category_id, difficulty_tier);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_challenges_difficulty_flag;
+
+ALTER TABLE challenges
+    DROP CONSTRAINT IF EXISTS valid_difficulty_flag,
+    DROP COLUMN IF EXISTS empirical_difficulty,
+    DROP COLUMN IF EXISTS solve_rate,
+    DROP COLUMN IF EXISTS median_time_ratio,
+    DROP COLUMN IF EXISTS difficulty_sample_size,
+    DROP COLUMN IF EXISTS difficulty_flag,
+    DROP COLUMN IF EXISTS difficulty_assessed_at,
+    DROP COLUMN IF EXISTS original_difficulty_tier;
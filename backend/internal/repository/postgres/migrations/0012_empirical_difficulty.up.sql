@@ -0,0 +1,31 @@
+-- Empirical difficulty, estimated periodically from how players do on a
+-- challenge. Challenges whose observed difficulty disagrees with their
+-- tier are flagged, or re-tiered if automatic re-tiering is on.
+
+ALTER TABLE challenges
+    ADD COLUMN empirical_difficulty DOUBLE PRECISION, -- on the 1-5 tier scale
+    ADD COLUMN solve_rate DOUBLE PRECISION,           -- mean attempt score
+    ADD COLUMN median_time_ratio DOUBLE PRECISION,    -- median share of the time limit used
+    ADD COLUMN difficulty_sample_size INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN difficulty_flag VARCHAR(20),
+    ADD COLUMN difficulty_assessed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN original_difficulty_tier INTEGER,      -- set the first time a challenge is re-tiered
+    ADD CONSTRAINT valid_difficulty_flag CHECK (difficulty_flag IN ('too_easy', 'too_hard'));
+
+CREATE INDEX idx_challenges_difficulty_flag ON challenges(difficulty_flag)
+    WHERE difficulty_flag IS NOT NULL;
+
+-- Bring the attempt counters in line with the attempts recorded so far
+UPDATE challenges c
+SET usage_count = counts.total,
+    correct_count = counts.correct,
+    incorrect_count = counts.incorrect
+FROM (
+    SELECT challenge_id,
+           COUNT(*) AS total,
+           COUNT(*) FILTER (WHERE is_correct) AS correct,
+           COUNT(*) FILTER (WHERE NOT is_correct AND NOT pending) AS incorrect
+    FROM user_challenge_attempts
+    GROUP BY challenge_id
+) counts
+WHERE c.id = counts.challenge_id;
@@ -0,0 +1,7 @@
+ALTER TABLE challenge_revisions
+    DROP COLUMN IF EXISTS sources,
+    DROP COLUMN IF EXISTS explanation;
+
+ALTER TABLE challenges
+    DROP COLUMN IF EXISTS sources,
+    DROP COLUMN IF EXISTS explanation;
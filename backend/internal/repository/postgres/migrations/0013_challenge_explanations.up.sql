@@ -0,0 +1,11 @@
+-- Explanations and source references, shown to players once they have
+-- answered a challenge. Revisions keep them alongside the rest of the
+-- challenge content.
+
+ALTER TABLE challenges
+    ADD COLUMN explanation TEXT,
+    ADD COLUMN sources JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE challenge_revisions
+    ADD COLUMN explanation TEXT,
+    ADD COLUMN sources JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,23 @@
+-- Per-category skill ratings, Glicko style. Each attempt moves the rating
+-- toward how the user did against the challenge's difficulty, and the
+-- deviation shrinks as the rating becomes more certain. Challenges are
+-- served near the user's rating.
+
+CREATE TABLE IF NOT EXISTS user_skills (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+
+    rating DOUBLE PRECISION NOT NULL DEFAULT 1100,
+    rating_deviation DOUBLE PRECISION NOT NULL DEFAULT 350,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    unlocked_tier INTEGER NOT NULL DEFAULT 1, -- highest tier the user has proven they can answer
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, category_id),
+    CONSTRAINT valid_unlocked_tier CHECK (unlocked_tier BETWEEN 1 AND 5)
+);
+
+-- How many players of a category have reached each tier
+CREATE INDEX idx_user_skills_category_tier ON user_skills(category_id, unlocked_tier);
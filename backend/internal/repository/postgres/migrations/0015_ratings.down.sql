@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_user_skills_category_rating;
+DROP INDEX IF EXISTS idx_users_rating;
+
+ALTER TABLE challenges
+    DROP COLUMN IF EXISTS rating_attempts,
+    DROP COLUMN IF EXISTS rating;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS rating_updated_at,
+    DROP COLUMN IF EXISTS rated_attempts,
+    DROP COLUMN IF EXISTS rating_deviation,
+    DROP COLUMN IF EXISTS rating;
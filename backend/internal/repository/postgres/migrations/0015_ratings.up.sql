@@ -0,0 +1,22 @@
+-- Elo style ratings alongside points. Users carry a global rating next to
+-- their per-category skill, and challenges carry a rating that moves with
+-- every attempt, so answering hard challenges counts for more than
+-- answering many easy ones.
+
+ALTER TABLE users
+    ADD COLUMN rating DOUBLE PRECISION NOT NULL DEFAULT 1100,
+    ADD COLUMN rating_deviation DOUBLE PRECISION NOT NULL DEFAULT 350,
+    ADD COLUMN rated_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN rating_updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE challenges
+    ADD COLUMN rating DOUBLE PRECISION,
+    ADD COLUMN rating_attempts INTEGER NOT NULL DEFAULT 0;
+
+-- Challenges start at the rating of their tier
+UPDATE challenges SET rating = 1000 + (difficulty_tier - 1) * 200;
+ALTER TABLE challenges ALTER COLUMN rating SET NOT NULL;
+
+-- Rating leaderboards
+CREATE INDEX idx_users_rating ON users(rating DESC);
+CREATE INDEX idx_user_skills_category_rating ON user_skills(category_id, rating DESC);
@@ -0,0 +1,26 @@
+-- Points earned per user, category and UTC day of the attempt. Daily,
+-- weekly and monthly leaderboards sum the days in their window instead
+-- of scanning attempts. Kept in step with attempts' points_earned as
+-- attempts are scored, resolved and regraded.
+
+CREATE TABLE IF NOT EXISTS user_daily_points (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    points BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (user_id, category_id, day)
+);
+
+CREATE INDEX idx_user_daily_points_day ON user_daily_points(day);
+CREATE INDEX idx_user_daily_points_category_day ON user_daily_points(category_id, day);
+
+-- Sum the attempts scored so far; pending predictions are added when they
+-- are resolved
+INSERT INTO user_daily_points (user_id, category_id, day, points)
+SELECT uca.user_id, c.category_id, (uca.attempted_at AT TIME ZONE 'UTC')::date, SUM(uca.points_earned)
+FROM user_challenge_attempts uca
+JOIN challenges c ON c.id = uca.challenge_id
+WHERE NOT uca.pending
+GROUP BY uca.user_id, c.category_id, (uca.attempted_at AT TIME ZONE 'UTC')::date
+ON CONFLICT (user_id, category_id, day) DO UPDATE SET points = EXCLUDED.points;
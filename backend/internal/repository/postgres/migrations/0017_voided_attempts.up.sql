@@ -0,0 +1,8 @@
+-- Attempts at a voided challenge no longer count for anything. They are
+-- kept, so the user cannot attempt the challenge again, but left out of
+-- challenge counters, difficulty calibration, rating replays and stats.
+-- Attempts voided before this migration cannot be told apart from wrong
+-- answers and stay as they are.
+
+ALTER TABLE user_challenge_attempts
+    ADD COLUMN voided BOOLEAN NOT NULL DEFAULT false;
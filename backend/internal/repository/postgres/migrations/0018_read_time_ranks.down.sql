@@ -0,0 +1,48 @@
+DROP VIEW IF EXISTS active_users_stats;
+
+DROP INDEX IF EXISTS idx_users_points_order;
+
+ALTER TABLE category_rankings ADD COLUMN IF NOT EXISTS rank INTEGER;
+UPDATE category_rankings cr
+SET rank = ranked.rank
+FROM (
+    SELECT
+        cr.id,
+        ROW_NUMBER() OVER (
+            PARTITION BY cr.category_id
+            ORDER BY cr.points DESC, u.created_at ASC, u.id
+        ) as rank
+    FROM category_rankings cr
+    JOIN users u ON cr.user_id = u.id
+) ranked
+WHERE cr.id = ranked.id;
+CREATE INDEX IF NOT EXISTS idx_category_rankings_category ON category_rankings(category_id, rank);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS global_rank INTEGER;
+UPDATE users u
+SET global_rank = ranked.rank
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY total_points DESC, created_at ASC, id) as rank
+    FROM users
+    WHERE is_active = true
+) ranked
+WHERE u.id = ranked.id;
+CREATE INDEX IF NOT EXISTS idx_users_global_rank ON users(global_rank) WHERE is_active = true;
+
+CREATE OR REPLACE VIEW active_users_stats AS
+SELECT
+    u.id,
+    u.username,
+    u.display_name,
+    u.total_points,
+    u.global_rank,
+    COUNT(DISTINCT uca.challenge_id) as challenges_completed,
+    COUNT(DISTINCT cr.category_id) as categories_active,
+    MAX(us.current_streak) as best_current_streak,
+    u.last_active
+FROM users u
+LEFT JOIN user_challenge_attempts uca ON u.id = uca.user_id
+LEFT JOIN category_rankings cr ON u.id = cr.user_id
+LEFT JOIN user_streaks us ON u.id = us.user_id
+WHERE u.is_active = true
+GROUP BY u.id;
@@ -0,0 +1,40 @@
+-- Ranks are worked out when read instead of being stored. Keeping stored
+-- ranks current meant rewriting other users' rows, under a lock every
+-- submission had to wait on. Ties go to the longest-standing user.
+
+DROP VIEW IF EXISTS active_users_stats;
+
+DROP INDEX IF EXISTS idx_users_global_rank;
+ALTER TABLE users DROP COLUMN IF EXISTS global_rank;
+
+DROP INDEX IF EXISTS idx_category_rankings_category;
+ALTER TABLE category_rankings DROP COLUMN IF EXISTS rank;
+
+-- Serves the global board's order and the count of users ahead
+CREATE INDEX IF NOT EXISTS idx_users_points_order
+    ON users(total_points DESC, created_at, id) WHERE is_active = true;
+
+CREATE OR REPLACE VIEW active_users_stats AS
+SELECT
+    u.id,
+    u.username,
+    u.display_name,
+    u.total_points,
+    (
+        SELECT COUNT(*) + 1
+        FROM users ahead
+        WHERE ahead.is_active = true
+          AND (ahead.total_points > u.total_points
+               OR (ahead.total_points = u.total_points
+                   AND (ahead.created_at, ahead.id) < (u.created_at, u.id)))
+    ) as global_rank,
+    COUNT(DISTINCT uca.challenge_id) as challenges_completed,
+    COUNT(DISTINCT cr.category_id) as categories_active,
+    MAX(us.current_streak) as best_current_streak,
+    u.last_active
+FROM users u
+LEFT JOIN user_challenge_attempts uca ON u.id = uca.user_id
+LEFT JOIN category_rankings cr ON u.id = cr.user_id
+LEFT JOIN user_streaks us ON u.id = us.user_id
+WHERE u.is_active = true
+GROUP BY u.id;
@@ -27,7 +27,7 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *model
 		RETURNING id, is_read, is_pushed, created_at
 	`
 
-	err := r.db.Pool.QueryRow(
+	err := r.db.Conn(ctx).QueryRow(
 		ctx,
 		query,
 		notification.UserID,
@@ -62,7 +62,7 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 		LIMIT $2
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, limit)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, userID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query notifications: %w", err)
 	}
@@ -101,7 +101,7 @@ func (r *NotificationRepository) MarkAsRead(ctx context.Context, notificationID
 		WHERE id = $1 AND user_id = $2
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query, notificationID, userID)
+	result, err := r.db.Conn(ctx).Exec(ctx, query, notificationID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to mark as read: %w", err)
 	}
@@ -121,7 +121,7 @@ func (r *NotificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.
 		WHERE user_id = $1 AND is_read = false
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, userID)
+	_, err := r.db.Conn(ctx).Exec(ctx, query, userID)
 	return err
 }
 
@@ -134,7 +134,7 @@ func (r *NotificationRepository) GetUnreadCount(ctx context.Context, userID uuid
 	`
 
 	var count int
-	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(&count)
+	err := r.db.Conn(ctx).QueryRow(ctx, query, userID).Scan(&count)
 	return count, err
 }
 
@@ -152,7 +152,7 @@ func (r *NotificationRepository) RegisterDeviceToken(ctx context.Context, token
 		RETURNING id, is_active, created_at, last_used
 	`
 
-	err := r.db.Pool.QueryRow(
+	err := r.db.Conn(ctx).QueryRow(
 		ctx,
 		query,
 		token.UserID,
@@ -180,7 +180,7 @@ func (r *NotificationRepository) GetActiveDeviceTokens(ctx context.Context, user
 		WHERE user_id = $1 AND is_active = true
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query device tokens: %w", err)
 	}
@@ -206,7 +206,7 @@ func (r *NotificationRepository) DeleteDeviceToken(ctx context.Context, userID u
 		WHERE user_id = $1 AND fcm_token = $2
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, userID, fcmToken)
+	_, err := r.db.Conn(ctx).Exec(ctx, query, userID, fcmToken)
 	return err
 }
 
@@ -217,7 +217,7 @@ func (r *NotificationRepository) DeleteExpiredNotifications(ctx context.Context)
 		WHERE expires_at < CURRENT_TIMESTAMP
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query)
+	_, err := r.db.Conn(ctx).Exec(ctx, query)
 	return err
 }
 
@@ -233,7 +233,7 @@ func (r *NotificationRepository) GetPendingPushNotifications(ctx context.Context
 		LIMIT $1
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, limit)
+	rows, err := r.db.Conn(ctx).Query(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query pending notifications: %w", err)
 	}
@@ -272,6 +272,6 @@ func (r *NotificationRepository) MarkAsPushed(ctx context.Context, notificationI
 		WHERE id = $1
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, notificationID)
+	_, err := r.db.Conn(ctx).Exec(ctx, query, notificationID)
 	return err
 }
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// RefreshTokenRepository handles refresh token database operations
+type RefreshTokenRepository struct {
+	db *DB
+}
+
+// NewRefreshTokenRepository creates a new RefreshTokenRepository
+func NewRefreshTokenRepository(db *DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create stores a newly issued refresh token
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	err := r.db.Conn(ctx).QueryRow(
+		ctx,
+		query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.ExpiresAt,
+	).Scan(&token.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a refresh token by its jti
+func (r *RefreshTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, expires_at, created_at, used_at, revoked_at, replaced_by
+		FROM refresh_tokens
+		WHERE id = $1
+	`
+
+	var token models.RefreshToken
+	err := r.db.Conn(ctx).QueryRow(ctx, query, id).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// MarkUsed records that a token was rotated into replacedBy. It returns
+// false if the token was already used, revoked or expired, which means
+// another request rotated it first.
+func (r *RefreshTokenRepository) MarkUsed(ctx context.Context, id, replacedBy uuid.UUID) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = CURRENT_TIMESTAMP, replaced_by = $2
+		WHERE id = $1
+		  AND used_at IS NULL
+		  AND revoked_at IS NULL
+		  AND expires_at > CURRENT_TIMESTAMP
+	`
+
+	result, err := r.db.Conn(ctx).Exec(ctx, query, id, replacedBy)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark refresh token used: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// RevokeFamily revokes every token rotated from the same login
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserFamily revokes a login session, but only if it belongs to userID
+func (r *RefreshTokenRepository) RevokeUserFamily(ctx context.Context, userID, familyID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	if _, err := r.db.Conn(ctx).Exec(ctx, query, familyID, userID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every refresh token a user holds and returns
+// how many were revoked
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Conn(ctx).Exec(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
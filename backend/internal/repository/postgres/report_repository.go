@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ReportRepository handles challenge report database operations
+type ReportRepository struct {
+	db *DB
+}
+
+// NewReportRepository creates a new ReportRepository
+func NewReportRepository(db *DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// Create records a report. A user can report a challenge only once.
+func (r *ReportRepository) Create(ctx context.Context, report *models.ChallengeReport) error {
+	query := `
+		INSERT INTO challenge_reports (challenge_id, user_id, reason, details)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at
+	`
+
+	err := r.db.Conn(ctx).QueryRow(
+		ctx,
+		query,
+		report.ChallengeID,
+		report.UserID,
+		report.Reason,
+		report.Details,
+	).Scan(&report.ID, &report.Status, &report.CreatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if stderrors.As(err, &pgErr) && pgErr.ConstraintName == "unique_user_report" {
+			return errors.ErrAlreadyReported
+		}
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	return nil
+}
+
+// CountOpen counts a challenge's open reports
+func (r *ReportRepository) CountOpen(ctx context.Context, challengeID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM challenge_reports
+		WHERE challenge_id = $1 AND status = 'open'
+	`
+
+	var count int
+	err := r.db.Conn(ctx).QueryRow(ctx, query, challengeID).Scan(&count)
+	return count, err
+}
+
+// ListForChallenge retrieves a challenge's reports, newest first,
+// optionally filtered by status
+func (r *ReportRepository) ListForChallenge(ctx context.Context, challengeID uuid.UUID, status string) ([]models.ChallengeReport, error) {
+	query := `
+		SELECT id, challenge_id, user_id, reason, details, status,
+		       resolved_by, resolved_at, resolution_notes, created_at
+		FROM challenge_reports
+		WHERE challenge_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, challengeID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.ChallengeReport{}
+	for rows.Next() {
+		var report models.ChallengeReport
+		err := rows.Scan(
+			&report.ID,
+			&report.ChallengeID,
+			&report.UserID,
+			&report.Reason,
+			&report.Details,
+			&report.Status,
+			&report.ResolvedBy,
+			&report.ResolvedAt,
+			&report.ResolutionNotes,
+			&report.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+// ListSummaries aggregates reports with the given status per challenge,
+// most reported first
+func (r *ReportRepository) ListSummaries(ctx context.Context, status string, limit, offset int) ([]models.ReportSummary, error) {
+	query := `
+		SELECT c.id, c.title, c.challenge_type, c.is_active, c.suspended_at,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE cr.reason = 'wrong_answer'),
+		       COUNT(*) FILTER (WHERE cr.reason = 'offensive'),
+		       COUNT(*) FILTER (WHERE cr.reason = 'ambiguous'),
+		       COUNT(*) FILTER (WHERE cr.reason = 'duplicate'),
+		       MIN(cr.created_at), MAX(cr.created_at)
+		FROM challenge_reports cr
+		JOIN challenges c ON c.id = cr.challenge_id
+		WHERE cr.status = $1
+		GROUP BY c.id
+		ORDER BY COUNT(*) DESC, MIN(cr.created_at)
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []models.ReportSummary{}
+	for rows.Next() {
+		var summary models.ReportSummary
+		var wrongAnswer, offensive, ambiguous, duplicate int
+		err := rows.Scan(
+			&summary.ChallengeID,
+			&summary.Title,
+			&summary.ChallengeType,
+			&summary.IsActive,
+			&summary.SuspendedAt,
+			&summary.Total,
+			&wrongAnswer,
+			&offensive,
+			&ambiguous,
+			&duplicate,
+			&summary.FirstReportedAt,
+			&summary.LastReportedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report summary: %w", err)
+		}
+		summary.ByReason = map[string]int{
+			models.ReportWrongAnswer: wrongAnswer,
+			models.ReportOffensive:   offensive,
+			models.ReportAmbiguous:   ambiguous,
+			models.ReportDuplicate:   duplicate,
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// ResolveOpen closes every open report on a challenge with the given
+// status and returns how many were closed
+func (r *ReportRepository) ResolveOpen(
+	ctx context.Context,
+	challengeID uuid.UUID,
+	status string,
+	resolvedBy uuid.UUID,
+	notes *string,
+) (int, error) {
+	query := `
+		UPDATE challenge_reports
+		SET status = $2,
+		    resolved_by = $3,
+		    resolved_at = CURRENT_TIMESTAMP,
+		    resolution_notes = $4
+		WHERE challenge_id = $1 AND status = 'open'
+	`
+
+	tag, err := r.db.Conn(ctx).Exec(ctx, query, challengeID, status, resolvedBy, notes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
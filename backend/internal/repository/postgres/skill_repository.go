@@ -0,0 +1,347 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SkillRepository handles user skill ratings, per category and overall
+type SkillRepository struct {
+	db *DB
+}
+
+// NewSkillRepository creates a new SkillRepository
+func NewSkillRepository(db *DB) *SkillRepository {
+	return &SkillRepository{db: db}
+}
+
+const skillColumns = `user_id, category_id, rating, rating_deviation, attempts, unlocked_tier, updated_at`
+
+// Get retrieves a user's skill in a category, or nil if they have not
+// played it yet
+func (r *SkillRepository) Get(ctx context.Context, userID, categoryID uuid.UUID) (*models.UserSkill, error) {
+	query := `
+		SELECT ` + skillColumns + `
+		FROM user_skills
+		WHERE user_id = $1 AND category_id = $2
+	`
+
+	var skill models.UserSkill
+	err := scanSkill(r.db.Conn(ctx).QueryRow(ctx, query, userID, categoryID), &skill)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get skill: %w", err)
+	}
+
+	return &skill, nil
+}
+
+// GetForUpdate retrieves and locks a user's skill in a category, creating
+// it at the starting rating if they have not played it yet. It must be
+// called inside a transaction.
+func (r *SkillRepository) GetForUpdate(ctx context.Context, userID, categoryID uuid.UUID) (*models.UserSkill, error) {
+	conn := r.db.Conn(ctx)
+
+	_, err := conn.Exec(ctx, `
+		INSERT INTO user_skills (user_id, category_id, rating, rating_deviation)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, category_id) DO NOTHING
+	`, userID, categoryID, models.SkillInitialRating, models.SkillInitialDeviation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skill: %w", err)
+	}
+
+	query := `
+		SELECT ` + skillColumns + `
+		FROM user_skills
+		WHERE user_id = $1 AND category_id = $2
+		FOR UPDATE
+	`
+
+	var skill models.UserSkill
+	if err := scanSkill(conn.QueryRow(ctx, query, userID, categoryID), &skill); err != nil {
+		return nil, fmt.Errorf("failed to lock skill: %w", err)
+	}
+
+	return &skill, nil
+}
+
+// Save writes a user's updated skill
+func (r *SkillRepository) Save(ctx context.Context, skill *models.UserSkill) error {
+	query := `
+		UPDATE user_skills
+		SET rating = $3,
+		    rating_deviation = $4,
+		    attempts = $5,
+		    unlocked_tier = $6,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND category_id = $2
+		RETURNING updated_at
+	`
+
+	err := r.db.Conn(ctx).QueryRow(
+		ctx,
+		query,
+		skill.UserID,
+		skill.CategoryID,
+		skill.Rating,
+		skill.Deviation,
+		skill.Attempts,
+		skill.UnlockedTier,
+	).Scan(&skill.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save skill: %w", err)
+	}
+
+	return nil
+}
+
+// CountTierReached counts the players of a category and how many of them
+// have unlocked tier or above
+func (r *SkillRepository) CountTierReached(ctx context.Context, categoryID uuid.UUID, tier int) (reached, total int, err error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE unlocked_tier >= $2), COUNT(*)
+		FROM user_skills
+		WHERE category_id = $1
+	`
+
+	err = r.db.Conn(ctx).QueryRow(ctx, query, categoryID, tier).Scan(&reached, &total)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count players by tier: %w", err)
+	}
+
+	return reached, total, nil
+}
+
+// GetGlobalForUpdate retrieves and locks a user's global rating. It must
+// be called inside a transaction.
+func (r *SkillRepository) GetGlobalForUpdate(ctx context.Context, userID uuid.UUID) (*models.SkillRating, error) {
+	query := `
+		SELECT rating, rating_deviation, rated_attempts, rating_updated_at
+		FROM users
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var rating models.SkillRating
+	err := r.db.Conn(ctx).QueryRow(ctx, query, userID).Scan(
+		&rating.Rating,
+		&rating.Deviation,
+		&rating.Attempts,
+		&rating.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to lock rating: %w", err)
+	}
+
+	return &rating, nil
+}
+
+// SaveGlobal writes a user's updated global rating
+func (r *SkillRepository) SaveGlobal(ctx context.Context, userID uuid.UUID, rating *models.SkillRating) error {
+	query := `
+		UPDATE users
+		SET rating = $2,
+		    rating_deviation = $3,
+		    rated_attempts = $4,
+		    rating_updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING rating_updated_at
+	`
+
+	err := r.db.Conn(ctx).QueryRow(ctx, query, userID, rating.Rating, rating.Deviation, rating.Attempts).
+		Scan(&rating.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save rating: %w", err)
+	}
+
+	return nil
+}
+
+// ratingsLock is the advisory lock key rating updates hold shared and a
+// recalculation holds exclusively
+const ratingsLock = "ratings"
+
+// LockForRecalculation waits for rating updates in flight and holds off
+// new ones until the transaction ends. Nothing else waits: users, skills
+// and challenges stay open to sign-ins, registrations and edits.
+func (r *SkillRepository) LockForRecalculation(ctx context.Context) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, ratingsLock)
+	if err != nil {
+		return fmt.Errorf("failed to lock ratings: %w", err)
+	}
+	return nil
+}
+
+// LockForRating lets a rating update run alongside others, but not while
+// ratings are recalculated. It must be taken before any row the update
+// writes is locked.
+func (r *SkillRepository) LockForRating(ctx context.Context) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `SELECT pg_advisory_xact_lock_shared(hashtext($1))`, ratingsLock)
+	if err != nil {
+		return fmt.Errorf("failed to lock ratings: %w", err)
+	}
+	return nil
+}
+
+// ForEachRatedAttempt calls fn with every scored attempt that counts
+// towards ratings, oldest first, with the tier its challenge had at the
+// revision it was graded against. Predictions and voided attempts are not
+// rated.
+func (r *SkillRepository) ForEachRatedAttempt(ctx context.Context, fn func(attempt models.RatedAttempt) error) error {
+	query := `
+		SELECT uca.user_id, uca.challenge_id, c.category_id,
+		       COALESCE(cr.difficulty_tier, c.difficulty_tier), uca.score, uca.attempted_at
+		FROM user_challenge_attempts uca
+		JOIN challenges c ON c.id = uca.challenge_id
+		LEFT JOIN challenge_revisions cr
+		       ON cr.challenge_id = uca.challenge_id AND cr.revision = uca.challenge_revision
+		WHERE NOT uca.pending AND NOT uca.voided AND c.challenge_type <> 'prediction'
+		ORDER BY uca.attempted_at, uca.id
+	`
+
+	rows, err := r.db.Conn(ctx).Query(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to query rated attempts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attempt models.RatedAttempt
+		err := rows.Scan(
+			&attempt.UserID,
+			&attempt.ChallengeID,
+			&attempt.CategoryID,
+			&attempt.DifficultyTier,
+			&attempt.Score,
+			&attempt.AttemptedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan rated attempt: %w", err)
+		}
+		if err := fn(attempt); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ReplaceSkills puts every category skill back at the starting rating,
+// then writes the recalculated skills. Unlocked tiers are never taken away.
+func (r *SkillRepository) ReplaceSkills(ctx context.Context, skills []models.UserSkill) error {
+	conn := r.db.Conn(ctx)
+
+	_, err := conn.Exec(ctx, `
+		UPDATE user_skills
+		SET rating = $1, rating_deviation = $2, attempts = 0, updated_at = CURRENT_TIMESTAMP
+	`, models.SkillInitialRating, models.SkillInitialDeviation)
+	if err != nil {
+		return fmt.Errorf("failed to reset skills: %w", err)
+	}
+
+	userIDs := make([]uuid.UUID, len(skills))
+	categoryIDs := make([]uuid.UUID, len(skills))
+	ratings := make([]float64, len(skills))
+	deviations := make([]float64, len(skills))
+	attempts := make([]int, len(skills))
+	tiers := make([]int, len(skills))
+	updatedAt := make([]time.Time, len(skills))
+	for i, skill := range skills {
+		userIDs[i] = skill.UserID
+		categoryIDs[i] = skill.CategoryID
+		ratings[i] = skill.Rating
+		deviations[i] = skill.Deviation
+		attempts[i] = skill.Attempts
+		tiers[i] = skill.UnlockedTier
+		updatedAt[i] = skill.UpdatedAt
+	}
+
+	_, err = conn.Exec(ctx, `
+		INSERT INTO user_skills (
+			user_id, category_id, rating, rating_deviation, attempts, unlocked_tier, updated_at
+		)
+		SELECT * FROM unnest(
+			$1::uuid[], $2::uuid[], $3::float8[], $4::float8[], $5::int[], $6::int[], $7::timestamptz[]
+		)
+		ON CONFLICT (user_id, category_id)
+		DO UPDATE SET
+			rating = EXCLUDED.rating,
+			rating_deviation = EXCLUDED.rating_deviation,
+			attempts = EXCLUDED.attempts,
+			unlocked_tier = GREATEST(user_skills.unlocked_tier, EXCLUDED.unlocked_tier),
+			updated_at = EXCLUDED.updated_at
+	`, userIDs, categoryIDs, ratings, deviations, attempts, tiers, updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save skills: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceGlobalRatings puts every user back at the starting rating, then
+// writes the recalculated global ratings
+func (r *SkillRepository) ReplaceGlobalRatings(ctx context.Context, ratings map[uuid.UUID]models.SkillRating) error {
+	conn := r.db.Conn(ctx)
+
+	_, err := conn.Exec(ctx, `
+		UPDATE users
+		SET rating = $1, rating_deviation = $2, rated_attempts = 0, rating_updated_at = CURRENT_TIMESTAMP
+	`, models.SkillInitialRating, models.SkillInitialDeviation)
+	if err != nil {
+		return fmt.Errorf("failed to reset ratings: %w", err)
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(ratings))
+	values := make([]float64, 0, len(ratings))
+	deviations := make([]float64, 0, len(ratings))
+	attempts := make([]int, 0, len(ratings))
+	updatedAt := make([]time.Time, 0, len(ratings))
+	for userID, rating := range ratings {
+		userIDs = append(userIDs, userID)
+		values = append(values, rating.Rating)
+		deviations = append(deviations, rating.Deviation)
+		attempts = append(attempts, rating.Attempts)
+		updatedAt = append(updatedAt, rating.UpdatedAt)
+	}
+
+	_, err = conn.Exec(ctx, `
+		UPDATE users u
+		SET rating = r.rating,
+		    rating_deviation = r.deviation,
+		    rated_attempts = r.attempts,
+		    rating_updated_at = r.updated_at
+		FROM unnest($1::uuid[], $2::float8[], $3::float8[], $4::int[], $5::timestamptz[])
+		     AS r(id, rating, deviation, attempts, updated_at)
+		WHERE u.id = r.id
+	`, userIDs, values, deviations, attempts, updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save ratings: %w", err)
+	}
+
+	return nil
+}
+
+func scanSkill(row pgx.Row, skill *models.UserSkill) error {
+	return row.Scan(
+		&skill.UserID,
+		&skill.CategoryID,
+		&skill.Rating,
+		&skill.Deviation,
+		&skill.Attempts,
+		&skill.UnlockedTier,
+		&skill.UpdatedAt,
+	)
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Querier is the query interface shared by the pool and transactions
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// TxRunner runs a unit of work inside a single database transaction.
+// Services depend on this instead of *DB when all they need is atomicity.
+type TxRunner interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// maxTxAttempts bounds how often a transaction aborted by a deadlock or a
+// serialization failure is retried
+const maxTxAttempts = 3
+
+// txKey is the context key under which WithTx stores the active transaction
+type txKey struct{}
+
+// Conn returns the transaction bound to ctx by WithTx, or the pool if there is none.
+// Every repository query goes through Conn so it joins an enclosing unit of work.
+func (db *DB) Conn(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db.Pool
+}
+
+// InTx reports whether ctx carries a transaction started by WithTx
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return ok
+}
+
+// WithTx runs fn inside a transaction. Repository calls made with the context
+// passed to fn join that transaction. If ctx already carries a transaction,
+// fn joins it instead of opening a new one, so services can compose freely.
+// The outermost call retries fn on deadlocks and serialization failures,
+// so fn must not have side effects outside the database.
+func (db *DB) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if InTx(ctx) {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = db.runTx(ctx, fn)
+		if !isRetryableTxError(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// runTx runs fn in a fresh transaction, committing if it returns nil
+func (db *DB) runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// isRetryableTxError reports whether err aborted the transaction in a way
+// that a fresh attempt may succeed
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case "40001", // serialization_failure
+		"40P01": // deadlock_detected
+		return true
+	}
+	return false
+}
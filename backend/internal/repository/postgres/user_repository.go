@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/fanmania/backend/internal/domain/errors"
 	"github.com/fanmania/backend/internal/domain/models"
@@ -10,6 +11,19 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// globalRankColumn selects the global rank of the users row aliased u:
+// one more than the number of active users ahead of them on points, ties
+// going to the longest-standing user. Worked out when read so submissions
+// never rewrite other users' ranks.
+const globalRankColumn = `(
+	SELECT COUNT(*) + 1
+	FROM users ahead
+	WHERE ahead.is_active = true
+	  AND (ahead.total_points > u.total_points
+	       OR (ahead.total_points = u.total_points
+	           AND (ahead.created_at, ahead.id) < (u.created_at, u.id)))
+)`
+
 // UserRepository handles user database operations
 type UserRepository struct {
 	db *DB
@@ -25,10 +39,10 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users (username, email, password_hash, display_name, avatar_url)
 		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at, updated_at, total_points, is_active, is_verified
+		RETURNING id, created_at, updated_at, total_points, is_active, is_verified, role
 	`
 
-	err := r.db.Pool.QueryRow(
+	err := r.db.Conn(ctx).QueryRow(
 		ctx,
 		query,
 		user.Username,
@@ -43,6 +57,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		&user.TotalPoints,
 		&user.IsActive,
 		&user.IsVerified,
+		&user.Role,
 	)
 
 	if err != nil {
@@ -63,14 +78,14 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
 		SELECT id, username, email, password_hash, display_name, avatar_url,
-		       total_points, global_rank, created_at, updated_at, last_active,
-		       is_active, is_verified
-		FROM users
+		       total_points, ` + globalRankColumn + `, created_at, updated_at, last_active,
+		       is_active, is_verified, role
+		FROM users u
 		WHERE id = $1 AND is_active = true
 	`
 
 	var user models.User
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.Conn(ctx).QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -84,6 +99,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 		&user.LastActive,
 		&user.IsActive,
 		&user.IsVerified,
+		&user.Role,
 	)
 
 	if err != nil {
@@ -100,14 +116,14 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	query := `
 		SELECT id, username, email, password_hash, display_name, avatar_url,
-		       total_points, global_rank, created_at, updated_at, last_active,
-		       is_active, is_verified
-		FROM users
+		       total_points, ` + globalRankColumn + `, created_at, updated_at, last_active,
+		       is_active, is_verified, role
+		FROM users u
 		WHERE username = $1 AND is_active = true
 	`
 
 	var user models.User
-	err := r.db.Pool.QueryRow(ctx, query, username).Scan(
+	err := r.db.Conn(ctx).QueryRow(ctx, query, username).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -121,6 +137,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 		&user.LastActive,
 		&user.IsActive,
 		&user.IsVerified,
+		&user.Role,
 	)
 
 	if err != nil {
@@ -137,14 +154,14 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
 		SELECT id, username, email, password_hash, display_name, avatar_url,
-		       total_points, global_rank, created_at, updated_at, last_active,
-		       is_active, is_verified
-		FROM users
+		       total_points, ` + globalRankColumn + `, created_at, updated_at, last_active,
+		       is_active, is_verified, role
+		FROM users u
 		WHERE email = $1 AND is_active = true
 	`
 
 	var user models.User
-	err := r.db.Pool.QueryRow(ctx, query, email).Scan(
+	err := r.db.Conn(ctx).QueryRow(ctx, query, email).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -158,6 +175,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 		&user.LastActive,
 		&user.IsActive,
 		&user.IsVerified,
+		&user.Role,
 	)
 
 	if err != nil {
@@ -179,7 +197,7 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		RETURNING updated_at
 	`
 
-	err := r.db.Pool.QueryRow(
+	err := r.db.Conn(ctx).QueryRow(
 		ctx,
 		query,
 		user.DisplayName,
@@ -205,11 +223,11 @@ func (r *UserRepository) UpdateLastActive(ctx context.Context, userID uuid.UUID)
 		WHERE id = $1
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, userID)
+	_, err := r.db.Conn(ctx).Exec(ctx, query, userID)
 	return err
 }
 
-// UpdatePoints updates user's total points and rank
+// UpdatePoints updates user's total points
 func (r *UserRepository) UpdatePoints(ctx context.Context, userID uuid.UUID, pointsDelta int) error {
 	query := `
 		UPDATE users
@@ -218,16 +236,86 @@ func (r *UserRepository) UpdatePoints(ctx context.Context, userID uuid.UUID, poi
 		WHERE id = $2
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, pointsDelta, userID)
+	_, err := r.db.Conn(ctx).Exec(ctx, query, pointsDelta, userID)
 	return err
 }
 
+// SetVerified marks a user's email address as verified
+func (r *UserRepository) SetVerified(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET is_verified = true, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND is_active = true
+	`
+
+	result, err := r.db.Conn(ctx).Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to verify user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdatePassword replaces a user's password hash
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND is_active = true
+	`
+
+	result, err := r.db.Conn(ctx).Exec(ctx, query, passwordHash, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateRole changes a user's role
+func (r *UserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role string) error {
+	query := `
+		UPDATE users
+		SET role = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND is_active = true
+	`
+
+	result, err := r.db.Conn(ctx).Exec(ctx, query, role, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// RoleExists checks if at least one active user holds the given role
+func (r *UserRepository) RoleExists(ctx context.Context, role string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE role = $1 AND is_active = true)`
+
+	var exists bool
+	err := r.db.Conn(ctx).QueryRow(ctx, query, role).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
 // UsernameExists checks if a username already exists
 func (r *UserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`
 
 	var exists bool
-	err := r.db.Pool.QueryRow(ctx, query, username).Scan(&exists)
+	err := r.db.Conn(ctx).QueryRow(ctx, query, username).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -240,7 +328,7 @@ func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, e
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 
 	var exists bool
-	err := r.db.Pool.QueryRow(ctx, query, email).Scan(&exists)
+	err := r.db.Conn(ctx).QueryRow(ctx, query, email).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -250,29 +338,28 @@ func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, e
 
 // GetStats retrieves user statistics
 func (r *UserRepository) GetStats(ctx context.Context, userID uuid.UUID) (*models.UserStats, error) {
+	// Streaks come from the global (category_id IS NULL) row of user_streaks.
+	// A streak whose last activity is older than yesterday is already broken.
 	query := `
 		SELECT 
 			u.total_points,
-			u.global_rank,
-			COUNT(DISTINCT cr.category_id) as categories_active,
-			COUNT(DISTINCT uca.challenge_id) as challenges_completed,
-			COALESCE(MAX(us.current_streak), 0) as current_streak,
-			COALESCE(MAX(us.longest_streak), 0) as longest_streak,
-			CASE 
-				WHEN COUNT(uca.id) > 0 
-				THEN (COUNT(CASE WHEN uca.is_correct THEN 1 END)::float / COUNT(uca.id)::float * 100)
-				ELSE 0 
-			END as accuracy_rate
+			` + globalRankColumn + ` as global_rank,
+			(SELECT COUNT(*) FROM category_rankings cr WHERE cr.user_id = u.id) as categories_active,
+			(SELECT COUNT(*) FROM user_challenge_attempts uca WHERE uca.user_id = u.id AND NOT uca.voided) as challenges_completed,
+			COALESCE(CASE WHEN us.last_activity_date >= $2 THEN us.current_streak END, 0) as current_streak,
+			COALESCE(us.longest_streak, 0) as longest_streak,
+			COALESCE((
+				SELECT COUNT(CASE WHEN uca.is_correct THEN 1 END)::float / NULLIF(COUNT(uca.id), 0)::float * 100
+				FROM user_challenge_attempts uca
+				WHERE uca.user_id = u.id AND NOT uca.voided
+			), 0) as accuracy_rate
 		FROM users u
-		LEFT JOIN category_rankings cr ON u.id = cr.user_id
-		LEFT JOIN user_challenge_attempts uca ON u.id = uca.user_id
-		LEFT JOIN user_streaks us ON u.id = us.user_id
+		LEFT JOIN user_streaks us ON us.user_id = u.id AND us.category_id IS NULL
 		WHERE u.id = $1 AND u.is_active = true
-		GROUP BY u.id, u.total_points, u.global_rank
 	`
 
 	var stats models.UserStats
-	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(
+	err := r.db.Conn(ctx).QueryRow(ctx, query, userID, streakCutoff()).Scan(
 		&stats.TotalPoints,
 		&stats.GlobalRank,
 		&stats.CategoriesActive,
@@ -291,3 +378,9 @@ func (r *UserRepository) GetStats(ctx context.Context, userID uuid.UUID) (*model
 
 	return &stats, nil
 }
+
+// streakCutoff returns the oldest last_activity_date that still keeps a
+// streak alive (yesterday), matching StreakService's day boundaries
+func streakCutoff() time.Time {
+	return time.Now().Truncate(24*time.Hour).AddDate(0, 0, -1)
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// UserTokenRepository handles single-use account token database operations
+type UserTokenRepository struct {
+	db *DB
+}
+
+// NewUserTokenRepository creates a new UserTokenRepository
+func NewUserTokenRepository(db *DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+// Create stores a token hash for a user. Any earlier unused token with the
+// same purpose is invalidated so only the latest link works.
+func (r *UserTokenRepository) Create(
+	ctx context.Context,
+	userID uuid.UUID,
+	purpose, tokenHash string,
+	expiresAt time.Time,
+) error {
+	conn := r.db.Conn(ctx)
+
+	_, err := conn.Exec(ctx, `
+		UPDATE user_tokens
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, userID, purpose)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate previous tokens: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, `
+		INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, userID, purpose, tokenHash, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return nil
+}
+
+// Consume marks a valid token as used and returns its user. Unknown,
+// expired and already used tokens all yield ErrInvalidAccountToken.
+func (r *UserTokenRepository) Consume(ctx context.Context, purpose, tokenHash string) (uuid.UUID, error) {
+	query := `
+		UPDATE user_tokens
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1
+		  AND purpose = $2
+		  AND used_at IS NULL
+		  AND expires_at > CURRENT_TIMESTAMP
+		RETURNING user_id
+	`
+
+	var userID uuid.UUID
+	err := r.db.Conn(ctx).QueryRow(ctx, query, tokenHash, purpose).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return uuid.Nil, errors.ErrInvalidAccountToken
+		}
+		return uuid.Nil, fmt.Errorf("failed to consume token: %w", err)
+	}
+
+	return userID, nil
+}
@@ -6,7 +6,10 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log"
 	"math/rand"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,25 +21,32 @@ import (
 
 // AIChallengeService handles AI-powered challenge generation
 type AIChallengeService struct {
-	anthropicClient  *ai.AnthropicClient
+	provider         ai.Provider
 	promptBuilder    *ai.ChallengePromptBuilder
 	legalValidator   *ai.LegalValidator
+	pricing          *ai.Pricing
 	challengeRepo    *postgres.ChallengeRepository
 	categoryRepo     *postgres.CategoryRepository
+	generationLog    *postgres.GenerationLogRepository
 }
 
-// NewAIChallengeService creates a new AI challenge service
+// NewAIChallengeService creates a new AI challenge service backed by provider
 func NewAIChallengeService(
-	anthropicAPIKey string,
+	provider ai.Provider,
+	legalValidator *ai.LegalValidator,
+	pricing *ai.Pricing,
 	challengeRepo *postgres.ChallengeRepository,
 	categoryRepo *postgres.CategoryRepository,
+	generationLog *postgres.GenerationLogRepository,
 ) *AIChallengeService {
 	return &AIChallengeService{
-		anthropicClient: ai.NewAnthropicClient(anthropicAPIKey),
+		provider:        provider,
 		promptBuilder:   ai.NewChallengePromptBuilder(),
-		legalValidator:  ai.NewLegalValidator(),
+		legalValidator:  legalValidator,
+		pricing:         pricing,
 		challengeRepo:   challengeRepo,
 		categoryRepo:    categoryRepo,
+		generationLog:   generationLog,
 	}
 }
 
@@ -47,6 +57,11 @@ type GenerateChallengeResult struct {
 	GeneratedJSON  string                   `json:"generated_json,omitempty"`
 	Success        bool                     `json:"success"`
 	Error          string                   `json:"error,omitempty"`
+
+	// For the generation log
+	completion     *ai.Completion
+	outcome        string
+	failureReasons []string
 }
 
 // GenerateChallenge generates a new challenge using AI
@@ -55,6 +70,22 @@ func (s *AIChallengeService) GenerateChallenge(
 	categoryID uuid.UUID,
 	difficultyTier int,
 	challengeType string,
+) (*GenerateChallengeResult, error) {
+	result, err := s.generateChallenge(ctx, categoryID, difficultyTier, challengeType)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordChallengeGeneration(ctx, categoryID, difficultyTier, challengeType, result)
+	return result, nil
+}
+
+// generateChallenge generates a challenge without recording the call
+func (s *AIChallengeService) generateChallenge(
+	ctx context.Context,
+	categoryID uuid.UUID,
+	difficultyTier int,
+	challengeType string,
 ) (*GenerateChallengeResult, error) {
 	// Get category details
 	category, err := s.categoryRepo.GetByID(ctx, categoryID, nil)
@@ -102,12 +133,19 @@ func (s *AIChallengeService) GenerateChallenge(
 			*category.Description,
 			difficultyTier,
 		)
+	case "pattern":
+		prompt = s.promptBuilder.BuildPatternPrompt(
+			category.Name,
+			*category.Description,
+			difficultyTier,
+			existingTitles,
+		)
 	default:
 		return nil, fmt.Errorf("unsupported challenge type: %s", challengeType)
 	}
 
 	// Generate challenge with AI
-	response, err := s.anthropicClient.GenerateChallenge(ctx, prompt, systemPrompt)
+	completion, err := s.provider.Complete(ctx, prompt, systemPrompt)
 	if err != nil {
 		return &GenerateChallengeResult{
 			Success: false,
@@ -116,10 +154,12 @@ func (s *AIChallengeService) GenerateChallenge(
 				IsValid: false,
 				Passed:  false,
 			},
+			outcome: models.GenerationProviderError,
 		}, nil
 	}
 
 	// Clean and parse JSON response
+	response := completion.Text
 	cleanedJSON := ai.CleanJSONResponse(response)
 	
 	var generatedChallenge ai.GeneratedChallenge
@@ -133,18 +173,25 @@ func (s *AIChallengeService) GenerateChallenge(
 				Passed:  false,
 				Errors:  []string{"Invalid JSON format"},
 			},
+			completion:     completion,
+			outcome:        models.GenerationParseError,
+			failureReasons: []string{"Invalid JSON format"},
 		}, nil
 	}
 
 	// Validate legal compliance
-	validation := s.legalValidator.ValidateChallenge(&generatedChallenge)
+	validation := s.legalValidator.ValidateChallenge(&generatedChallenge, challengeType, category.Slug)
 	
 	if !validation.Passed {
+		outcome, reasons := validationFailure(validation)
 		return &GenerateChallengeResult{
-			Success:       false,
-			Error:         "Challenge failed legal validation",
-			GeneratedJSON: cleanedJSON,
-			Validation:    validation,
+			Success:        false,
+			Error:          "Challenge failed legal validation",
+			GeneratedJSON:  cleanedJSON,
+			Validation:     validation,
+			completion:     completion,
+			outcome:        outcome,
+			failureReasons: reasons,
 		}, nil
 	}
 
@@ -161,18 +208,29 @@ func (s *AIChallengeService) GenerateChallenge(
 	challenge, err := s.convertToChallenge(categoryID, difficultyTier, challengeType, sanitized)
 	if err != nil {
 		return &GenerateChallengeResult{
-			Success:       false,
-			Error:         fmt.Sprintf("Failed to convert to challenge: %v", err),
-			GeneratedJSON: cleanedJSON,
-			Validation:    validation,
+			Success:        false,
+			Error:          fmt.Sprintf("Failed to convert to challenge: %v", err),
+			GeneratedJSON:  cleanedJSON,
+			Validation:     validation,
+			completion:     completion,
+			outcome:        models.GenerationInvalid,
+			failureReasons: []string{failureReason(err.Error())},
 		}, nil
 	}
 
+	// Hold sensitive content back from players until a moderator approves it
+	if validation.NeedsReview {
+		challenge.ReviewStatus = models.ReviewPending
+		challenge.ReviewFlags = validation.Warnings
+	}
+
 	return &GenerateChallengeResult{
 		Challenge:     challenge,
 		Validation:    validation,
 		GeneratedJSON: cleanedJSON,
 		Success:       true,
+		completion:    completion,
+		outcome:       models.GenerationGenerated,
 	}, nil
 }
 
@@ -183,10 +241,11 @@ func (s *AIChallengeService) GenerateAndSaveChallenge(
 	difficultyTier int,
 	challengeType string,
 ) (*GenerateChallengeResult, error) {
-	result, err := s.GenerateChallenge(ctx, categoryID, difficultyTier, challengeType)
+	result, err := s.generateChallenge(ctx, categoryID, difficultyTier, challengeType)
 	if err != nil {
 		return nil, err
 	}
+	defer s.recordChallengeGeneration(ctx, categoryID, difficultyTier, challengeType, result)
 
 	if !result.Success {
 		return result, nil
@@ -196,6 +255,7 @@ func (s *AIChallengeService) GenerateAndSaveChallenge(
 	if s.isDuplicateQuestion(ctx, categoryID, result.Challenge.QuestionData) {
 		result.Success = false
 		result.Error = "Generated question is too similar to existing questions"
+		result.outcome = models.GenerationDuplicate
 		return result, nil
 	}
 
@@ -203,12 +263,181 @@ func (s *AIChallengeService) GenerateAndSaveChallenge(
 	if err := s.challengeRepo.Create(ctx, result.Challenge); err != nil {
 		result.Success = false
 		result.Error = fmt.Sprintf("Failed to save challenge: %v", err)
+		result.outcome = models.GenerationSaveError
 		return result, nil
 	}
 
+	result.outcome = models.GenerationSaved
 	return result, nil
 }
 
+// quotedText matches the quoted specifics in validation messages
+var quotedText = regexp.MustCompile(`'[^']*'`)
+
+// failureReason reduces a validation message to a reason that can be
+// counted, without the specifics that differ from one challenge to the next
+func failureReason(message string) string {
+	return quotedText.ReplaceAllString(message, "'...'")
+}
+
+// validationFailure classifies a failed validation. Blocking legal rules
+// are reported by rule ID; if none fired the challenge was malformed.
+func validationFailure(validation *ai.ValidationResult) (string, []string) {
+	var rules []string
+	for _, violation := range validation.Violations {
+		if violation.Severity == ai.SeverityBlock {
+			rules = append(rules, violation.RuleID)
+		}
+	}
+	if len(rules) > 0 {
+		return models.GenerationLegalRejected, rules
+	}
+
+	reasons := make([]string, 0, len(validation.Errors))
+	for _, message := range validation.Errors {
+		reasons = append(reasons, failureReason(message))
+	}
+	return models.GenerationInvalid, reasons
+}
+
+// recordChallengeGeneration logs the provider call behind a challenge
+// generation result
+func (s *AIChallengeService) recordChallengeGeneration(
+	ctx context.Context,
+	categoryID uuid.UUID,
+	difficultyTier int,
+	challengeType string,
+	result *GenerateChallengeResult,
+) {
+	entry := &models.GenerationLogEntry{
+		Kind:           models.GenerationKindChallenge,
+		CategoryID:     &categoryID,
+		DifficultyTier: &difficultyTier,
+		ChallengeType:  &challengeType,
+		Outcome:        result.outcome,
+		FailureReasons: result.failureReasons,
+	}
+	if result.Error != "" {
+		entry.Error = &result.Error
+	}
+	if result.outcome == models.GenerationSaved {
+		entry.ChallengeID = &result.Challenge.ID
+	}
+
+	s.recordGeneration(ctx, entry, result.completion)
+}
+
+// recordGeneration logs a provider call with its token usage and estimated
+// cost. completion is nil if the call failed. Failing to log is reported
+// but does not fail the generation.
+func (s *AIChallengeService) recordGeneration(ctx context.Context, entry *models.GenerationLogEntry, completion *ai.Completion) {
+	if s.generationLog == nil {
+		return
+	}
+
+	if completion == nil {
+		// Nothing was served, so nothing was spent
+		var cost float64
+		entry.Provider = s.provider.Name()
+		entry.EstimatedCostUSD = &cost
+	} else {
+		model := completion.Model
+		entry.Provider = completion.Provider
+		entry.Model = &model
+		entry.InputTokens = completion.Usage.InputTokens
+		entry.OutputTokens = completion.Usage.OutputTokens
+		if cost, ok := s.pricing.Estimate(completion.Model, completion.Usage); ok {
+			entry.EstimatedCostUSD = &cost
+		}
+	}
+
+	if err := s.generationLog.Record(ctx, entry); err != nil {
+		log.Printf("⚠ Failed to record AI generation: %v", err)
+	}
+}
+
+// GetGenerationStats summarizes AI generation: the challenges it has
+// produced and how they are used, how often its output passes validation,
+// and what the provider calls have cost
+func (s *AIChallengeService) GetGenerationStats(
+	ctx context.Context,
+	filter models.GenerationStatsFilter,
+) (*models.GenerationStats, error) {
+	counts, err := s.challengeRepo.GetGenerationCounts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.challengeRepo.GetGeneratedUsage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	outcomes, err := s.generationLog.GetOutcomeCounts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	reasons, err := s.generationLog.GetTopFailureReasons(ctx, filter, 10)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := s.generationLog.GetProviderUsage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.GenerationStats{
+		ByCategory:   counts,
+		ByDifficulty: map[int]int{},
+		Usage:        *usage,
+		Providers:    providers,
+		Outcomes:     outcomes,
+	}
+
+	for _, count := range counts {
+		stats.TotalGenerated += count.Generated
+		stats.TotalActive += count.Active
+		stats.PendingReview += count.PendingReview
+		stats.ByDifficulty[count.DifficultyTier] += count.Generated
+	}
+
+	// Everything that got past the provider was validated; everything that
+	// passed was checked for duplicates, except previews
+	failed := outcomes[models.GenerationParseError] +
+		outcomes[models.GenerationLegalRejected] +
+		outcomes[models.GenerationInvalid]
+	checked := outcomes[models.GenerationDuplicate] +
+		outcomes[models.GenerationSaved] +
+		outcomes[models.GenerationSaveError]
+	passed := checked + outcomes[models.GenerationGenerated]
+
+	stats.Validation = models.GenerationValidation{
+		Checked:           passed + failed,
+		Passed:            passed,
+		Failed:            failed,
+		TopFailureReasons: reasons,
+	}
+	if stats.Validation.Checked > 0 {
+		stats.Validation.PassRate = float64(passed) / float64(stats.Validation.Checked)
+	}
+
+	stats.Duplicates = models.GenerationDuplicates{
+		Checked:  checked,
+		Rejected: outcomes[models.GenerationDuplicate],
+	}
+	if checked > 0 {
+		stats.Duplicates.Rate = float64(stats.Duplicates.Rejected) / float64(checked)
+	}
+
+	for _, provider := range providers {
+		stats.Cost.EstimatedUSD += provider.EstimatedCostUSD
+	}
+	if saved := outcomes[models.GenerationSaved]; saved > 0 {
+		perChallenge := stats.Cost.EstimatedUSD / float64(saved)
+		stats.Cost.PerSavedChallengeUSD = &perChallenge
+	}
+
+	return stats, nil
+}
+
 // isDuplicateQuestion checks if a similar question already exists
 func (s *AIChallengeService) isDuplicateQuestion(ctx context.Context, categoryID uuid.UUID, questionData json.RawMessage) bool {
 	// Parse the question
@@ -218,7 +447,7 @@ func (s *AIChallengeService) isDuplicateQuestion(ctx context.Context, categoryID
 	}
 
 	// Create a normalized hash of the question
-	newHash := s.hashQuestion(newQD.Question)
+	newHash := s.hashQuestion(questionText(newQD))
 
 	// Get existing challenges
 	existingChallenges, err := s.challengeRepo.GetByCategoryAndDifficulty(ctx, categoryID, 0, 200)
@@ -232,7 +461,7 @@ func (s *AIChallengeService) isDuplicateQuestion(ctx context.Context, categoryID
 		if err := json.Unmarshal(ch.QuestionData, &existingQD); err != nil {
 			continue
 		}
-		if s.hashQuestion(existingQD.Question) == newHash {
+		if s.hashQuestion(questionText(existingQD)) == newHash {
 			return true
 		}
 	}
@@ -240,6 +469,32 @@ func (s *AIChallengeService) isDuplicateQuestion(ctx context.Context, categoryID
 	return false
 }
 
+// questionText is the text that identifies a question. Timeline and
+// pattern questions share stock wordings, so their items are included.
+func questionText(qd models.QuestionData) string {
+	if qd.Pattern != nil {
+		items := qd.Pattern.Sequence
+		if len(items) == 0 {
+			for _, opt := range qd.Options {
+				items = append(items, opt.Text)
+			}
+			sort.Strings(items)
+		}
+		return qd.Question + " " + strings.Join(items, " ")
+	}
+
+	if len(qd.Events) == 0 {
+		return qd.Question
+	}
+
+	events := make([]string, 0, len(qd.Events))
+	for _, event := range qd.Events {
+		events = append(events, event.Text)
+	}
+	sort.Strings(events)
+	return qd.Question + " " + strings.Join(events, " ")
+}
+
 // hashQuestion creates a normalized hash for question comparison
 func (s *AIChallengeService) hashQuestion(question string) string {
 	// Normalize: lowercase, remove extra spaces, trim
@@ -249,38 +504,6 @@ func (s *AIChallengeService) hashQuestion(question string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// GenerateBatch generates multiple challenges at once
-func (s *AIChallengeService) GenerateBatch(
-	ctx context.Context,
-	categoryID uuid.UUID,
-	difficultyTiers []int,
-	challengeType string,
-	count int,
-) ([]*GenerateChallengeResult, error) {
-	results := []*GenerateChallengeResult{}
-
-	for _, tier := range difficultyTiers {
-		for i := 0; i < count; i++ {
-			result, err := s.GenerateAndSaveChallenge(ctx, categoryID, tier, challengeType)
-			if err != nil {
-				// Log error but continue
-				results = append(results, &GenerateChallengeResult{
-					Success: false,
-					Error:   err.Error(),
-				})
-				continue
-			}
-			
-			results = append(results, result)
-			
-			// Rate limiting - wait 2 seconds between calls
-			time.Sleep(2 * time.Second)
-		}
-	}
-
-	return results, nil
-}
-
 // convertToChallenge converts AI-generated challenge to domain model
 func (s *AIChallengeService) convertToChallenge(
 	categoryID uuid.UUID,
@@ -288,28 +511,17 @@ func (s *AIChallengeService) convertToChallenge(
 	challengeType string,
 	generated *ai.GeneratedChallenge,
 ) (*models.Challenge, error) {
-	// Build question data JSON with shuffled options
-	questionData := models.QuestionData{
-		Type:     challengeType,
-		Question: generated.Question,
-		Options:  []models.QuestionOption{},
-	}
-
-	// Create options and shuffle them
-	options := make([]models.QuestionOption, 0, len(generated.Options))
-	for _, opt := range generated.Options {
-		options = append(options, models.QuestionOption{
-			ID:   opt.ID,
-			Text: opt.Text,
-		})
-	}
-
 	// Shuffle options randomly
-	rand.Shuffle(len(options), func(i, j int) {
-		options[i], options[j] = options[j], options[i]
+	shuffled := *generated
+	shuffled.Options = append([]ai.ChallengeOption(nil), generated.Options...)
+	rand.Shuffle(len(shuffled.Options), func(i, j int) {
+		shuffled.Options[i], shuffled.Options[j] = shuffled.Options[j], shuffled.Options[i]
 	})
 
-	questionData.Options = options
+	questionData, correctAnswer, err := buildQuestionData(challengeType, &shuffled)
+	if err != nil {
+		return nil, err
+	}
 
 	questionJSON, err := json.Marshal(questionData)
 	if err != nil {
@@ -317,7 +529,7 @@ func (s *AIChallengeService) convertToChallenge(
 	}
 
 	// Hash the correct answer
-	correctAnswerHash := s.hashAnswer(generated.CorrectAnswer)
+	correctAnswerHash := s.hashAnswer(correctAnswer)
 
 	// Calculate base points (100 for all)
 	basePoints := 100
@@ -335,6 +547,7 @@ func (s *AIChallengeService) convertToChallenge(
 		Description:       &description,
 		QuestionData:      questionJSON,
 		CorrectAnswerHash: correctAnswerHash,
+		Sources:           generated.Sources,
 		DifficultyTier:    difficultyTier,
 		BasePoints:        basePoints,
 		TimeLimitSeconds:  &timeLimitSeconds,
@@ -343,10 +556,65 @@ func (s *AIChallengeService) convertToChallenge(
 		IsActive:          true,
 		ActiveUntil:       &activeUntil,
 	}
+	if generated.Explanation != "" {
+		explanation := generated.Explanation
+		challenge.Explanation = &explanation
+	}
 
 	return challenge, nil
 }
 
+// buildQuestionData lays out a challenge's question for its type and
+// returns the correct answer in the form submissions are hashed against
+func buildQuestionData(challengeType string, generated *ai.GeneratedChallenge) (models.QuestionData, string, error) {
+	questionData := models.QuestionData{
+		Type:     challengeType,
+		Question: generated.Question,
+	}
+
+	options := make([]models.QuestionOption, 0, len(generated.Options))
+	for _, opt := range generated.Options {
+		options = append(options, models.QuestionOption{
+			ID:   opt.ID,
+			Text: opt.Text,
+		})
+	}
+
+	questionData.Options = options
+	correctAnswer := generated.CorrectAnswer
+
+	switch challengeType {
+	case "timeline":
+		// Timelines become events that carry their place in the correct order
+		order, err := ai.ParseTimelineOrder(generated)
+		if err != nil {
+			return models.QuestionData{}, "", err
+		}
+		position := make(map[string]int, len(order))
+		for i, id := range order {
+			position[id] = i + 1
+		}
+
+		questionData.Events = make([]models.TimelineEvent, 0, len(options))
+		for _, opt := range options {
+			questionData.Events = append(questionData.Events, models.TimelineEvent{
+				ID:       opt.ID,
+				Text:     opt.Text,
+				Position: position[opt.ID],
+			})
+		}
+		questionData.Options = nil
+		correctAnswer = strings.Join(order, ",")
+	case "pattern":
+		questionData.Pattern = &models.PatternData{
+			Kind:     generated.PatternKind,
+			Sequence: generated.Sequence,
+		}
+	}
+
+	return questionData, correctAnswer, nil
+}
+
 // hashAnswer creates SHA256 hash of answer
 func (s *AIChallengeService) hashAnswer(answer string) string {
 	normalized := strings.ToLower(strings.TrimSpace(answer))
@@ -365,6 +633,8 @@ func (s *AIChallengeService) calculateTimeLimit(difficultyTier int, challengeTyp
 		baseTime = 45
 	case "true_false":
 		baseTime = 20
+	case "pattern":
+		baseTime = 40
 	}
 
 	// Add time for higher difficulties
@@ -373,9 +643,14 @@ func (s *AIChallengeService) calculateTimeLimit(difficultyTier int, challengeTyp
 	return baseTime + extraTime
 }
 
-// ValidateAPIKey validates the Anthropic API key
+// ValidateAPIKey checks the configured provider's credentials
 func (s *AIChallengeService) ValidateAPIKey(ctx context.Context) error {
-	return s.anthropicClient.ValidateAPIKey(ctx)
+	return s.provider.ValidateAPIKey(ctx)
+}
+
+// ProviderName identifies the configured provider
+func (s *AIChallengeService) ProviderName() string {
+	return s.provider.Name()
 }
 
 // GenerateCategoryResult represents the result of category generation
@@ -384,12 +659,30 @@ type GenerateCategoryResult struct {
 	GeneratedJSON string            `json:"generated_json,omitempty"`
 	Success       bool              `json:"success"`
 	Error         string            `json:"error,omitempty"`
+
+	// For the generation log
+	completion *ai.Completion
+	outcome    string
 }
 
 // GenerateCategories generates new category ideas using AI
 func (s *AIChallengeService) GenerateCategories(
 	ctx context.Context,
 	count int,
+) (*GenerateCategoryResult, error) {
+	result, err := s.generateCategories(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordCategoryGeneration(ctx, result)
+	return result, nil
+}
+
+// generateCategories generates category ideas without recording the call
+func (s *AIChallengeService) generateCategories(
+	ctx context.Context,
+	count int,
 ) (*GenerateCategoryResult, error) {
 	// Get existing categories to avoid duplicates
 	existingCategories, _ := s.categoryRepo.GetAll(ctx, nil)
@@ -403,15 +696,17 @@ func (s *AIChallengeService) GenerateCategories(
 	systemPrompt := s.promptBuilder.GetCategorySystemPrompt()
 
 	// Generate with AI
-	response, err := s.anthropicClient.GenerateChallenge(ctx, prompt, systemPrompt)
+	completion, err := s.provider.Complete(ctx, prompt, systemPrompt)
 	if err != nil {
 		return &GenerateCategoryResult{
 			Success: false,
 			Error:   fmt.Sprintf("AI generation failed: %v", err),
+			outcome: models.GenerationProviderError,
 		}, nil
 	}
 
 	// Clean and parse JSON response
+	response := completion.Text
 	cleanedJSON := ai.CleanJSONResponse(response)
 
 	var generatedResponse ai.GeneratedCategoriesResponse
@@ -420,6 +715,8 @@ func (s *AIChallengeService) GenerateCategories(
 			Success:       false,
 			Error:         fmt.Sprintf("Failed to parse AI response: %v", err),
 			GeneratedJSON: response,
+			completion:    completion,
+			outcome:       models.GenerationParseError,
 		}, nil
 	}
 
@@ -444,18 +741,38 @@ func (s *AIChallengeService) GenerateCategories(
 		Categories:    categories,
 		GeneratedJSON: cleanedJSON,
 		Success:       true,
+		completion:    completion,
+		outcome:       models.GenerationGenerated,
 	}, nil
 }
 
+// recordCategoryGeneration logs the provider call behind a category
+// generation result
+func (s *AIChallengeService) recordCategoryGeneration(ctx context.Context, result *GenerateCategoryResult) {
+	entry := &models.GenerationLogEntry{
+		Kind:    models.GenerationKindCategory,
+		Outcome: result.outcome,
+	}
+	if result.Error != "" {
+		entry.Error = &result.Error
+	}
+	if result.outcome == models.GenerationParseError {
+		entry.FailureReasons = []string{"Invalid JSON format"}
+	}
+
+	s.recordGeneration(ctx, entry, result.completion)
+}
+
 // GenerateAndSaveCategories generates and saves new categories to database
 func (s *AIChallengeService) GenerateAndSaveCategories(
 	ctx context.Context,
 	count int,
 ) (*GenerateCategoryResult, error) {
-	result, err := s.GenerateCategories(ctx, count)
+	result, err := s.generateCategories(ctx, count)
 	if err != nil {
 		return nil, err
 	}
+	defer s.recordCategoryGeneration(ctx, result)
 
 	if !result.Success {
 		return result, nil
@@ -472,6 +789,10 @@ func (s *AIChallengeService) GenerateAndSaveCategories(
 		savedCategories = append(savedCategories, catCopy)
 	}
 
+	result.outcome = models.GenerationSaved
+	if len(savedCategories) == 0 && len(result.Categories) > 0 {
+		result.outcome = models.GenerationSaveError
+	}
 	result.Categories = savedCategories
 	return result, nil
 }
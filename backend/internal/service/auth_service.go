@@ -2,27 +2,65 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/fanmania/backend/internal/domain/errors"
 	"github.com/fanmania/backend/internal/domain/models"
 	"github.com/fanmania/backend/internal/repository/postgres"
 	"github.com/fanmania/backend/pkg/jwt"
+	"github.com/fanmania/backend/pkg/mailer"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Single-use account token purposes and lifetimes
+const (
+	tokenPurposeEmailVerification = "email_verification"
+	tokenPurposePasswordReset     = "password_reset"
+
+	emailVerificationTTL = 24 * time.Hour
+	passwordResetTTL     = time.Hour
+
+	mailSendTimeout = 10 * time.Second
+)
+
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo *postgres.UserRepository
-	jwt      *jwt.TokenGenerator
+	txRunner         postgres.TxRunner
+	userRepo         *postgres.UserRepository
+	refreshTokenRepo *postgres.RefreshTokenRepository
+	userTokenRepo    *postgres.UserTokenRepository
+	jwt              *jwt.TokenGenerator
+	mailer           mailer.Mailer
+	publicURL        string
 }
 
 // NewAuthService creates a new AuthService
-func NewAuthService(userRepo *postgres.UserRepository, jwtGen *jwt.TokenGenerator) *AuthService {
+func NewAuthService(
+	txRunner postgres.TxRunner,
+	userRepo *postgres.UserRepository,
+	refreshTokenRepo *postgres.RefreshTokenRepository,
+	userTokenRepo *postgres.UserTokenRepository,
+	jwtGen *jwt.TokenGenerator,
+	mail mailer.Mailer,
+	publicURL string,
+) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		jwt:      jwtGen,
+		txRunner:         txRunner,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		userTokenRepo:    userTokenRepo,
+		jwt:              jwtGen,
+		mailer:           mail,
+		publicURL:        strings.TrimSuffix(publicURL, "/"),
 	}
 }
 
@@ -64,23 +102,16 @@ func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest)
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Generate tokens
-	accessToken, err := s.jwt.GenerateAccessToken(user.ID, user.Username)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
-	}
-
-	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID, user.Username)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
-	}
+	// Mail in the background; a mail failure should not fail or hold up
+	// registration, and the user can ask for a resend
+	s.background(func(ctx context.Context) {
+		if err := s.sendVerificationEmail(ctx, user); err != nil {
+			log.Printf("⚠ Failed to send verification email to user %s: %v", user.ID, err)
+		}
+	})
 
-	return &models.AuthResponse{
-		User:         user,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(s.jwt.GetAccessTokenExpiry().Seconds()),
-	}, nil
+	// Generate tokens for a new login session
+	return s.issueTokens(ctx, user, uuid.New(), uuid.New())
 }
 
 // Login authenticates a user
@@ -102,64 +133,368 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 	// Update last active
 	_ = s.userRepo.UpdateLastActive(ctx, user.ID)
 
-	// Generate tokens
-	accessToken, err := s.jwt.GenerateAccessToken(user.ID, user.Username)
+	// Generate tokens for a new login session
+	return s.issueTokens(ctx, user, uuid.New(), uuid.New())
+}
+
+// RefreshToken rotates a refresh token: the presented token is spent and a
+// new one in the same family is issued. Presenting a token that was already
+// rotated means it leaked, so the whole family is revoked.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
+	// Validate refresh token
+	claims, err := s.jwt.ValidateRefreshToken(refreshToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+		return nil, errors.ErrInvalidToken
 	}
 
-	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID, user.Username)
+	// Tokens issued before rotation existed carry no jti and are refused
+	tokenID, err := uuid.Parse(claims.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, errors.ErrInvalidToken
 	}
 
-	return &models.AuthResponse{
-		User:         user,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(s.jwt.GetAccessTokenExpiry().Seconds()),
-	}, nil
-}
-
-// RefreshToken generates new tokens from a refresh token
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
-	// Validate refresh token
-	claims, err := s.jwt.ValidateRefreshToken(refreshToken)
+	stored, err := s.refreshTokenRepo.GetByID(ctx, tokenID)
 	if err != nil {
+		return nil, err
+	}
+	if stored.UserID != claims.UserID || stored.FamilyID != claims.SessionID {
+		return nil, errors.ErrInvalidToken
+	}
+	if stored.UsedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+	if stored.RevokedAt != nil {
 		return nil, errors.ErrInvalidToken
 	}
 
 	// Get user to ensure they still exist and are active
 	user, err := s.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
-		return nil, errors.ErrInvalidToken
+		if err == errors.ErrUserNotFound {
+			if _, err := s.refreshTokenRepo.RevokeAllForUser(ctx, claims.UserID); err != nil {
+				return nil, err
+			}
+			return nil, errors.ErrAccountDisabled
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var resp *models.AuthResponse
+	newTokenID := uuid.New()
+	err = s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = s.issueTokens(ctx, user, newTokenID, stored.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		rotated, err := s.refreshTokenRepo.MarkUsed(ctx, stored.ID, newTokenID)
+		if err != nil {
+			return err
+		}
+		if !rotated {
+			// Lost a race with another request presenting the same token
+			return errors.ErrTokenReused
+		}
+		return nil
+	})
+	if err == errors.ErrTokenReused {
+		return nil, s.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// revokeReusedFamily revokes a token family after reuse was detected
+func (s *AuthService) revokeReusedFamily(ctx context.Context, familyID uuid.UUID) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return errors.ErrTokenReused
+}
+
+// Logout revokes the login session the access token belongs to
+func (s *AuthService) Logout(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if sessionID == uuid.Nil {
+		return errors.ErrInvalidToken
+	}
+	return s.refreshTokenRepo.RevokeUserFamily(ctx, userID, sessionID)
+}
+
+// LogoutAll revokes every login session of a user
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	_, err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID)
+	return err
+}
+
+// ResendVerificationEmail sends a fresh verification link, invalidating any
+// earlier one. It does nothing for users who are already verified.
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsVerified {
+		return nil
+	}
+
+	return s.sendVerificationEmail(ctx, user)
+}
+
+// VerifyEmail consumes a verification token and marks its user verified
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	return s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		userID, err := s.userTokenRepo.Consume(ctx, tokenPurposeEmailVerification, hashAccountToken(token))
+		if err != nil {
+			return err
+		}
+		return s.userRepo.SetVerified(ctx, userID)
+	})
+}
+
+// ForgotPassword emails a password reset link. It reports success whether
+// or not the email belongs to an account, and the link is created and
+// mailed in the background so the response takes as long either way. It
+// cannot be used to probe for registered addresses.
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err == errors.ErrUserNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	s.background(func(ctx context.Context) {
+		if err := s.sendPasswordResetEmail(ctx, user); err != nil {
+			log.Printf("⚠ Failed to send password reset email to user %s: %v", user.ID, err)
+		}
+	})
+
+	return nil
+}
+
+// sendPasswordResetEmail creates a password reset token and mails its link
+func (s *AuthService) sendPasswordResetEmail(ctx context.Context, user *models.User) error {
+	token, err := s.createAccountToken(ctx, user.ID, tokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.sendMail(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Fanmania password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password for your Fanmania account. "+
+				"If it was you, open this link within %d minutes:\n\n%s\n\n"+
+				"If not, you can ignore this email; your password has not changed.\n",
+			user.Username, int(passwordResetTTL.Minutes()), s.link("/reset-password", token),
+		),
+	})
+}
+
+// ResetPassword consumes a reset token and sets a new password. Every
+// session is logged out, and the email counts as verified since the user
+// proved they can read it.
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Generate new tokens
-	newAccessToken, err := s.jwt.GenerateAccessToken(user.ID, user.Username)
+	return s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		userID, err := s.userTokenRepo.Consume(ctx, tokenPurposePasswordReset, hashAccountToken(token))
+		if err != nil {
+			return err
+		}
+		if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+			return err
+		}
+		if err := s.userRepo.SetVerified(ctx, userID); err != nil {
+			return err
+		}
+		_, err = s.refreshTokenRepo.RevokeAllForUser(ctx, userID)
+		return err
+	})
+}
+
+// IsEmailVerified reports whether a user has verified their email address
+func (s *AuthService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.IsVerified, nil
+}
+
+// sendVerificationEmail creates a verification token and mails its link
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *models.User) error {
+	token, err := s.createAccountToken(ctx, user.ID, tokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.sendMail(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Fanmania email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nConfirm your email address by opening this link within %d hours:\n\n%s\n",
+			user.Username, int(emailVerificationTTL.Hours()), s.link("/verify-email", token),
+		),
+	})
+}
+
+// createAccountToken generates a random single-use token and stores its hash
+func (s *AuthService) createAccountToken(
+	ctx context.Context,
+	userID uuid.UUID,
+	purpose string,
+	ttl time.Duration,
+) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.userTokenRepo.Create(ctx, userID, purpose, hashAccountToken(token), time.Now().Add(ttl)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// sendMail sends msg with a bounded timeout
+func (s *AuthService) sendMail(ctx context.Context, msg mailer.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, mailSendTimeout)
+	defer cancel()
+	return s.mailer.Send(ctx, msg)
+}
+
+// background runs fn detached from the request, so mail delivery never
+// holds up or shows in a response. fn gets mailSendTimeout to finish.
+func (s *AuthService) background(fn func(ctx context.Context)) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+		fn(ctx)
+	}()
+}
+
+// link builds an emailed link carrying token
+func (s *AuthService) link(path, token string) string {
+	return s.publicURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// hashAccountToken hashes a token for storage; tokens are never stored in plaintext
+func hashAccountToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// issueTokens stores refresh token tokenID in the given family and signs an
+// access/refresh token pair for it
+func (s *AuthService) issueTokens(
+	ctx context.Context,
+	user *models.User,
+	tokenID, familyID uuid.UUID,
+) (*models.AuthResponse, error) {
+	stored := &models.RefreshToken{
+		ID:        tokenID,
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(s.jwt.GetRefreshTokenExpiry()),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.jwt.GenerateAccessToken(user.ID, user.Username, user.Role, familyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	newRefreshToken, err := s.jwt.GenerateRefreshToken(user.ID, user.Username)
+	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID, user.Username, stored.ID, familyID, stored.ExpiresAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
 	return &models.AuthResponse{
 		User:         user,
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 		ExpiresIn:    int64(s.jwt.GetAccessTokenExpiry().Seconds()),
 	}, nil
 }
 
 // ValidateToken validates an access token and returns user ID
 func (s *AuthService) ValidateToken(tokenString string) (uuid.UUID, error) {
-	claims, err := s.jwt.ValidateAccessToken(tokenString)
+	claims, err := s.ValidateAccessToken(tokenString)
 	if err != nil {
-		return uuid.Nil, errors.ErrInvalidToken
+		return uuid.Nil, err
 	}
 
 	return claims.UserID, nil
 }
+
+// ValidateAccessToken validates an access token and returns its claims
+func (s *AuthService) ValidateAccessToken(tokenString string) (*jwt.Claims, error) {
+	claims, err := s.jwt.ValidateAccessToken(tokenString)
+	if err != nil {
+		return nil, errors.ErrInvalidToken
+	}
+
+	// Tokens issued before roles existed carry no role claim
+	if claims.Role == "" {
+		claims.Role = models.RoleUser
+	}
+
+	return claims, nil
+}
+
+// UpdateUserRole changes the role of a user. Admins cannot change their own
+// role, so the last admin can never lock themselves out.
+func (s *AuthService) UpdateUserRole(ctx context.Context, actorID, userID uuid.UUID, role string) (*models.User, error) {
+	if !models.IsValidRole(role) {
+		return nil, errors.ErrInvalidRole
+	}
+	if actorID == userID {
+		return nil, errors.ErrForbidden
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+// BootstrapAdmin promotes the user with the given email to admin, but only
+// while no admin exists yet. It returns true if a promotion happened.
+func (s *AuthService) BootstrapAdmin(ctx context.Context, email string) (bool, error) {
+	if email == "" {
+		return false, nil
+	}
+
+	exists, err := s.userRepo.RoleExists(ctx, models.RoleAdmin)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for existing admin: %w", err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, user.ID, models.RoleAdmin); err != nil {
+		return false, fmt.Errorf("failed to promote admin: %w", err)
+	}
+
+	return true, nil
+}
@@ -6,43 +6,106 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log"
+	"math"
 	"math/rand"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/fanmania/backend/internal/ai"
 	"github.com/fanmania/backend/internal/domain/errors"
 	"github.com/fanmania/backend/internal/domain/models"
 	"github.com/fanmania/backend/internal/repository/postgres"
+	"github.com/fanmania/backend/pkg/jwt"
 	"github.com/google/uuid"
 )
 
+const (
+	// poolTargetSize is the stock of challenges automatic generation keeps
+	// in each category tier; minPoolRefill is the fewest queued at once
+	poolTargetSize = 50
+	minPoolRefill  = 5
+	// poolCheckCooldown is how often one category tier's stock is checked,
+	// however many users fetch from it
+	poolCheckCooldown = 10 * time.Minute
+	// defaultBasePoints and defaultTimeLimitSeconds apply to hand-authored
+	// challenges that do not set their own
+	defaultBasePoints       = 100
+	defaultTimeLimitSeconds = 60
+)
+
 // ChallengeService handles challenge business logic
 type ChallengeService struct {
-	challengeRepo      *postgres.ChallengeRepository
-	userRepo           *postgres.UserRepository
-	categoryRepo       *postgres.CategoryRepository
-	aiChallengeService *AIChallengeService
+	txRunner            postgres.TxRunner
+	challengeRepo       *postgres.ChallengeRepository
+	userRepo            *postgres.UserRepository
+	categoryRepo        *postgres.CategoryRepository
+	rankingService      *RankingService
+	streakService       *StreakService
+	skillService        *SkillService
+	notificationService *NotificationService
+	generationQueue     *GenerationQueue
+	legalValidator      *ai.LegalValidator
+	jwt                 *jwt.TokenGenerator
+	sessionOpts         SessionOptions
+
+	poolCheckMu sync.Mutex
+	poolChecked map[poolKey]time.Time
+}
+
+// poolKey identifies a category tier's challenge pool
+type poolKey struct {
+	categoryID uuid.UUID
+	tier       int
+}
+
+// SessionOptions tunes challenge attempt sessions
+type SessionOptions struct {
+	GracePeriod       time.Duration // added to a challenge's time limit to absorb network latency
+	TokenTTL          time.Duration // how long a started challenge's session token can be submitted with
+	AllowMissingToken bool          // accept submissions from clients that do not send session tokens yet
 }
 
 // NewChallengeService creates a new ChallengeService
 func NewChallengeService(
+	txRunner postgres.TxRunner,
 	challengeRepo *postgres.ChallengeRepository,
 	userRepo *postgres.UserRepository,
 	categoryRepo *postgres.CategoryRepository,
+	rankingService *RankingService,
+	streakService *StreakService,
+	skillService *SkillService,
+	notificationService *NotificationService,
+	legalValidator *ai.LegalValidator,
+	jwtGen *jwt.TokenGenerator,
+	sessionOpts SessionOptions,
 ) *ChallengeService {
 	return &ChallengeService{
-		challengeRepo: challengeRepo,
-		userRepo:      userRepo,
-		categoryRepo:  categoryRepo,
+		txRunner:            txRunner,
+		challengeRepo:       challengeRepo,
+		userRepo:            userRepo,
+		categoryRepo:        categoryRepo,
+		rankingService:      rankingService,
+		streakService:       streakService,
+		skillService:        skillService,
+		notificationService: notificationService,
+		legalValidator:      legalValidator,
+		jwt:                 jwtGen,
+		sessionOpts:         sessionOpts,
+		poolChecked:         make(map[poolKey]time.Time),
 	}
 }
 
-// SetAIChallengeService sets the AI challenge service for on-demand generation
-func (s *ChallengeService) SetAIChallengeService(aiService *AIChallengeService) {
-	s.aiChallengeService = aiService
+// SetGenerationQueue sets the queue used to top up under-stocked challenge pools
+func (s *ChallengeService) SetGenerationQueue(queue *GenerationQueue) {
+	s.generationQueue = queue
 }
 
-// GetChallengesForUser retrieves available challenges for a user
+// GetChallengesForUser retrieves available challenges for a user. Without
+// a difficulty tier, challenges are picked to match the user's skill in
+// the category.
 func (s *ChallengeService) GetChallengesForUser(
 	ctx context.Context,
 	userID uuid.UUID,
@@ -56,81 +119,251 @@ func (s *ChallengeService) GetChallengesForUser(
 		return nil, err
 	}
 
+	var target *float64
+	tier := 0
+	if difficultyTier != nil {
+		tier = *difficultyTier
+	} else {
+		difficulty, err := s.skillService.TargetDifficulty(ctx, userID, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		target = &difficulty
+		tier = models.NearestTier(difficulty)
+	}
+
 	// Get challenges user hasn't attempted
 	challenges, err := s.challengeRepo.GetAvailableChallengesForUser(
-		ctx, userID, categoryID, difficultyTier, limit,
+		ctx, userID, categoryID, difficultyTier, target, limit,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get challenges: %w", err)
 	}
 
-	// If not enough challenges and AI service is configured, generate more
-	// Generate when pool is less than requested limit
-	if len(challenges) < limit && s.aiChallengeService != nil {
-		tier := 1
-		if difficultyTier != nil {
-			tier = *difficultyTier
-		}
+	s.topUpPool(ctx, categoryID, tier)
+
+	// Shuffle options and remove the answer and its explanation before returning
+	for i := range challenges {
+		challenges[i].CorrectAnswerHash = ""
+		challenges[i].Explanation = nil
+		challenges[i].Sources = nil
+		// Shuffle the options in question data so correct answer isn't always first
+		challenges[i].QuestionData = s.shuffleQuestionOptions(challenges[i].QuestionData)
+	}
 
-		// How many more do we need?
-		needed := limit - len(challenges)
+	return challenges, nil
+}
 
-		// Generate a few challenges synchronously (to avoid timeout)
-		// Limit to 3 synchronous generations to keep response time fast
-		syncLimit := 3
-		if needed < syncLimit {
-			syncLimit = needed
-		}
+// topUpPool queues generation in the background when a category tier's
+// pool runs below poolTargetSize. The pool is judged by its own size, not
+// by what one user has left to play, and checked at most once per
+// poolCheckCooldown; concurrent requests for the same pool are merged
+// into one job.
+func (s *ChallengeService) topUpPool(ctx context.Context, categoryID uuid.UUID, tier int) {
+	if s.generationQueue == nil || !s.claimPoolCheck(poolKey{categoryID: categoryID, tier: tier}) {
+		return
+	}
 
-		for i := 0; i < syncLimit; i++ {
-			result, err := s.aiChallengeService.GenerateAndSaveChallenge(
-				ctx, categoryID, tier, "multiple_choice",
-			)
-			if err != nil || !result.Success {
-				continue
-			}
-			challenges = append(challenges, *result.Challenge)
+	size, err := s.challengeRepo.CountPool(ctx, categoryID, tier)
+	if err != nil {
+		log.Printf("⚠ Failed to check challenge pool: %v", err)
+		return
+	}
+	if size >= poolTargetSize {
+		return
+	}
+
+	refill := poolTargetSize - size
+	if refill < minPoolRefill {
+		refill = minPoolRefill
+	}
+	_, _, err = s.generationQueue.Enqueue(ctx, GenerationRequest{
+		CategoryID:     categoryID,
+		DifficultyTier: tier,
+		ChallengeType:  "multiple_choice",
+		Count:          refill,
+		Source:         models.GenerationSourceAuto,
+	})
+	if err != nil {
+		log.Printf("⚠ Failed to queue challenge generation: %v", err)
+	}
+}
+
+// claimPoolCheck reports whether a pool is due a check, starting its
+// cooldown if so
+func (s *ChallengeService) claimPoolCheck(key poolKey) bool {
+	s.poolCheckMu.Lock()
+	defer s.poolCheckMu.Unlock()
+
+	now := time.Now()
+	if last, ok := s.poolChecked[key]; ok && now.Sub(last) < poolCheckCooldown {
+		return false
+	}
+	s.poolChecked[key] = now
+	return true
+}
+
+// StartChallenge starts the clock on a challenge the user has opened and
+// returns the signed session token to submit the attempt with. Opening it
+// again while the clock runs returns the same session; once it has run
+// out unanswered, the challenge can be started over.
+func (s *ChallengeService) StartChallenge(
+	ctx context.Context,
+	userID uuid.UUID,
+	challengeID uuid.UUID,
+) (*models.StartChallengeResponse, error) {
+	challenge, err := s.challengeRepo.GetByID(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.ResolvedAt != nil {
+		return nil, errors.ErrAlreadyResolved
+	}
+	if challenge.ActiveFrom != nil && challenge.ActiveFrom.After(time.Now()) {
+		return nil, errors.ErrChallengeNotOpen
+	}
+	if challenge.ActiveUntil != nil && challenge.ActiveUntil.Before(time.Now()) {
+		return nil, errors.ErrChallengeExpired
+	}
+
+	attempted, err := s.challengeRepo.HasUserAttempted(ctx, userID, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check attempt: %w", err)
+	}
+	if attempted {
+		return nil, errors.ErrAlreadyAttempted
+	}
+
+	var allowedSeconds *int
+	if challenge.TimeLimitSeconds != nil {
+		allowed := *challenge.TimeLimitSeconds + int(s.sessionOpts.GracePeriod.Seconds())
+		allowedSeconds = &allowed
+	}
+
+	session, err := s.challengeRepo.StartSession(ctx, userID, challenge.ID, allowedSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.jwt.GenerateAttemptToken(
+		session.ID, userID, challenge.ID, time.Now().Add(s.sessionOpts.TokenTTL),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign challenge session: %w", err)
+	}
+
+	return &models.StartChallengeResponse{
+		SessionToken: token,
+		StartedAt:    session.StartedAt,
+		ExpiresAt:    session.ExpiresAt,
+	}, nil
+}
+
+// elapsedSeconds checks the attempt session for a submission and returns how
+// long the user took, measured from when the challenge was started. It is
+// nil for an older client that never started the challenge, which can only
+// happen while submissions without a session token are allowed.
+func (s *ChallengeService) elapsedSeconds(
+	ctx context.Context,
+	userID uuid.UUID,
+	req *models.SubmitChallengeRequest,
+) (*int, error) {
+	var session *models.ChallengeSession
+	if req.SessionToken == "" && s.sessionOpts.AllowMissingToken {
+		// Older clients neither start challenges nor send the token; their
+		// attempts go untimed and earn no speed bonus
+		var err error
+		session, err = s.challengeRepo.GetSessionFor(ctx, userID, req.ChallengeID)
+		if err == errors.ErrInvalidSession {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		claims, err := s.jwt.ValidateAttemptToken(req.SessionToken)
+		if err != nil {
+			return nil, errors.ErrInvalidSession
+		}
+		if claims.UserID != userID || claims.ChallengeID != req.ChallengeID {
+			return nil, errors.ErrInvalidSession
 		}
 
-		// Generate more challenges in background for future requests
-		// Always generate extra to build up the pool
-		bgCount := needed - syncLimit
-		if bgCount < 5 {
-			bgCount = 5 // Generate at least 5 in background
+		if session, err = s.challengeRepo.GetSession(ctx, claims.SessionID); err != nil {
+			return nil, err
 		}
-		go func() {
-			bgCtx := context.Background()
-			for i := 0; i < bgCount; i++ {
-				s.aiChallengeService.GenerateAndSaveChallenge(
-					bgCtx, categoryID, tier, "multiple_choice",
-				)
-			}
-		}()
+	}
+	if session.UserID != userID || session.ChallengeID != req.ChallengeID {
+		return nil, errors.ErrInvalidSession
 	}
 
-	// Shuffle options and remove correct answer hash before returning
-	for i := range challenges {
-		challenges[i].CorrectAnswerHash = ""
-		// Shuffle the options in question data so correct answer isn't always first
-		challenges[i].QuestionData = s.shuffleQuestionOptions(challenges[i].QuestionData)
+	now := time.Now()
+	if session.ExpiresAt != nil && now.After(*session.ExpiresAt) {
+		return nil, errors.ErrTimeLimitExceeded
 	}
 
-	return challenges, nil
+	elapsed := int(math.Ceil(now.Sub(session.StartedAt).Seconds()))
+	if elapsed < 1 {
+		elapsed = 1
+	}
+
+	return &elapsed, nil
 }
 
-// SubmitChallengeAttempt handles a user's challenge submission
+// SubmitChallengeAttempt handles a user's challenge submission.
+// The attempt, points, rankings and streaks are committed as one unit of
+// work: either all of them are recorded or none are.
 func (s *ChallengeService) SubmitChallengeAttempt(
 	ctx context.Context,
 	userID uuid.UUID,
 	req *models.SubmitChallengeRequest,
 ) (*models.ChallengeResult, error) {
-	// Get challenge
-	challenge, err := s.challengeRepo.GetByID(ctx, req.ChallengeID)
+	var result *models.ChallengeResult
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.submitChallengeAttempt(ctx, userID, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if challenge is expired
+	if result.Skill != nil && result.Skill.UnlockedTier != nil {
+		if err := s.skillService.NotifyTierUnlocked(
+			ctx, userID, result.Skill.CategoryID, *result.Skill.UnlockedTier,
+		); err != nil {
+			log.Printf("⚠ Failed to notify user %s of unlocked tier: %v", userID, err)
+		}
+	}
+
+	return result, nil
+}
+
+// submitChallengeAttempt does the work of SubmitChallengeAttempt inside its transaction
+func (s *ChallengeService) submitChallengeAttempt(
+	ctx context.Context,
+	userID uuid.UUID,
+	req *models.SubmitChallengeRequest,
+) (*models.ChallengeResult, error) {
+	if err := s.skillService.LockForAttempt(ctx); err != nil {
+		return nil, err
+	}
+
+	// Get and lock the challenge, so a prediction cannot be resolved
+	// between this check and the attempt being recorded as pending
+	challenge, err := s.challengeRepo.GetByIDForUpdate(ctx, req.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.ResolvedAt != nil {
+		return nil, errors.ErrAlreadyResolved
+	}
+
+	// Check if challenge is open
+	if challenge.ActiveFrom != nil && challenge.ActiveFrom.After(time.Now()) {
+		return nil, errors.ErrChallengeNotOpen
+	}
 	if challenge.ActiveUntil != nil && challenge.ActiveUntil.Before(time.Now()) {
 		return nil, errors.ErrChallengeExpired
 	}
@@ -144,32 +377,78 @@ func (s *ChallengeService) SubmitChallengeAttempt(
 		return nil, errors.ErrAlreadyAttempted
 	}
 
-	// Validate answer
-	isCorrect := s.validateAnswer(req.SelectedAnswer, challenge.CorrectAnswerHash)
+	// Time taken comes from the server-side session, never from the client
+	timeTaken, err := s.elapsedSeconds(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Grade answer
+	answer, score, err := s.grade(challenge, req)
+	if err != nil {
+		return nil, err
+	}
+	isCorrect := score >= 1
+
+	// Predictions are scored when they are resolved
+	pending := challenge.ChallengeType == "prediction"
 
 	// Calculate points
-	pointsEarned := s.calculatePoints(challenge, isCorrect, req.TimeTakenSeconds)
+	pointsEarned := 0
+	if !pending {
+		pointsEarned = s.calculatePoints(challenge, score, timeTaken)
+	}
 
 	// Hash the submitted answer (for analytics, don't store plaintext)
-	answerHash := s.hashAnswer(req.SelectedAnswer)
+	answerHash := s.hashAnswer(answer)
 
 	// Record attempt
 	attempt := &models.ChallengeAttempt{
-		UserID:           userID,
-		ChallengeID:      req.ChallengeID,
-		IsCorrect:        isCorrect,
-		PointsEarned:     pointsEarned,
-		TimeTakenSeconds: req.TimeTakenSeconds,
-		AnswerHash:       &answerHash,
+		UserID:            userID,
+		ChallengeID:       req.ChallengeID,
+		IsCorrect:         isCorrect,
+		Score:             score,
+		PointsEarned:      pointsEarned,
+		TimeTakenSeconds:  timeTaken,
+		AnswerHash:        &answerHash,
+		Pending:           pending,
+		ChallengeRevision: challenge.Revision,
 	}
 
 	if err := s.challengeRepo.RecordAttempt(ctx, attempt); err != nil {
+		if err == errors.ErrAlreadyAttempted {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to record attempt: %w", err)
 	}
 
-	// Update user points
-	if err := s.userRepo.UpdatePoints(ctx, userID, pointsEarned); err != nil {
-		return nil, fmt.Errorf("failed to update points: %w", err)
+	var skill *models.SkillUpdate
+	if !pending {
+		// Update category ranking
+		if err := s.rankingService.UpdateCategoryRanking(
+			ctx, userID, challenge.CategoryID, pointsEarned, isCorrect, attempt.AttemptedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		// Update user points and global rank
+		if err := s.rankingService.ApplyGlobalPoints(ctx, userID, pointsEarned); err != nil {
+			return nil, err
+		}
+
+		// Move the user's skill toward how they did
+		if skill, err = s.skillService.RecordAttempt(ctx, userID, challenge, score); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update the global streak and the streak for this category
+	globalStreak, streakUpdated, err := s.streakService.UpdateStreak(ctx, userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update streak: %w", err)
+	}
+	if _, _, err := s.streakService.UpdateStreak(ctx, userID, &challenge.CategoryID); err != nil {
+		return nil, fmt.Errorf("failed to update category streak: %w", err)
 	}
 
 	// Get updated user data
@@ -178,28 +457,171 @@ func (s *ChallengeService) SubmitChallengeAttempt(
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// TODO: Update streak (will implement in streak service)
-	streakUpdated := false
-	streakDays := 0
-
 	result := &models.ChallengeResult{
 		IsCorrect:      isCorrect,
+		Score:          score,
+		Pending:        pending,
 		PointsEarned:   pointsEarned,
 		NewTotalPoints: user.TotalPoints,
 		NewRank:        user.GlobalRank,
 		StreakUpdated:  streakUpdated,
-		StreakDays:     streakDays,
+		StreakDays:     globalStreak.CurrentStreak,
+		Skill:          skill,
 	}
 
-	// Add explanation if incorrect
-	if !isCorrect {
-		explanation := "Incorrect answer. Keep practicing!"
-		result.Explanation = &explanation
+	// Add a message if incorrect
+	if pending {
+		message := "Prediction recorded. You'll be notified when it's resolved."
+		result.Explanation = &message
+	} else if score > 0 && !isCorrect {
+		message := "Partially correct. Keep practicing!"
+		result.Explanation = &message
+	} else if !isCorrect {
+		message := "Incorrect answer. Keep practicing!"
+		result.Explanation = &message
+	}
+
+	// Now the user has answered, show them the answer and why. Predictions
+	// have no answer until they are resolved.
+	if !pending {
+		result.CorrectAnswer, result.CorrectOrder = s.revealAnswer(challenge)
+		result.AnswerExplanation = challenge.Explanation
+		result.Sources = challenge.Sources
 	}
 
 	return result, nil
 }
 
+// revealAnswer finds the correct answer to a challenge from its stored
+// question: the correct option ID, or for a timeline the event IDs in
+// order. Answers are only stored hashed, so each option is hashed and
+// compared. Nothing is returned if the answer can't be recovered.
+func (s *ChallengeService) revealAnswer(challenge *models.Challenge) (*string, []string) {
+	var data models.QuestionData
+	if err := json.Unmarshal(challenge.QuestionData, &data); err != nil {
+		return nil, nil
+	}
+
+	if challenge.ChallengeType == "timeline" {
+		// Timelines stored without event positions can't be put in order
+		if len(data.Events) == 0 {
+			return nil, nil
+		}
+		events := append([]models.TimelineEvent(nil), data.Events...)
+		sort.Slice(events, func(i, j int) bool {
+			return events[i].Position < events[j].Position
+		})
+		order := make([]string, len(events))
+		for i, event := range events {
+			order[i] = event.ID
+		}
+		return nil, order
+	}
+
+	for _, option := range data.Options {
+		if s.validateAnswer(option.ID, challenge.CorrectAnswerHash) {
+			id := option.ID
+			return &id, nil
+		}
+	}
+	return nil, nil
+}
+
+// grade scores a submission from 0 (wrong) to 1 (fully correct) and returns
+// the answer in the canonical form used for its hash
+func (s *ChallengeService) grade(
+	challenge *models.Challenge,
+	req *models.SubmitChallengeRequest,
+) (string, float64, error) {
+	switch challenge.ChallengeType {
+	case "timeline":
+		return s.gradeTimeline(challenge, req)
+	case "prediction":
+		// There is no correct answer until the prediction is resolved
+		answer, err := selectedOption(challenge, req.SelectedAnswer)
+		return answer, 0, err
+	case "pattern":
+		// A pattern is answered with a single option: the missing item
+		// or the odd one out
+		answer, err := selectedOption(challenge, req.SelectedAnswer)
+		if err != nil {
+			return "", 0, err
+		}
+		if s.validateAnswer(answer, challenge.CorrectAnswerHash) {
+			return answer, 1, nil
+		}
+		return answer, 0, nil
+	default:
+		if s.validateAnswer(req.SelectedAnswer, challenge.CorrectAnswerHash) {
+			return req.SelectedAnswer, 1, nil
+		}
+		return req.SelectedAnswer, 0, nil
+	}
+}
+
+// gradeTimeline scores an ordering by pairwise accuracy: the fraction of
+// event pairs the user put in the right relative order. The order may be
+// sent as selected_order or as comma-separated IDs in selected_answer.
+func (s *ChallengeService) gradeTimeline(
+	challenge *models.Challenge,
+	req *models.SubmitChallengeRequest,
+) (string, float64, error) {
+	submitted := req.SelectedOrder
+	if len(submitted) == 0 {
+		submitted = strings.Split(req.SelectedAnswer, ",")
+	}
+	order := make([]string, len(submitted))
+	for i, id := range submitted {
+		order[i] = strings.ToLower(strings.TrimSpace(id))
+	}
+	answer := strings.Join(order, ",")
+
+	var data models.QuestionData
+	if err := json.Unmarshal(challenge.QuestionData, &data); err != nil {
+		return "", 0, fmt.Errorf("invalid question data: %w", err)
+	}
+
+	// Timelines stored without event positions can only be graded all or nothing
+	if len(data.Events) == 0 {
+		if s.validateAnswer(answer, challenge.CorrectAnswerHash) {
+			return answer, 1, nil
+		}
+		return answer, 0, nil
+	}
+
+	if len(order) != len(data.Events) {
+		return "", 0, errors.ErrInvalidAnswer
+	}
+
+	positions := make(map[string]int, len(data.Events))
+	for _, event := range data.Events {
+		positions[strings.ToLower(event.ID)] = event.Position
+	}
+
+	seen := make(map[string]bool, len(order))
+	for _, id := range order {
+		if _, ok := positions[id]; !ok || seen[id] {
+			return "", 0, errors.ErrInvalidAnswer
+		}
+		seen[id] = true
+	}
+
+	pairs, ordered := 0, 0
+	for i := 0; i < len(order); i++ {
+		for j := i + 1; j < len(order); j++ {
+			pairs++
+			if positions[order[i]] < positions[order[j]] {
+				ordered++
+			}
+		}
+	}
+	if pairs == 0 {
+		return answer, 1, nil
+	}
+
+	return answer, float64(ordered) / float64(pairs), nil
+}
+
 // validateAnswer checks if the submitted answer matches the correct answer hash
 func (s *ChallengeService) validateAnswer(submittedAnswer, correctHash string) bool {
 	submittedHash := s.hashAnswer(submittedAnswer)
@@ -214,17 +636,30 @@ func (s *ChallengeService) hashAnswer(answer string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// calculatePoints determines points earned based on difficulty, speed, and correctness
+// calculatePoints determines points earned based on difficulty, speed, and
+// score. A partial score earns credit only for doing better than chance:
+// a random timeline order gets half its pairs right, so credit runs from 0
+// at a score of 0.5 to full points at 1. Only a wholly wrong answer is
+// penalized.
 func (s *ChallengeService) calculatePoints(
 	challenge *models.Challenge,
-	isCorrect bool,
+	score float64,
 	timeTaken *int,
 ) int {
-	if !isCorrect {
+	if score <= 0 {
 		// Penalty for wrong answer: lose 30% of base points
 		return -int(float64(challenge.BasePoints) * 0.3)
 	}
 
+	credit := 1.0
+	if score < 1 {
+		credit = 2*score - 1
+	}
+	if credit <= 0 {
+		// No better than chance: nothing gained, nothing lost
+		return 0
+	}
+
 	basePoints := float64(challenge.BasePoints)
 
 	// Difficulty multiplier
@@ -246,6 +681,11 @@ func (s *ChallengeService) calculatePoints(
 
 	points := basePoints * difficultyMultiplier
 
+	// Partial credit, without the speed bonus
+	if credit < 1 {
+		return int(points * credit)
+	}
+
 	// Speed bonus: if completed in less than 50% of time limit
 	if timeTaken != nil && challenge.TimeLimitSeconds != nil {
 		timeLimit := float64(*challenge.TimeLimitSeconds)
@@ -296,9 +736,667 @@ func (s *ChallengeService) CreateChallenge(ctx context.Context, challenge *model
 		return fmt.Errorf("difficulty tier must be between 1 and 5")
 	}
 
+	// Predictions must close before they can be resolved
+	if challenge.ChallengeType == "prediction" && challenge.ActiveUntil == nil {
+		return fmt.Errorf("prediction challenges need an active_until time")
+	}
+
 	return s.challengeRepo.Create(ctx, challenge)
 }
 
+// AuthorChallenge creates a hand-written challenge after checking it with
+// the legal validator. The validation result is returned with the
+// challenge, or with errors.ErrContentRejected if the content failed.
+func (s *ChallengeService) AuthorChallenge(
+	ctx context.Context,
+	editorID uuid.UUID,
+	input *models.ChallengeInput,
+) (*models.Challenge, *ai.ValidationResult, error) {
+	challenge := &models.Challenge{
+		ChallengeType: input.ChallengeType,
+		IsActive:      true,
+	}
+
+	validation, err := s.applyInput(ctx, challenge, input)
+	if err != nil {
+		return nil, validation, err
+	}
+	challenge.EditedBy = &editorID
+
+	if err := s.CreateChallenge(ctx, challenge); err != nil {
+		return nil, validation, err
+	}
+
+	return challenge, validation, nil
+}
+
+// EditChallenge replaces a challenge's content and schedule, saving the
+// result as a new revision. Attempts keep the revision they were graded
+// against. The type of a challenge cannot change.
+func (s *ChallengeService) EditChallenge(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+	input *models.ChallengeInput,
+) (*models.Challenge, *ai.ValidationResult, error) {
+	var challenge *models.Challenge
+	var validation *ai.ValidationResult
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		challenge, err = s.challengeRepo.GetForUpdate(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+		if challenge.ChallengeType != input.ChallengeType {
+			return errors.ErrTypeChange
+		}
+
+		if validation, err = s.applyInput(ctx, challenge, input); err != nil {
+			return err
+		}
+		challenge.EditedBy = &editorID
+
+		return s.challengeRepo.Update(ctx, challenge)
+	})
+	if err != nil {
+		return nil, validation, err
+	}
+
+	return challenge, validation, nil
+}
+
+// ScheduleChallenge sets when a challenge takes attempts
+func (s *ChallengeService) ScheduleChallenge(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+	req *models.ChallengeScheduleRequest,
+) (*models.Challenge, error) {
+	if err := checkSchedule(req.ActiveFrom, req.ActiveUntil); err != nil {
+		return nil, err
+	}
+
+	return s.editChallenge(ctx, editorID, challengeID, func(challenge *models.Challenge) error {
+		if challenge.ChallengeType == "prediction" {
+			// Reopening a resolved prediction would take picks that can
+			// never be scored
+			if challenge.ResolvedAt != nil {
+				return errors.ErrAlreadyResolved
+			}
+			if req.ActiveUntil == nil {
+				return errors.ErrInvalidPredictionWindow
+			}
+		}
+
+		challenge.ActiveFrom = req.ActiveFrom
+		challenge.ActiveUntil = req.ActiveUntil
+		return nil
+	})
+}
+
+// SetChallengeActive activates or deactivates a challenge. Deactivated
+// challenges are no longer served or accepted but keep their attempts.
+func (s *ChallengeService) SetChallengeActive(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+	active bool,
+) (*models.Challenge, error) {
+	return s.editChallenge(ctx, editorID, challengeID, func(challenge *models.Challenge) error {
+		challenge.IsActive = active
+		return nil
+	})
+}
+
+// CloneChallenge copies a challenge into a new, inactive challenge with
+// its own revision history. A cloned prediction starts unresolved.
+func (s *ChallengeService) CloneChallenge(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+	req *models.CloneChallengeRequest,
+) (*models.Challenge, error) {
+	source, err := s.challengeRepo.GetAnyByID(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &models.Challenge{
+		CategoryID:        source.CategoryID,
+		Title:             source.Title,
+		Description:       source.Description,
+		QuestionData:      source.QuestionData,
+		CorrectAnswerHash: source.CorrectAnswerHash,
+		Explanation:       source.Explanation,
+		Sources:           source.Sources,
+		DifficultyTier:    source.DifficultyTier,
+		BasePoints:        source.BasePoints,
+		TimeLimitSeconds:  source.TimeLimitSeconds,
+		ChallengeType:     source.ChallengeType,
+		ActiveFrom:        source.ActiveFrom,
+		ActiveUntil:       source.ActiveUntil,
+		EditedBy:          &editorID,
+	}
+	if clone.ChallengeType == "prediction" {
+		clone.CorrectAnswerHash = ""
+	}
+
+	if req.CategoryID != nil {
+		clone.CategoryID = *req.CategoryID
+	}
+	if req.Title != nil {
+		clone.Title = *req.Title
+	}
+	if req.DifficultyTier != nil {
+		clone.DifficultyTier = *req.DifficultyTier
+	}
+	if req.ActiveFrom != nil || req.ActiveUntil != nil {
+		clone.ActiveFrom = req.ActiveFrom
+		clone.ActiveUntil = req.ActiveUntil
+	}
+	if err := checkSchedule(clone.ActiveFrom, clone.ActiveUntil); err != nil {
+		return nil, err
+	}
+
+	if err := s.CreateChallenge(ctx, clone); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+// GetChallengeForEditor retrieves a challenge, active or not
+func (s *ChallengeService) GetChallengeForEditor(ctx context.Context, challengeID uuid.UUID) (*models.Challenge, error) {
+	return s.challengeRepo.GetAnyByID(ctx, challengeID)
+}
+
+// ListChallengesForEditor lists challenges, active or not, newest first
+func (s *ChallengeService) ListChallengesForEditor(ctx context.Context, filter models.ChallengeFilter) ([]models.Challenge, error) {
+	return s.challengeRepo.List(ctx, filter)
+}
+
+// GetRevisions retrieves a challenge's revision history, latest first
+func (s *ChallengeService) GetRevisions(ctx context.Context, challengeID uuid.UUID) ([]models.ChallengeRevision, error) {
+	if _, err := s.challengeRepo.GetAnyByID(ctx, challengeID); err != nil {
+		return nil, err
+	}
+	return s.challengeRepo.ListRevisions(ctx, challengeID)
+}
+
+// GetRevision retrieves one revision of a challenge, such as the one an
+// attempt was graded against
+func (s *ChallengeService) GetRevision(ctx context.Context, challengeID uuid.UUID, revision int) (*models.ChallengeRevision, error) {
+	return s.challengeRepo.GetRevision(ctx, challengeID, revision)
+}
+
+// editChallenge applies edit to a locked challenge and saves it as a new
+// revision. Nothing is saved if edit leaves the challenge unchanged.
+func (s *ChallengeService) editChallenge(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+	edit func(challenge *models.Challenge) error,
+) (*models.Challenge, error) {
+	var challenge *models.Challenge
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		challenge, err = s.challengeRepo.GetForUpdate(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+
+		before := *challenge
+		if err := edit(challenge); err != nil {
+			return err
+		}
+		if challenge.IsActive == before.IsActive &&
+			sameTime(challenge.ActiveFrom, before.ActiveFrom) &&
+			sameTime(challenge.ActiveUntil, before.ActiveUntil) {
+			return nil
+		}
+
+		challenge.EditedBy = &editorID
+		return s.challengeRepo.Update(ctx, challenge)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// applyInput checks hand-authored content with the legal validator and
+// writes it to challenge
+func (s *ChallengeService) applyInput(
+	ctx context.Context,
+	challenge *models.Challenge,
+	input *models.ChallengeInput,
+) (*ai.ValidationResult, error) {
+	if err := checkSchedule(input.ActiveFrom, input.ActiveUntil); err != nil {
+		return nil, err
+	}
+
+	// Rules can be relaxed or tightened per category
+	category, err := s.categoryRepo.GetByID(ctx, input.CategoryID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	description := ""
+	if input.Description != nil {
+		description = *input.Description
+	}
+	explanation := ""
+	if input.Explanation != nil {
+		explanation = *input.Explanation
+	}
+	content := &ai.GeneratedChallenge{
+		Title:         input.Title,
+		Description:   description,
+		Question:      input.Question,
+		CorrectAnswer: input.CorrectAnswer,
+		Explanation:   explanation,
+		Sources:       input.Sources,
+	}
+	for _, opt := range input.Options {
+		content.Options = append(content.Options, ai.ChallengeOption{ID: opt.ID, Text: opt.Text})
+	}
+	if input.Pattern != nil {
+		content.PatternKind = input.Pattern.Kind
+		content.Sequence = input.Pattern.Sequence
+	}
+
+	validation := s.legalValidator.ValidateChallenge(content, input.ChallengeType, category.Slug)
+	if !validation.Passed {
+		return validation, errors.ErrContentRejected
+	}
+
+	content = s.legalValidator.SanitizeChallenge(content)
+	questionData, correctAnswer, err := buildQuestionData(input.ChallengeType, content)
+	if err != nil {
+		return validation, errors.ErrContentRejected
+	}
+	questionJSON, err := json.Marshal(questionData)
+	if err != nil {
+		return validation, fmt.Errorf("failed to marshal question data: %w", err)
+	}
+
+	challenge.CategoryID = input.CategoryID
+	challenge.Title = content.Title
+	challenge.Description = input.Description
+	challenge.QuestionData = questionJSON
+	challenge.CorrectAnswerHash = s.hashAnswer(correctAnswer)
+	challenge.Explanation = nil
+	if content.Explanation != "" {
+		challenge.Explanation = &content.Explanation
+	}
+	challenge.Sources = content.Sources
+	challenge.DifficultyTier = input.DifficultyTier
+	challenge.BasePoints = input.BasePoints
+	if challenge.BasePoints == 0 {
+		challenge.BasePoints = defaultBasePoints
+	}
+	challenge.TimeLimitSeconds = input.TimeLimitSeconds
+	if challenge.TimeLimitSeconds == nil {
+		timeLimit := defaultTimeLimitSeconds
+		challenge.TimeLimitSeconds = &timeLimit
+	}
+	challenge.ActiveFrom = input.ActiveFrom
+	challenge.ActiveUntil = input.ActiveUntil
+	if input.IsActive != nil {
+		challenge.IsActive = *input.IsActive
+	}
+
+	return validation, nil
+}
+
+// checkSchedule checks that a challenge closes after it opens
+func checkSchedule(activeFrom, activeUntil *time.Time) error {
+	if activeFrom != nil && activeUntil != nil && !activeUntil.After(*activeFrom) {
+		return errors.ErrInvalidSchedule
+	}
+	return nil
+}
+
+// sameTime reports whether two optional times are equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// CreatePrediction opens a prediction challenge. It takes picks between
+// active_from (default now) and active_until and has no correct answer
+// until ResolvePrediction is called.
+func (s *ChallengeService) CreatePrediction(
+	ctx context.Context,
+	editorID uuid.UUID,
+	req *models.CreatePredictionRequest,
+) (*models.Challenge, error) {
+	activeFrom := time.Now()
+	if req.ActiveFrom != nil {
+		activeFrom = *req.ActiveFrom
+	}
+	if !req.ActiveUntil.After(activeFrom) || !req.ActiveUntil.After(time.Now()) {
+		return nil, errors.ErrInvalidPredictionWindow
+	}
+
+	// Picks are matched case-insensitively, so option IDs must stay distinct
+	seen := make(map[string]bool, len(req.Options))
+	for _, opt := range req.Options {
+		id := strings.ToLower(strings.TrimSpace(opt.ID))
+		if seen[id] {
+			return nil, errors.ErrInvalidInput
+		}
+		seen[id] = true
+	}
+
+	questionData, err := json.Marshal(models.QuestionData{
+		Type:     "prediction",
+		Question: req.Question,
+		Options:  req.Options,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal question data: %w", err)
+	}
+
+	basePoints := req.BasePoints
+	if basePoints == 0 {
+		basePoints = defaultBasePoints
+	}
+
+	challenge := &models.Challenge{
+		CategoryID:     req.CategoryID,
+		Title:          req.Title,
+		Description:    req.Description,
+		QuestionData:   questionData,
+		DifficultyTier: req.DifficultyTier,
+		BasePoints:     basePoints,
+		ChallengeType:  "prediction",
+		IsActive:       true,
+		ActiveFrom:     &activeFrom,
+		ActiveUntil:    &req.ActiveUntil,
+		EditedBy:       &editorID,
+	}
+
+	if err := s.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// ResolvePrediction records the outcome of a prediction challenge and
+// scores every pick made on it. Scoring, rankings and the challenge's
+// resolution are committed together; users are notified afterwards.
+// Correct picks earn the challenge's points without a speed bonus and
+// wrong picks earn nothing.
+func (s *ChallengeService) ResolvePrediction(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+	correctAnswer string,
+) (*models.PredictionResolution, error) {
+	answer := strings.ToLower(strings.TrimSpace(correctAnswer))
+
+	var challenge *models.Challenge
+	var attempts []models.ChallengeAttempt
+	resolution := &models.PredictionResolution{
+		ChallengeID:   challengeID,
+		CorrectAnswer: answer,
+	}
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		challenge, err = s.challengeRepo.GetForUpdate(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+		if challenge.ChallengeType != "prediction" {
+			return errors.ErrNotPrediction
+		}
+		if challenge.ResolvedAt != nil {
+			return errors.ErrAlreadyResolved
+		}
+
+		var data models.QuestionData
+		if err := json.Unmarshal(challenge.QuestionData, &data); err != nil {
+			return fmt.Errorf("invalid question data: %w", err)
+		}
+		if !hasOption(data.Options, answer) {
+			return errors.ErrInvalidAnswer
+		}
+
+		answerHash := s.hashAnswer(answer)
+		if resolution.ResolvedAt, err = s.challengeRepo.Resolve(ctx, challengeID, answerHash, editorID); err != nil {
+			return err
+		}
+
+		resolution.PointsAwarded = s.calculatePoints(challenge, 1, nil)
+		attempts, err = s.challengeRepo.ScorePendingAttempts(ctx, challengeID, answerHash, resolution.PointsAwarded)
+		if err != nil {
+			return err
+		}
+
+		return s.rankingService.ApplyScoredAttempts(ctx, challenge.CategoryID, attempts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolution.Attempts = len(attempts)
+	for _, attempt := range attempts {
+		if attempt.IsCorrect {
+			resolution.Correct++
+		}
+
+		if err := s.notificationService.SendPredictionResolvedNotification(
+			ctx, attempt.UserID, challenge.Title, attempt.IsCorrect, attempt.PointsEarned,
+		); err != nil {
+			log.Printf("⚠ Failed to notify user %s of resolved prediction %s: %v", attempt.UserID, challengeID, err)
+		}
+	}
+
+	return resolution, nil
+}
+
+// RegradeChallenge corrects a challenge's answer and regrades every
+// attempt at it, adjusting points and rankings to match. A suspended
+// challenge goes back into play. Timelines cannot be regraded because
+// only a hash of each submitted order is kept.
+func (s *ChallengeService) RegradeChallenge(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+	correctAnswer string,
+) (*models.Regrade, error) {
+	var regrade *models.Regrade
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		challenge, err := s.challengeRepo.GetForUpdate(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+		if challenge.ChallengeType == "timeline" ||
+			(challenge.ChallengeType == "prediction" && challenge.ResolvedAt == nil) {
+			return errors.ErrCannotRegrade
+		}
+
+		answer, err := selectedOption(challenge, correctAnswer)
+		if err != nil {
+			return err
+		}
+
+		challenge.CorrectAnswerHash = s.hashAnswer(answer)
+		if challenge.SuspendedAt != nil {
+			challenge.IsActive = true
+		}
+		challenge.EditedBy = &editorID
+		if err := s.challengeRepo.Update(ctx, challenge); err != nil {
+			return err
+		}
+
+		attempts, err := s.challengeRepo.GetAttemptsForRegrade(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+
+		regrades := make([]models.AttemptRegrade, 0, len(attempts))
+		for _, attempt := range attempts {
+			if attempt.Pending || attempt.Voided {
+				continue
+			}
+
+			correct := attempt.AnswerHash != nil && *attempt.AnswerHash == challenge.CorrectAnswerHash
+			score := 0.0
+			if correct {
+				score = 1
+			}
+
+			// Predictions earn nothing for a wrong pick and have no speed bonus
+			var points int
+			if challenge.ChallengeType == "prediction" {
+				if correct {
+					points = s.calculatePoints(challenge, 1, nil)
+				}
+			} else {
+				points = s.calculatePoints(challenge, score, attempt.TimeTakenSeconds)
+			}
+
+			regrades = append(regrades, models.AttemptRegrade{
+				AttemptID:     attempt.ID,
+				UserID:        attempt.UserID,
+				Ranked:        true,
+				PointsBefore:  attempt.PointsEarned,
+				PointsAfter:   points,
+				CorrectBefore: attempt.IsCorrect,
+				CorrectAfter:  correct,
+				ScoreAfter:    score,
+				AttemptedAt:   attempt.AttemptedAt,
+			})
+		}
+
+		regrade, err = s.applyRegrade(ctx, challenge, regrades)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return regrade, nil
+}
+
+// VoidChallenge withdraws a challenge: it is deactivated and every attempt
+// at it stops counting, taking back the points it earned or cost
+func (s *ChallengeService) VoidChallenge(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+) (*models.Regrade, error) {
+	var regrade *models.Regrade
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		challenge, err := s.challengeRepo.GetForUpdate(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+
+		challenge.IsActive = false
+		challenge.EditedBy = &editorID
+		if err := s.challengeRepo.Update(ctx, challenge); err != nil {
+			return err
+		}
+
+		attempts, err := s.challengeRepo.GetAttemptsForRegrade(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+
+		regrades := make([]models.AttemptRegrade, 0, len(attempts))
+		for _, attempt := range attempts {
+			if attempt.Voided {
+				continue
+			}
+			regrades = append(regrades, models.AttemptRegrade{
+				AttemptID:     attempt.ID,
+				UserID:        attempt.UserID,
+				Ranked:        !attempt.Pending,
+				PointsBefore:  attempt.PointsEarned,
+				CorrectBefore: attempt.IsCorrect,
+				Voided:        true,
+				AttemptedAt:   attempt.AttemptedAt,
+			})
+		}
+
+		regrade, err = s.applyRegrade(ctx, challenge, regrades)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return regrade, nil
+}
+
+// applyRegrade saves regraded attempts and compensates rankings and totals
+func (s *ChallengeService) applyRegrade(
+	ctx context.Context,
+	challenge *models.Challenge,
+	regrades []models.AttemptRegrade,
+) (*models.Regrade, error) {
+	regrade := &models.Regrade{Attempts: len(regrades)}
+	for _, r := range regrades {
+		if r.Voided || r.PointsAfter != r.PointsBefore || r.CorrectAfter != r.CorrectBefore {
+			regrade.Changed++
+		}
+		if r.Ranked {
+			regrade.PointsDelta += r.PointsAfter - r.PointsBefore
+		}
+	}
+	if len(regrades) == 0 {
+		return regrade, nil
+	}
+
+	if err := s.challengeRepo.ApplyRegrade(ctx, challenge.ID, regrades); err != nil {
+		return nil, err
+	}
+	if err := s.rankingService.ApplyRegrade(ctx, challenge.CategoryID, regrades); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Regraded challenge %s: %d of %d attempts changed, %+d points",
+		challenge.ID, regrade.Changed, regrade.Attempts, regrade.PointsDelta)
+	return regrade, nil
+}
+
+// selectedOption normalizes a single-option answer and checks that it
+// names one of the challenge's options
+func selectedOption(challenge *models.Challenge, selected string) (string, error) {
+	var data models.QuestionData
+	if err := json.Unmarshal(challenge.QuestionData, &data); err != nil {
+		return "", fmt.Errorf("invalid question data: %w", err)
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(selected))
+	if !hasOption(data.Options, answer) {
+		return "", errors.ErrInvalidAnswer
+	}
+	return answer, nil
+}
+
+// hasOption reports whether id (lowercased) names one of options
+func hasOption(options []models.QuestionOption, id string) bool {
+	for _, opt := range options {
+		if strings.ToLower(strings.TrimSpace(opt.ID)) == id {
+			return true
+		}
+	}
+	return false
+}
+
 // GetUserAttemptStats gets user's attempt statistics
 func (s *ChallengeService) GetUserAttemptStats(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
 	// Get today's attempts
@@ -321,8 +1419,9 @@ func (s *ChallengeService) GetUserAttemptStats(ctx context.Context, userID uuid.
 	}, nil
 }
 
-// shuffleQuestionOptions shuffles the options in question data JSON
-// This ensures the correct answer isn't always in the same position
+// shuffleQuestionOptions shuffles the options and timeline events in
+// question data JSON so the correct answer isn't always in the same
+// position, and clears the events' positions
 func (s *ChallengeService) shuffleQuestionOptions(questionData json.RawMessage) json.RawMessage {
 	var data models.QuestionData
 	if err := json.Unmarshal(questionData, &data); err != nil {
@@ -336,6 +1435,14 @@ func (s *ChallengeService) shuffleQuestionOptions(questionData json.RawMessage)
 		})
 	}
 
+	// Shuffle the events and hide their order
+	rand.Shuffle(len(data.Events), func(i, j int) {
+		data.Events[i], data.Events[j] = data.Events[j], data.Events[i]
+	})
+	for i := range data.Events {
+		data.Events[i].Position = 0
+	}
+
 	// Re-marshal the shuffled data
 	shuffled, err := json.Marshal(data)
 	if err != nil {
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"math"
+	"testing"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+)
+
+func timelineChallenge(t *testing.T, s *ChallengeService, events []models.TimelineEvent, correct string) *models.Challenge {
+	t.Helper()
+	data, err := json.Marshal(models.QuestionData{Type: "timeline", Question: "Order these", Events: events})
+	if err != nil {
+		t.Fatalf("marshal question data: %v", err)
+	}
+	return &models.Challenge{
+		ChallengeType:     "timeline",
+		QuestionData:      data,
+		CorrectAnswerHash: s.hashAnswer(correct),
+	}
+}
+
+func TestGradeTimeline(t *testing.T) {
+	s := &ChallengeService{}
+	positioned := []models.TimelineEvent{
+		{ID: "a", Position: 1},
+		{ID: "b", Position: 2},
+		{ID: "c", Position: 3},
+		{ID: "d", Position: 4},
+	}
+
+	tests := []struct {
+		name       string
+		events     []models.TimelineEvent
+		order      []string
+		answer     string
+		wantAnswer string
+		wantScore  float64
+		wantErr    error
+	}{
+		{name: "in order", events: positioned, order: []string{"a", "b", "c", "d"}, wantAnswer: "a,b,c,d", wantScore: 1},
+		{name: "reversed", events: positioned, order: []string{"d", "c", "b", "a"}, wantAnswer: "d,c,b,a", wantScore: 0},
+		{name: "one neighbouring swap", events: positioned, order: []string{"b", "a", "c", "d"}, wantAnswer: "b,a,c,d", wantScore: 5.0 / 6},
+		{name: "ends swapped", events: positioned, order: []string{"d", "b", "c", "a"}, wantAnswer: "d,b,c,a", wantScore: 1.0 / 6},
+		{name: "comma separated answer", events: positioned, answer: " A, b ,C,d", wantAnswer: "a,b,c,d", wantScore: 1},
+		{name: "order preferred over answer", events: positioned, order: []string{"a", "b", "c", "d"}, answer: "d,c,b,a", wantAnswer: "a,b,c,d", wantScore: 1},
+		{name: "single event", events: []models.TimelineEvent{{ID: "a", Position: 1}}, order: []string{"a"}, wantAnswer: "a", wantScore: 1},
+		{name: "too few events", events: positioned, order: []string{"a", "b", "c"}, wantErr: errors.ErrInvalidAnswer},
+		{name: "repeated event", events: positioned, order: []string{"a", "a", "b", "c"}, wantErr: errors.ErrInvalidAnswer},
+		{name: "unknown event", events: positioned, order: []string{"a", "b", "c", "e"}, wantErr: errors.ErrInvalidAnswer},
+		{name: "no positions, right", order: []string{"a", "b", "c"}, wantAnswer: "a,b,c", wantScore: 1},
+		{name: "no positions, wrong", order: []string{"b", "a", "c"}, wantAnswer: "b,a,c", wantScore: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := timelineChallenge(t, s, tt.events, "a,b,c")
+			req := &models.SubmitChallengeRequest{SelectedOrder: tt.order, SelectedAnswer: tt.answer}
+
+			answer, score, err := s.gradeTimeline(challenge, req)
+			if tt.wantErr != nil {
+				if !stderrors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if answer != tt.wantAnswer {
+				t.Errorf("answer = %q, want %q", answer, tt.wantAnswer)
+			}
+			if math.Abs(score-tt.wantScore) > 1e-9 {
+				t.Errorf("score = %v, want %v", score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestCalculatePoints(t *testing.T) {
+	s := &ChallengeService{}
+	limit := 60
+	fast, slow := 20, 45
+
+	tests := []struct {
+		name      string
+		tier      int
+		timeLimit *int
+		score     float64
+		timeTaken *int
+		want      int
+	}{
+		{name: "wrong answer is penalized", tier: 3, timeLimit: &limit, score: 0, timeTaken: &fast, want: -30},
+		{name: "right answer", tier: 1, timeLimit: &limit, score: 1, timeTaken: &slow, want: 100},
+		{name: "difficulty multiplier", tier: 3, timeLimit: &limit, score: 1, timeTaken: &slow, want: 200},
+		{name: "top tier", tier: 5, timeLimit: &limit, score: 1, timeTaken: &slow, want: 500},
+		{name: "unknown tier", tier: 9, timeLimit: &limit, score: 1, timeTaken: &slow, want: 100},
+		{name: "speed bonus", tier: 2, timeLimit: &limit, score: 1, timeTaken: &fast, want: 180},
+		{name: "untimed attempt gets no speed bonus", tier: 2, timeLimit: &limit, score: 1, want: 150},
+		{name: "no time limit gets no speed bonus", tier: 2, score: 1, timeTaken: &fast, want: 150},
+		{name: "partial credit above chance", tier: 2, timeLimit: &limit, score: 0.75, timeTaken: &slow, want: 75},
+		{name: "partial credit skips speed bonus", tier: 2, timeLimit: &limit, score: 0.75, timeTaken: &fast, want: 75},
+		{name: "chance earns nothing", tier: 2, timeLimit: &limit, score: 0.5, timeTaken: &fast, want: 0},
+		{name: "worse than chance earns nothing", tier: 2, timeLimit: &limit, score: 0.2, timeTaken: &fast, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := &models.Challenge{
+				DifficultyTier:   tt.tier,
+				BasePoints:       100,
+				TimeLimitSeconds: tt.timeLimit,
+			}
+			if got := s.calculatePoints(challenge, tt.score, tt.timeTaken); got != tt.want {
+				t.Errorf("calculatePoints = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/fanmania/backend/internal/repository/postgres"
+)
+
+// DifficultyOptions tunes difficulty calibration
+type DifficultyOptions struct {
+	MinAttempts int     // scored attempts needed before a challenge is assessed
+	Tolerance   float64 // how far, in tiers, observed difficulty may stray from the tier before it is flagged
+	AutoRetier  bool    // move flagged challenges to their observed tier instead of only flagging them
+}
+
+// DifficultyService estimates how hard challenges really are from how
+// players do on them, and flags or re-tiers those whose tier is wrong
+type DifficultyService struct {
+	txRunner      postgres.TxRunner
+	challengeRepo *postgres.ChallengeRepository
+	opts          DifficultyOptions
+}
+
+// NewDifficultyService creates a new DifficultyService
+func NewDifficultyService(
+	txRunner postgres.TxRunner,
+	challengeRepo *postgres.ChallengeRepository,
+	opts DifficultyOptions,
+) *DifficultyService {
+	if opts.MinAttempts < 1 {
+		opts.MinAttempts = 1
+	}
+	return &DifficultyService{
+		txRunner:      txRunner,
+		challengeRepo: challengeRepo,
+		opts:          opts,
+	}
+}
+
+// Calibrate assesses every challenge in play with enough attempts. If
+// another process is already calibrating, the run is skipped.
+func (s *DifficultyService) Calibrate(ctx context.Context) (*models.CalibrationResult, error) {
+	result := &models.CalibrationResult{}
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		*result = models.CalibrationResult{}
+
+		locked, err := s.challengeRepo.TryLockDifficultyCalibration(ctx)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			result.Skipped = true
+			return nil
+		}
+
+		samples, err := s.challengeRepo.GetDifficultySamples(ctx, s.opts.MinAttempts)
+		if err != nil {
+			return err
+		}
+
+		assessments := make([]models.DifficultyAssessment, 0, len(samples))
+		for _, sample := range samples {
+			assessment := s.assess(sample)
+
+			tier := models.NearestTier(assessment.Empirical)
+			switch {
+			case assessment.Flag == nil:
+			case s.opts.AutoRetier && tier != sample.DifficultyTier:
+				if err := s.challengeRepo.Retier(ctx, sample.ChallengeID, tier); err != nil {
+					return err
+				}
+				assessment.Flag = nil
+				result.Retiered++
+			default:
+				result.Flagged++
+			}
+
+			assessments = append(assessments, assessment)
+		}
+
+		result.Assessed = len(assessments)
+		return s.challengeRepo.SaveDifficultyAssessments(ctx, assessments)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Run calibrates every interval until ctx is cancelled
+func (s *DifficultyService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			result, err := s.Calibrate(ctx)
+			if err != nil {
+				log.Printf("⚠ Failed to calibrate challenge difficulty: %v", err)
+			} else if !result.Skipped {
+				log.Printf("✓ Calibrated challenge difficulty (%d assessed, %d flagged, %d re-tiered)",
+					result.Assessed, result.Flagged, result.Retiered)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// assess estimates a challenge's difficulty and flags it if the estimate
+// is more than the tolerance away from its tier
+func (s *DifficultyService) assess(sample models.DifficultySample) models.DifficultyAssessment {
+	assessment := models.DifficultyAssessment{
+		ChallengeID:     sample.ChallengeID,
+		Empirical:       empiricalDifficulty(sample),
+		SolveRate:       sample.SolveRate,
+		MedianTimeRatio: sample.MedianTimeRatio,
+		SampleSize:      sample.Attempts,
+	}
+
+	gap := assessment.Empirical - float64(sample.DifficultyTier)
+	switch {
+	case gap > s.opts.Tolerance:
+		flag := models.DifficultyTooHard
+		assessment.Flag = &flag
+	case gap < -s.opts.Tolerance:
+		flag := models.DifficultyTooEasy
+		assessment.Flag = &flag
+	}
+
+	return assessment
+}
+
+// empiricalDifficulty places a challenge on the 1-5 tier scale. The solve
+// rate is first corrected for guessing, so a true/false question half the
+// players get right counts as unsolved rather than middling. Each tier then
+// spans a fifth of the corrected rate, from tier 1 at 80% and above down
+// to tier 5 at none, and needing more of the time limit than usual adds up
+// to a quarter of a tier.
+func empiricalDifficulty(sample models.DifficultySample) float64 {
+	chance := guessRate(sample)
+	corrected := 0.0
+	if chance < 1 {
+		corrected = (sample.SolveRate - chance) / (1 - chance)
+	}
+	corrected = math.Max(0, math.Min(1, corrected))
+
+	difficulty := 1 + (0.8-corrected)/0.2
+	difficulty += (sample.MedianTimeRatio - 0.5) / 2
+
+	return math.Max(1, math.Min(5, difficulty))
+}
+
+// guessRate is the score expected from answering a challenge at random
+func guessRate(sample models.DifficultySample) float64 {
+	switch sample.ChallengeType {
+	case "true_false":
+		return 0.5
+	case "timeline":
+		// Timelines are scored by pairwise accuracy, and a random order
+		// puts half the pairs the right way round on average
+		return 0.5
+	case "multiple_choice", "pattern":
+		if sample.OptionCount > 1 {
+			return 1 / float64(sample.OptionCount)
+		}
+	}
+	return 0
+}
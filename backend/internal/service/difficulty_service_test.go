@@ -0,0 +1,69 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fanmania/backend/internal/domain/models"
+)
+
+func TestGuessRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample models.DifficultySample
+		want   float64
+	}{
+		{name: "true/false", sample: models.DifficultySample{ChallengeType: "true_false"}, want: 0.5},
+		{name: "timeline", sample: models.DifficultySample{ChallengeType: "timeline", OptionCount: 5}, want: 0.5},
+		{name: "four options", sample: models.DifficultySample{ChallengeType: "multiple_choice", OptionCount: 4}, want: 0.25},
+		{name: "pattern", sample: models.DifficultySample{ChallengeType: "pattern", OptionCount: 5}, want: 0.2},
+		{name: "single option", sample: models.DifficultySample{ChallengeType: "multiple_choice", OptionCount: 1}, want: 0},
+		{name: "options unknown", sample: models.DifficultySample{ChallengeType: "multiple_choice"}, want: 0},
+		{name: "prediction", sample: models.DifficultySample{ChallengeType: "prediction", OptionCount: 2}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := guessRate(tt.sample); got != tt.want {
+				t.Errorf("guessRate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmpiricalDifficulty(t *testing.T) {
+	tests := []struct {
+		name          string
+		challengeType string
+		options       int
+		solveRate     float64
+		timeRatio     float64
+		want          float64
+	}{
+		{name: "solved above chance by 80% is tier 1", challengeType: "multiple_choice", options: 4, solveRate: 0.85, timeRatio: 0.5, want: 1},
+		{name: "solved at chance is tier 5", challengeType: "multiple_choice", options: 4, solveRate: 0.25, timeRatio: 0.5, want: 5},
+		{name: "below chance is clamped to tier 5", challengeType: "multiple_choice", options: 4, solveRate: 0.1, timeRatio: 0.5, want: 5},
+		{name: "middling solve rate", challengeType: "multiple_choice", options: 4, solveRate: 0.55, timeRatio: 0.5, want: 3},
+		{name: "slow answers add up to a quarter tier", challengeType: "multiple_choice", options: 4, solveRate: 0.55, timeRatio: 1, want: 3.25},
+		{name: "fast answers take off up to a quarter tier", challengeType: "multiple_choice", options: 4, solveRate: 0.55, timeRatio: 0, want: 2.75},
+		{name: "always solved quickly is clamped to tier 1", challengeType: "multiple_choice", options: 4, solveRate: 1, timeRatio: 0, want: 1},
+		{name: "true/false half right counts as unsolved", challengeType: "true_false", solveRate: 0.5, timeRatio: 0.5, want: 5},
+		{name: "true/false mostly right", challengeType: "true_false", solveRate: 0.9, timeRatio: 0.5, want: 1},
+		{name: "timeline three quarters of pairs right", challengeType: "timeline", solveRate: 0.75, timeRatio: 0.5, want: 2.5},
+		{name: "no guessing correction without options", challengeType: "multiple_choice", solveRate: 0.4, timeRatio: 0.5, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample := models.DifficultySample{
+				ChallengeType:   tt.challengeType,
+				OptionCount:     tt.options,
+				SolveRate:       tt.solveRate,
+				MedianTimeRatio: tt.timeRatio,
+			}
+			if got := empiricalDifficulty(sample); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("empiricalDifficulty = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
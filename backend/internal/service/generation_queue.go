@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/fanmania/backend/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxConsecutiveFailures ends a job attempt after this many generations
+	// in a row fail, so a broken prompt or provider outage backs off
+	maxConsecutiveFailures = 3
+	// maxRetryBackoff caps the delay between job attempts
+	maxRetryBackoff = 30 * time.Minute
+)
+
+// GenerationQueueOptions tunes the generation worker pool
+type GenerationQueueOptions struct {
+	Workers      int           // concurrent jobs per process
+	PollInterval time.Duration // how often idle workers check for new jobs
+	MaxAttempts  int           // attempts before a job is marked failed
+	RetryBackoff time.Duration // delay before the first retry; doubles each attempt
+	StaleAfter   time.Duration // running jobs not heard from for this long are requeued
+}
+
+// GenerationRequest describes challenges to generate
+type GenerationRequest struct {
+	CategoryID     uuid.UUID
+	DifficultyTier int
+	ChallengeType  string
+	Count          int
+	Source         string
+	RequestedBy    *uuid.UUID
+}
+
+// GenerationQueue queues AI challenge generation in Postgres and works
+// through it with a bounded pool of workers. Requests for the same
+// category, tier and type are merged, and failed jobs are retried with
+// exponential backoff.
+type GenerationQueue struct {
+	jobRepo   *postgres.GenerationJobRepository
+	aiService *AIChallengeService
+	opts      GenerationQueueOptions
+
+	wake     chan struct{}
+	stopping chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	// ctx is cancelled to abort in-flight generations when a drain times out
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewGenerationQueue creates a new GenerationQueue. Workers do not run
+// until Start is called.
+func NewGenerationQueue(
+	jobRepo *postgres.GenerationJobRepository,
+	aiService *AIChallengeService,
+	opts GenerationQueueOptions,
+) *GenerationQueue {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &GenerationQueue{
+		jobRepo:   jobRepo,
+		aiService: aiService,
+		opts:      opts,
+		wake:      make(chan struct{}, opts.Workers),
+		stopping:  make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Enqueue queues a generation request and returns the job it was stored
+// in. merged is true when the request joined a job already in the queue.
+func (q *GenerationQueue) Enqueue(ctx context.Context, req GenerationRequest) (job *models.GenerationJob, merged bool, err error) {
+	job = &models.GenerationJob{
+		CategoryID:     req.CategoryID,
+		DifficultyTier: req.DifficultyTier,
+		ChallengeType:  req.ChallengeType,
+		RequestedCount: req.Count,
+		Source:         req.Source,
+		RequestedBy:    req.RequestedBy,
+		MaxAttempts:    q.opts.MaxAttempts,
+	}
+
+	merged, err = q.jobRepo.Enqueue(ctx, job)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Nudge an idle worker rather than waiting for its next poll
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	return job, merged, nil
+}
+
+// GetJob retrieves a job so callers can poll its progress
+func (q *GenerationQueue) GetJob(ctx context.Context, id uuid.UUID) (*models.GenerationJob, error) {
+	return q.jobRepo.GetByID(ctx, id)
+}
+
+// ListJobs returns recent jobs, optionally filtered by status
+func (q *GenerationQueue) ListJobs(ctx context.Context, status string, limit int) ([]models.GenerationJob, error) {
+	return q.jobRepo.List(ctx, status, limit)
+}
+
+// Start requeues jobs abandoned by a previous process and starts the workers
+func (q *GenerationQueue) Start() {
+	q.requeueStale()
+
+	for i := 0; i < q.opts.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	q.wg.Add(1)
+	go q.reap()
+}
+
+// Shutdown stops claiming new jobs and waits for workers to hand back the
+// jobs they hold. Each worker finishes the generation it is making first;
+// if ctx expires before then, in-flight generations are aborted.
+func (q *GenerationQueue) Shutdown(ctx context.Context) error {
+	q.stopOnce.Do(func() { close(q.stopping) })
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work claims and runs jobs until the queue is shut down
+func (q *GenerationQueue) work() {
+	defer q.wg.Done()
+
+	for !q.isStopping() {
+		job, err := q.jobRepo.ClaimNext(q.ctx)
+		if err != nil {
+			log.Printf("⚠ Failed to claim generation job: %v", err)
+		}
+		if job == nil {
+			q.idle()
+			continue
+		}
+
+		q.run(job)
+	}
+}
+
+// idle waits for the next poll, a new job or shutdown
+func (q *GenerationQueue) idle() {
+	timer := time.NewTimer(q.opts.PollInterval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-q.wake:
+	case <-q.stopping:
+	}
+}
+
+// run generates challenges for job until it reaches its target, fails too
+// often in a row, or the queue shuts down
+func (q *GenerationQueue) run(job *models.GenerationJob) {
+	failures := 0
+	lastError := ""
+
+	for {
+		if job.Remaining() == 0 {
+			completed, err := q.jobRepo.Complete(context.Background(), job.ID)
+			if err != nil {
+				log.Printf("⚠ Failed to complete generation job %s: %v", job.ID, err)
+				return
+			}
+			if completed {
+				log.Printf("✓ Generation job %s finished (%d generated, %d failed)", job.ID, job.GeneratedCount, job.FailedCount)
+				return
+			}
+
+			// A merged request raised the target since the last result
+			if job, err = q.jobRepo.GetByID(context.Background(), job.ID); err != nil {
+				log.Printf("⚠ Failed to reload generation job: %v", err)
+				return
+			}
+			continue
+		}
+
+		if q.isStopping() {
+			q.release(job)
+			return
+		}
+
+		result, err := q.aiService.GenerateAndSaveChallenge(
+			q.ctx, job.CategoryID, job.DifficultyTier, job.ChallengeType,
+		)
+		if q.ctx.Err() != nil {
+			// Aborted by shutdown; not the job's fault
+			q.release(job)
+			return
+		}
+		if stderrors.Is(err, errors.ErrCategoryNotFound) {
+			q.fail(job, err.Error())
+			return
+		}
+
+		var challengeID *uuid.UUID
+		switch {
+		case err != nil:
+			lastError = err.Error()
+		case !result.Success:
+			lastError = result.Error
+		default:
+			challengeID = &result.Challenge.ID
+		}
+
+		updated, err := q.jobRepo.RecordResult(context.Background(), job.ID, challengeID)
+		if err != nil {
+			log.Printf("⚠ Failed to record progress on generation job %s: %v", job.ID, err)
+			q.retry(job, err.Error())
+			return
+		}
+		job = updated
+
+		if challengeID != nil {
+			failures = 0
+			continue
+		}
+
+		failures++
+		if failures >= maxConsecutiveFailures {
+			q.retry(job, lastError)
+			return
+		}
+	}
+}
+
+// retry schedules another attempt at job with exponential backoff, or
+// fails it once its attempts are used up
+func (q *GenerationQueue) retry(job *models.GenerationJob, lastError string) {
+	if job.Attempts >= job.MaxAttempts {
+		q.fail(job, lastError)
+		return
+	}
+
+	runAt := time.Now().Add(q.backoff(job.Attempts))
+	if err := q.jobRepo.Retry(context.Background(), job.ID, lastError, runAt); err != nil {
+		log.Printf("⚠ Failed to reschedule generation job %s: %v", job.ID, err)
+		return
+	}
+	log.Printf("⚠ Generation job %s attempt %d failed, retrying at %s: %s",
+		job.ID, job.Attempts, runAt.Format(time.RFC3339), lastError)
+}
+
+// fail marks job as permanently failed
+func (q *GenerationQueue) fail(job *models.GenerationJob, lastError string) {
+	if err := q.jobRepo.Fail(context.Background(), job.ID, lastError); err != nil {
+		log.Printf("⚠ Failed to mark generation job %s failed: %v", job.ID, err)
+		return
+	}
+	log.Printf("✗ Generation job %s failed after %d attempts: %s", job.ID, job.Attempts, lastError)
+}
+
+// release hands job back to the queue for another worker or process
+func (q *GenerationQueue) release(job *models.GenerationJob) {
+	if err := q.jobRepo.Release(context.Background(), job.ID); err != nil {
+		log.Printf("⚠ Failed to release generation job %s: %v", job.ID, err)
+	}
+}
+
+// backoff is the delay before the retry following the given attempt
+func (q *GenerationQueue) backoff(attempts int) time.Duration {
+	delay := q.opts.RetryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
+
+// reap periodically requeues jobs held by workers that died mid-job
+func (q *GenerationQueue) reap() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.opts.StaleAfter)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			q.requeueStale()
+		case <-q.stopping:
+			return
+		}
+	}
+}
+
+func (q *GenerationQueue) requeueStale() {
+	count, err := q.jobRepo.RequeueStale(q.ctx, time.Now().Add(-q.opts.StaleAfter))
+	if err != nil {
+		log.Printf("⚠ Failed to requeue stale generation jobs: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("✓ Requeued %d stale generation jobs", count)
+	}
+}
+
+func (q *GenerationQueue) isStopping() bool {
+	select {
+	case <-q.stopping:
+		return true
+	default:
+		return false
+	}
+}
@@ -213,6 +213,34 @@ func (s *NotificationService) SendDifficultyProgressNotification(
 	)
 }
 
+// SendPredictionResolvedNotification tells a user how their pick on a
+// prediction challenge turned out
+func (s *NotificationService) SendPredictionResolvedNotification(
+	ctx context.Context,
+	userID uuid.UUID,
+	challengeTitle string,
+	isCorrect bool,
+	pointsEarned int,
+) error {
+	title := "Your prediction was resolved"
+	body := fmt.Sprintf("%s: your pick was wrong this time", challengeTitle)
+	if isCorrect {
+		body = fmt.Sprintf("%s: you called it! +%d points", challengeTitle, pointsEarned)
+	}
+	
+	expiresIn := 7 * 24 * time.Hour
+	
+	return s.CreateNotification(
+		ctx,
+		userID,
+		title,
+		body,
+		"prediction_resolved",
+		nil,
+		&expiresIn,
+	)
+}
+
 // CleanupExpiredNotifications removes expired notifications
 func (s *NotificationService) CleanupExpiredNotifications(ctx context.Context) error {
 	return s.notificationRepo.DeleteExpiredNotifications(ctx)
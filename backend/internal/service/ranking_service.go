@@ -31,183 +31,363 @@ func NewRankingService(
 	}
 }
 
-// UpdateCategoryRanking updates a user's ranking in a category
+// batchLockKey is the advisory lock batch updates take turns on. A batch
+// locks many users' rows in no set order, so two at once could deadlock.
+// Single submissions touch one user's rows and do not take it.
+const batchLockKey = "ranking:batch"
+
+// UpdateCategoryRanking updates a user's points in a category, and their
+// points for the day of the attempt. Ranks are worked out when read, so
+// no other user's row is touched. It joins the caller's transaction if
+// ctx carries one.
 func (s *RankingService) UpdateCategoryRanking(
 	ctx context.Context,
 	userID uuid.UUID,
 	categoryID uuid.UUID,
 	pointsDelta int,
 	isCorrect bool,
+	attemptedAt time.Time,
 ) error {
-	// Start transaction
-	tx, err := s.db.Pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// Upsert category ranking
-	query := `
-		INSERT INTO category_rankings (
-			user_id, category_id, points, 
-			challenges_completed, challenges_correct, 
-			last_activity, updated_at
-		) VALUES ($1, $2, $3, 1, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		ON CONFLICT (user_id, category_id) 
-		DO UPDATE SET
-			points = category_rankings.points + $3,
-			challenges_completed = category_rankings.challenges_completed + 1,
-			challenges_correct = category_rankings.challenges_correct + CASE WHEN $4 THEN 1 ELSE 0 END,
-			last_activity = CURRENT_TIMESTAMP,
-			updated_at = CURRENT_TIMESTAMP
-		RETURNING points, challenges_completed, challenges_correct
-	`
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := s.db.Conn(ctx)
+
+		// Upsert category ranking
+		query := `
+			INSERT INTO category_rankings (
+				user_id, category_id, points, 
+				challenges_completed, challenges_correct, 
+				last_activity, updated_at
+			) VALUES ($1, $2, $3, 1, CASE WHEN $4 THEN 1 ELSE 0 END, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			ON CONFLICT (user_id, category_id) 
+			DO UPDATE SET
+				points = category_rankings.points + $3,
+				challenges_completed = category_rankings.challenges_completed + 1,
+				challenges_correct = category_rankings.challenges_correct + CASE WHEN $4 THEN 1 ELSE 0 END,
+				last_activity = CURRENT_TIMESTAMP,
+				updated_at = CURRENT_TIMESTAMP
+			RETURNING points, challenges_completed, challenges_correct
+		`
 
-	var totalPoints int64
-	var completed, correct int
-	err = tx.QueryRow(ctx, query, userID, categoryID, pointsDelta, isCorrect).Scan(
-		&totalPoints, &completed, &correct,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to update category ranking: %w", err)
-	}
+		var totalPoints int64
+		var completed, correct int
+		err := conn.QueryRow(ctx, query, userID, categoryID, pointsDelta, isCorrect).Scan(
+			&totalPoints, &completed, &correct,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update category ranking: %w", err)
+		}
 
-	// Calculate mastery percentage
-	masteryPercentage := float64(0)
-	if completed > 0 {
-		masteryPercentage = (float64(correct) / float64(completed)) * 100
-	}
+		// Calculate mastery percentage
+		masteryPercentage := float64(0)
+		if completed > 0 {
+			masteryPercentage = (float64(correct) / float64(completed)) * 100
+		}
 
-	// Update mastery percentage
-	_, err = tx.Exec(ctx, `
-		UPDATE category_rankings 
-		SET mastery_percentage = $1
-		WHERE user_id = $2 AND category_id = $3
-	`, masteryPercentage, userID, categoryID)
-	if err != nil {
-		return fmt.Errorf("failed to update mastery: %w", err)
+		// Update mastery percentage
+		_, err = conn.Exec(ctx, `
+			UPDATE category_rankings 
+			SET mastery_percentage = $1
+			WHERE user_id = $2 AND category_id = $3
+		`, masteryPercentage, userID, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to update mastery: %w", err)
+		}
+
+		return s.addDailyPoints(ctx, categoryID, []uuid.UUID{userID}, []time.Time{attemptedAt}, []int{pointsDelta})
+	})
+}
+
+// ApplyGlobalPoints adds pointsDelta to a user's total. Global ranks are
+// worked out from totals when read, so no other user's row is touched.
+func (s *RankingService) ApplyGlobalPoints(ctx context.Context, userID uuid.UUID, pointsDelta int) error {
+	if pointsDelta == 0 {
+		return nil
 	}
+	if err := s.userRepo.UpdatePoints(ctx, userID, pointsDelta); err != nil {
+		return fmt.Errorf("failed to update points: %w", err)
+	}
+	return nil
+}
 
-	// Recalculate ranks for this category
-	if err := s.recalculateCategoryRanks(ctx, tx, categoryID); err != nil {
-		return fmt.Errorf("failed to recalculate ranks: %w", err)
+// ApplyScoredAttempts applies a batch of attempts at one challenge, scored
+// together when a prediction is resolved, to category and global points.
+// It joins the caller's transaction if ctx carries one.
+func (s *RankingService) ApplyScoredAttempts(
+	ctx context.Context,
+	categoryID uuid.UUID,
+	attempts []models.ChallengeAttempt,
+) error {
+	if len(attempts) == 0 {
+		return nil
 	}
 
-	// Commit transaction
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	userIDs := make([]uuid.UUID, len(attempts))
+	points := make([]int, len(attempts))
+	correct := make([]bool, len(attempts))
+	attemptedAt := make([]time.Time, len(attempts))
+	for i, attempt := range attempts {
+		userIDs[i] = attempt.UserID
+		points[i] = attempt.PointsEarned
+		correct[i] = attempt.IsCorrect
+		attemptedAt[i] = attempt.AttemptedAt
 	}
 
-	return nil
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := s.db.Conn(ctx)
+
+		if err := s.lock(ctx, batchLockKey); err != nil {
+			return err
+		}
+
+		_, err := conn.Exec(ctx, `
+			INSERT INTO category_rankings (
+				user_id, category_id, points,
+				challenges_completed, challenges_correct,
+				last_activity, updated_at
+			)
+			SELECT r.user_id, $1, r.points, 1, CASE WHEN r.is_correct THEN 1 ELSE 0 END,
+			       CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+			FROM unnest($2::uuid[], $3::int[], $4::bool[]) AS r(user_id, points, is_correct)
+			ON CONFLICT (user_id, category_id)
+			DO UPDATE SET
+				points = category_rankings.points + EXCLUDED.points,
+				challenges_completed = category_rankings.challenges_completed + 1,
+				challenges_correct = category_rankings.challenges_correct + EXCLUDED.challenges_correct,
+				last_activity = CURRENT_TIMESTAMP,
+				updated_at = CURRENT_TIMESTAMP
+		`, categoryID, userIDs, points, correct)
+		if err != nil {
+			return fmt.Errorf("failed to update category rankings: %w", err)
+		}
+
+		_, err = conn.Exec(ctx, `
+			UPDATE category_rankings
+			SET mastery_percentage = challenges_correct * 100.0 / challenges_completed
+			WHERE category_id = $1 AND user_id = ANY($2) AND challenges_completed > 0
+		`, categoryID, userIDs)
+		if err != nil {
+			return fmt.Errorf("failed to update mastery: %w", err)
+		}
+
+		if err := s.addDailyPoints(ctx, categoryID, userIDs, attemptedAt, points); err != nil {
+			return err
+		}
+
+		_, err = conn.Exec(ctx, `
+			UPDATE users u
+			SET total_points = u.total_points + r.points,
+			    updated_at = CURRENT_TIMESTAMP
+			FROM unnest($1::uuid[], $2::int[]) AS r(user_id, points)
+			WHERE u.id = r.user_id AND r.points <> 0
+		`, userIDs, points)
+		if err != nil {
+			return fmt.Errorf("failed to update points: %w", err)
+		}
+
+		return nil
+	})
 }
 
-// recalculateCategoryRanks recalculates all ranks for a category
-func (s *RankingService) recalculateCategoryRanks(ctx context.Context, tx pgx.Tx, categoryID uuid.UUID) error {
-	query := `
-		WITH ranked_users AS (
-			SELECT 
-				user_id,
-				ROW_NUMBER() OVER (ORDER BY points DESC, updated_at ASC) as new_rank
-			FROM category_rankings
-			WHERE category_id = $1
-		)
-		UPDATE category_rankings cr
-		SET rank = ru.new_rank
-		FROM ranked_users ru
-		WHERE cr.user_id = ru.user_id AND cr.category_id = $1
-	`
+// ApplyRegrade adjusts category and global points for attempts at one category's
+// challenge whose grades changed. Only attempts that had counted towards
+// rankings are adjusted; a voided attempt stops counting entirely.
+func (s *RankingService) ApplyRegrade(
+	ctx context.Context,
+	categoryID uuid.UUID,
+	regrades []models.AttemptRegrade,
+) error {
+	var userIDs []uuid.UUID
+	var points, correct, completed []int
+	var attemptedAt []time.Time
+	for _, regrade := range regrades {
+		if !regrade.Ranked {
+			continue
+		}
 
-	result, err := tx.Exec(ctx, query, categoryID)
-	_ = result // Ignore result, only check error
-	return err
+		correctDelta := 0
+		if regrade.CorrectAfter && !regrade.CorrectBefore {
+			correctDelta = 1
+		} else if regrade.CorrectBefore && !regrade.CorrectAfter {
+			correctDelta = -1
+		}
+		completedDelta := 0
+		if regrade.Voided {
+			completedDelta = -1
+		}
+		pointsDelta := regrade.PointsAfter - regrade.PointsBefore
+		if pointsDelta == 0 && correctDelta == 0 && completedDelta == 0 {
+			continue
+		}
+
+		userIDs = append(userIDs, regrade.UserID)
+		points = append(points, pointsDelta)
+		correct = append(correct, correctDelta)
+		completed = append(completed, completedDelta)
+		attemptedAt = append(attemptedAt, regrade.AttemptedAt)
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := s.db.Conn(ctx)
+
+		if err := s.lock(ctx, batchLockKey); err != nil {
+			return err
+		}
+
+		_, err := conn.Exec(ctx, `
+			UPDATE category_rankings cr
+			SET points = cr.points + r.points,
+			    challenges_correct = cr.challenges_correct + r.correct,
+			    challenges_completed = cr.challenges_completed + r.completed,
+			    updated_at = CURRENT_TIMESTAMP
+			FROM unnest($2::uuid[], $3::int[], $4::int[], $5::int[]) AS r(user_id, points, correct, completed)
+			WHERE cr.category_id = $1 AND cr.user_id = r.user_id
+		`, categoryID, userIDs, points, correct, completed)
+		if err != nil {
+			return fmt.Errorf("failed to update category rankings: %w", err)
+		}
+
+		_, err = conn.Exec(ctx, `
+			UPDATE category_rankings
+			SET mastery_percentage = CASE
+			        WHEN challenges_completed > 0 THEN challenges_correct * 100.0 / challenges_completed
+			        ELSE 0
+			    END
+			WHERE category_id = $1 AND user_id = ANY($2)
+		`, categoryID, userIDs)
+		if err != nil {
+			return fmt.Errorf("failed to update mastery: %w", err)
+		}
+
+		// Regraded points belong to the day the attempt was made
+		if err := s.addDailyPoints(ctx, categoryID, userIDs, attemptedAt, points); err != nil {
+			return err
+		}
+
+		_, err = conn.Exec(ctx, `
+			UPDATE users u
+			SET total_points = u.total_points + r.points,
+			    updated_at = CURRENT_TIMESTAMP
+			FROM unnest($1::uuid[], $2::int[]) AS r(user_id, points)
+			WHERE u.id = r.user_id AND r.points <> 0
+		`, userIDs, points)
+		if err != nil {
+			return fmt.Errorf("failed to update points: %w", err)
+		}
+
+		return nil
+	})
 }
 
-// RecalculateGlobalRanks recalculates global user ranks
-func (s *RankingService) RecalculateGlobalRanks(ctx context.Context) error {
-	query := `
-		WITH ranked_users AS (
-			SELECT 
-				id,
-				ROW_NUMBER() OVER (ORDER BY total_points DESC, created_at ASC) as new_rank
-			FROM users
-			WHERE is_active = true
-		)
-		UPDATE users u
-		SET global_rank = ru.new_rank
-		FROM ranked_users ru
-		WHERE u.id = ru.id
-	`
+// addDailyPoints adds points to each user's total for the UTC day of their
+// attempt in a category
+func (s *RankingService) addDailyPoints(
+	ctx context.Context,
+	categoryID uuid.UUID,
+	userIDs []uuid.UUID,
+	attemptedAt []time.Time,
+	points []int,
+) error {
+	_, err := s.db.Conn(ctx).Exec(ctx, `
+		INSERT INTO user_daily_points (user_id, category_id, day, points)
+		SELECT r.user_id, $1, (r.attempted_at AT TIME ZONE 'UTC')::date, SUM(r.points)
+		FROM unnest($2::uuid[], $3::timestamptz[], $4::int[]) AS r(user_id, attempted_at, points)
+		GROUP BY r.user_id, (r.attempted_at AT TIME ZONE 'UTC')::date
+		ON CONFLICT (user_id, category_id, day)
+		DO UPDATE SET points = user_daily_points.points + EXCLUDED.points
+	`, categoryID, userIDs, attemptedAt, points)
+	if err != nil {
+		return fmt.Errorf("failed to update daily points: %w", err)
+	}
+	return nil
+}
 
-	_, err := s.db.Pool.Exec(ctx, query)
-	return err
+// lock takes a transaction-scoped advisory lock identified by key
+func (s *RankingService) lock(ctx context.Context, key string) error {
+	_, err := s.db.Conn(ctx).Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, key)
+	if err != nil {
+		return fmt.Errorf("failed to acquire ranking lock: %w", err)
+	}
+	return nil
 }
 
-// GetLeaderboard retrieves leaderboard for a category
+// GetLeaderboard retrieves a page of a leaderboard: up to limit entries
+// listed after the cursor. Pass nil for the top of the board.
 func (s *RankingService) GetLeaderboard(
 	ctx context.Context,
-	categoryID *uuid.UUID,
-	scope string,
+	q models.LeaderboardQuery,
+	after *models.LeaderboardCursor,
 	limit int,
 ) (*models.LeaderboardResponse, error) {
-	var query string
-	var args []interface{}
+	entries, err := s.leaderboardPage(ctx, q, after, false, limit)
+	if err != nil {
+		return nil, err
+	}
 
-	// Determine time range based on scope
-	var timeFilter string
-	switch scope {
-	case "daily":
-		timeFilter = "AND cr.last_activity >= CURRENT_DATE"
-	case "weekly":
-		timeFilter = "AND cr.last_activity >= CURRENT_DATE - INTERVAL '7 days'"
-	case "monthly":
-		timeFilter = "AND cr.last_activity >= CURRENT_DATE - INTERVAL '30 days'"
-	case "all_time":
-		timeFilter = ""
-	default:
-		timeFilter = ""
+	return s.leaderboardResponse(ctx, q, entries, len(entries) == limit)
+}
+
+// GetLeaderboardAround retrieves the part of a leaderboard around entry:
+// up to limit entries either side of it, and the entry itself
+func (s *RankingService) GetLeaderboardAround(
+	ctx context.Context,
+	q models.LeaderboardQuery,
+	entry *models.LeaderboardEntry,
+	limit int,
+) (*models.LeaderboardResponse, error) {
+	cursor := entry.Cursor()
+	before, err := s.leaderboardPage(ctx, q, &cursor, true, limit)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.leaderboardPage(ctx, q, &cursor, false, limit)
+	if err != nil {
+		return nil, err
 	}
 
-	if categoryID != nil {
-		// Category-specific leaderboard
-		query = fmt.Sprintf(`
-			SELECT 
-				cr.rank,
-				u.id,
-				u.username,
-				u.display_name,
-				u.avatar_url,
-				cr.points,
-				cr.mastery_percentage
-			FROM category_rankings cr
-			JOIN users u ON cr.user_id = u.id
-			WHERE cr.category_id = $1
-			  AND u.is_active = true
-			  %s
-			ORDER BY cr.rank ASC
-			LIMIT $2
-		`, timeFilter)
-		args = []interface{}{categoryID, limit}
-	} else {
-		// Global leaderboard
-		query = `
-			SELECT 
-				u.global_rank as rank,
-				u.id,
-				u.username,
-				u.display_name,
-				u.avatar_url,
-				u.total_points as points,
-				NULL as mastery_percentage
-			FROM users u
-			WHERE u.is_active = true
-			  AND u.global_rank IS NOT NULL
-			ORDER BY u.global_rank ASC
-			LIMIT $1
-		`
-		args = []interface{}{limit}
+	entries := make([]models.LeaderboardEntry, 0, len(before)+1+len(after))
+	for i := len(before) - 1; i >= 0; i-- {
+		entries = append(entries, before[i])
 	}
+	entries = append(entries, *entry)
+	entries = append(entries, after...)
+
+	return s.leaderboardResponse(ctx, q, entries, len(after) == limit)
+}
 
-	rows, err := s.db.Pool.Query(ctx, query, args...)
+// leaderboardPage retrieves up to limit entries listed after the cursor,
+// or nearest first before it when backwards is set
+func (s *RankingService) leaderboardPage(
+	ctx context.Context,
+	q models.LeaderboardQuery,
+	cursor *models.LeaderboardCursor,
+	backwards bool,
+	limit int,
+) ([]models.LeaderboardEntry, error) {
+	board, args := leaderboardQuery(q, time.Now())
+	order, where, args := leaderboardKeyset(q, args, cursor, backwards)
+
+	query := fmt.Sprintf(`
+		SELECT 
+			b.rank,
+			u.id,
+			u.username,
+			u.display_name,
+			u.avatar_url,
+			b.points,
+			b.mastery_percentage,
+			b.rating,
+			b.tied_at
+		FROM (%s) b
+		JOIN users u ON b.user_id = u.id
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, board, where, order, len(args)+1)
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query, append(args, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query leaderboard: %w", err)
 	}
@@ -216,108 +396,355 @@ func (s *RankingService) GetLeaderboard(
 	var entries []models.LeaderboardEntry
 	for rows.Next() {
 		var entry models.LeaderboardEntry
-		err := rows.Scan(
-			&entry.Rank,
-			&entry.UserID,
-			&entry.Username,
-			&entry.DisplayName,
-			&entry.AvatarURL,
-			&entry.Points,
-			&entry.MasteryPercentage,
-		)
-		if err != nil {
+		if err := scanLeaderboardEntry(rows, &entry); err != nil {
 			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
 		}
 		entries = append(entries, entry)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard: %w", err)
+	}
+
+	return entries, nil
+}
+
+// leaderboardResponse wraps entries with the board's metadata. more means
+// the board may go on past the last entry.
+func (s *RankingService) leaderboardResponse(
+	ctx context.Context,
+	q models.LeaderboardQuery,
+	entries []models.LeaderboardEntry,
+	more bool,
+) (*models.LeaderboardResponse, error) {
+	board, args := leaderboardQuery(q, time.Now())
 
 	// Get total users count
 	var totalUsers int
-	if categoryID != nil {
-		err = s.db.Pool.QueryRow(ctx, `
-			SELECT COUNT(*) FROM category_rankings WHERE category_id = $1
-		`, categoryID).Scan(&totalUsers)
-	} else {
-		err = s.db.Pool.QueryRow(ctx, `
-			SELECT COUNT(*) FROM users WHERE is_active = true
-		`).Scan(&totalUsers)
-	}
+	err := s.db.Conn(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM (`+board+`) b`, args...).Scan(&totalUsers)
 	if err != nil {
 		totalUsers = 0
 	}
 
-	return &models.LeaderboardResponse{
-		Scope:      scope,
-		CategoryID: categoryID,
+	response := &models.LeaderboardResponse{
+		Mode:       q.Mode,
+		Scope:      q.Scope,
+		CategoryID: q.CategoryID,
 		Entries:    entries,
 		TotalUsers: totalUsers,
-	}, nil
+	}
+	if more && len(entries) > 0 {
+		next := entries[len(entries)-1].Cursor().String()
+		response.NextCursor = &next
+	}
+
+	return response, nil
 }
 
-// GetUserRankInCategory gets user's current rank in a category
-func (s *RankingService) GetUserRankInCategory(
+// leaderboardKeyset returns the order a leaderboard lists its entries in,
+// and a condition matching the entries listed after the cursor, or before
+// it when backwards is set, in which case the order is reversed. The
+// condition's arguments are appended to args.
+func leaderboardKeyset(
+	q models.LeaderboardQuery,
+	args []interface{},
+	cursor *models.LeaderboardCursor,
+	backwards bool,
+) (order, where string, _ []interface{}) {
+	desc, asc, past, ahead := "DESC", "ASC", "<", ">"
+	if backwards {
+		desc, asc, past, ahead = asc, desc, ahead, past
+	}
+
+	n := len(args)
+	if q.Mode == "rating" {
+		order = fmt.Sprintf(`b.rating %s, b.user_id %s`, desc, asc)
+		if cursor == nil {
+			return order, "true", args
+		}
+		where = fmt.Sprintf(`(b.rating %[1]s $%[3]d OR (b.rating = $%[3]d AND b.user_id %[2]s $%[4]d))`,
+			past, ahead, n+1, n+2)
+		return order, where, append(args, cursor.Rating, cursor.UserID)
+	}
+
+	order = fmt.Sprintf(`b.points %s, b.tied_at %s, b.user_id %s`, desc, asc, asc)
+	if cursor == nil {
+		return order, "true", args
+	}
+	where = fmt.Sprintf(`(b.points %[1]s $%[3]d OR (b.points = $%[3]d AND (b.tied_at, b.user_id) %[2]s ($%[4]d, $%[5]d)))`,
+		past, ahead, n+1, n+2, n+3)
+	return order, where, append(args, cursor.Points, cursor.TiedAt, cursor.UserID)
+}
+
+// GetLeaderboardEntry retrieves a user's entry on a leaderboard, or nil
+// if they are not on it
+func (s *RankingService) GetLeaderboardEntry(
 	ctx context.Context,
+	q models.LeaderboardQuery,
 	userID uuid.UUID,
-	categoryID uuid.UUID,
-) (*int, error) {
-	query := `
-		SELECT rank FROM category_rankings
-		WHERE user_id = $1 AND category_id = $2
-	`
+) (*models.LeaderboardEntry, error) {
+	board, args := leaderboardQuery(q, time.Now())
 
-	var rank *int
-	err := s.db.Pool.QueryRow(ctx, query, userID, categoryID).Scan(&rank)
+	query := fmt.Sprintf(`
+		SELECT 
+			b.rank,
+			u.id,
+			u.username,
+			u.display_name,
+			u.avatar_url,
+			b.points,
+			b.mastery_percentage,
+			b.rating,
+			b.tied_at
+		FROM (%s) b
+		JOIN users u ON b.user_id = u.id
+		WHERE b.user_id = $%d
+	`, board, len(args)+1)
+
+	var entry models.LeaderboardEntry
+	err := scanLeaderboardEntry(s.db.Conn(ctx).QueryRow(ctx, query, append(args, userID)...), &entry)
 	if err != nil {
-		return nil, err
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get leaderboard entry: %w", err)
 	}
 
-	return rank, nil
+	return &entry, nil
 }
 
-// CreateLeaderboardSnapshot creates a snapshot of current leaderboard state
-func (s *RankingService) CreateLeaderboardSnapshot(ctx context.Context, snapshotType string) error {
-	now := time.Now()
-	snapshotDate := now.Truncate(24 * time.Hour)
+// leaderboardQuery returns a query ranking the users on a leaderboard,
+// with the columns rank, user_id, points, mastery_percentage, rating and
+// tied_at, and its arguments. Callers number their own arguments after
+// these.
+//
+// All-time points boards rank by total points. Daily, weekly and monthly
+// boards rank by the points earned from attempts made in the window. Ties
+// go to the longest-standing user. tied_at, when the user joined, is what points ties are broken
+// on. Rating boards rank by skill rating and leave off users whose rating
+// is still provisional.
+func leaderboardQuery(q models.LeaderboardQuery, now time.Time) (string, []interface{}) {
+	if q.Mode == "rating" {
+		if q.CategoryID != nil {
+			return `
+				SELECT 
+					ROW_NUMBER() OVER (ORDER BY us.rating DESC, us.user_id) as rank,
+					us.user_id,
+					COALESCE(cr.points, 0) as points,
+					cr.mastery_percentage,
+					us.rating,
+					u.created_at as tied_at
+				FROM user_skills us
+				JOIN users u ON us.user_id = u.id
+				LEFT JOIN category_rankings cr
+				  ON cr.user_id = us.user_id AND cr.category_id = us.category_id
+				WHERE us.category_id = $1
+				  AND us.attempts >= $2
+				  AND u.is_active = true
+			`, []interface{}{q.CategoryID, models.SkillProvisionalAttempts}
+		}
+		return `
+			SELECT 
+				ROW_NUMBER() OVER (ORDER BY u.rating DESC, u.id) as rank,
+				u.id as user_id,
+				u.total_points as points,
+				NULL::float8 as mastery_percentage,
+				u.rating,
+				u.created_at as tied_at
+			FROM users u
+			WHERE u.is_active = true
+			  AND u.rated_attempts >= $1
+		`, []interface{}{models.SkillProvisionalAttempts}
+	}
 
-	// Category snapshots
-	query := `
-		INSERT INTO leaderboard_snapshots (
-			user_id, category_id, points, rank, snapshot_type, snapshot_date
-		)
+	if since, ok := windowStart(q.Scope, now); ok {
+		if q.CategoryID != nil {
+			return `
+				SELECT 
+					ROW_NUMBER() OVER (ORDER BY SUM(dp.points) DESC, u.created_at ASC, u.id) as rank,
+					u.id as user_id,
+					SUM(dp.points) as points,
+					MAX(cr.mastery_percentage) as mastery_percentage,
+					NULL::float8 as rating,
+					u.created_at as tied_at
+				FROM user_daily_points dp
+				JOIN users u ON dp.user_id = u.id
+				LEFT JOIN category_rankings cr
+				  ON cr.user_id = dp.user_id AND cr.category_id = dp.category_id
+				WHERE dp.day >= $1
+				  AND dp.category_id = $2
+				  AND u.is_active = true
+				GROUP BY u.id
+			`, []interface{}{since, q.CategoryID}
+		}
+		return `
+			SELECT 
+				ROW_NUMBER() OVER (ORDER BY SUM(dp.points) DESC, u.created_at ASC, u.id) as rank,
+				u.id as user_id,
+				SUM(dp.points) as points,
+				NULL::float8 as mastery_percentage,
+				NULL::float8 as rating,
+				u.created_at as tied_at
+			FROM user_daily_points dp
+			JOIN users u ON dp.user_id = u.id
+			WHERE dp.day >= $1
+			  AND u.is_active = true
+			GROUP BY u.id
+		`, []interface{}{since}
+	}
+
+	if q.CategoryID != nil {
+		return `
+			SELECT 
+				ROW_NUMBER() OVER (ORDER BY cr.points DESC, u.created_at ASC, u.id) as rank,
+				cr.user_id,
+				cr.points,
+				cr.mastery_percentage,
+				NULL::float8 as rating,
+				u.created_at as tied_at
+			FROM category_rankings cr
+			JOIN users u ON cr.user_id = u.id
+			WHERE cr.category_id = $1
+			  AND u.is_active = true
+		`, []interface{}{q.CategoryID}
+	}
+	return `
 		SELECT 
-			user_id, category_id, points, rank, $1, $2
-		FROM category_rankings
-		WHERE last_activity >= $3
-	`
+			ROW_NUMBER() OVER (ORDER BY u.total_points DESC, u.created_at ASC, u.id) as rank,
+			u.id as user_id,
+			u.total_points as points,
+			NULL::float8 as mastery_percentage,
+			NULL::float8 as rating,
+			u.created_at as tied_at
+		FROM users u
+		WHERE u.is_active = true
+	`, nil
+}
 
-	var since time.Time
-	switch snapshotType {
+// windowStart returns the first UTC day counted by a daily, weekly or
+// monthly leaderboard, which all end today. ok is false for all_time.
+func windowStart(scope string, now time.Time) (since time.Time, ok bool) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	switch scope {
 	case "daily":
-		since = now.AddDate(0, 0, -1)
+		return today, true
 	case "weekly":
-		since = now.AddDate(0, 0, -7)
+		return today.AddDate(0, 0, -6), true
 	case "monthly":
-		since = now.AddDate(0, -1, 0)
+		return today.AddDate(0, 0, -29), true
 	default:
-		since = time.Time{} // All time
+		return time.Time{}, false
 	}
+}
+
+func scanLeaderboardEntry(row pgx.Row, entry *models.LeaderboardEntry) error {
+	return row.Scan(
+		&entry.Rank,
+		&entry.UserID,
+		&entry.Username,
+		&entry.DisplayName,
+		&entry.AvatarURL,
+		&entry.Points,
+		&entry.MasteryPercentage,
+		&entry.Rating,
+		&entry.TiedAt,
+	)
+}
+
+// GetUserRankInCategory gets user's current rank in a category, or nil if
+// they have not played it
+func (s *RankingService) GetUserRankInCategory(
+	ctx context.Context,
+	userID uuid.UUID,
+	categoryID uuid.UUID,
+) (*int, error) {
+	stats, err := s.categoryRepo.GetUserStats(ctx, categoryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats.Rank, nil
+}
 
-	_, err := s.db.Pool.Exec(ctx, query, snapshotType, snapshotDate, since)
+// GetUserGlobalRank gets user's current global rank
+func (s *RankingService) GetUserGlobalRank(ctx context.Context, userID uuid.UUID) (*int, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to create snapshot: %w", err)
+		return nil, err
+	}
+
+	return user.GlobalRank, nil
+}
+
+// CreateLeaderboardSnapshot creates a snapshot of current leaderboard
+// state. Daily, weekly and monthly snapshots record the points and ranks
+// of that window's boards, as GetLeaderboard serves them.
+func (s *RankingService) CreateLeaderboardSnapshot(ctx context.Context, snapshotType string) error {
+	now := time.Now()
+	snapshotDate := now.Truncate(24 * time.Hour)
+
+	since, windowed := windowStart(snapshotType, now)
+	if !windowed {
+		// Category snapshots, every category's board at once
+		query := `
+			INSERT INTO leaderboard_snapshots (
+				user_id, category_id, points, rank, snapshot_type, snapshot_date
+			)
+			SELECT 
+				cr.user_id,
+				cr.category_id,
+				cr.points,
+				ROW_NUMBER() OVER (
+					PARTITION BY cr.category_id
+					ORDER BY cr.points DESC, u.created_at ASC, u.id
+				),
+				$1, $2
+			FROM category_rankings cr
+			JOIN users u ON cr.user_id = u.id
+			WHERE u.is_active = true
+		`
+
+		_, err := s.db.Conn(ctx).Exec(ctx, query, snapshotType, snapshotDate)
+		if err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
+	} else {
+		// Category snapshots, every category's window board at once
+		query := `
+			INSERT INTO leaderboard_snapshots (
+				user_id, category_id, points, rank, snapshot_type, snapshot_date
+			)
+			SELECT 
+				u.id,
+				dp.category_id,
+				SUM(dp.points),
+				ROW_NUMBER() OVER (
+					PARTITION BY dp.category_id
+					ORDER BY SUM(dp.points) DESC, u.created_at ASC, u.id
+				),
+				$2, $3
+			FROM user_daily_points dp
+			JOIN users u ON dp.user_id = u.id
+			WHERE dp.day >= $1
+			  AND u.is_active = true
+			GROUP BY dp.category_id, u.id
+		`
+
+		_, err := s.db.Conn(ctx).Exec(ctx, query, since, snapshotType, snapshotDate)
+		if err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
 	}
 
 	// Global snapshots
+	board, args := leaderboardQuery(models.LeaderboardQuery{Mode: "points", Scope: snapshotType}, now)
 	globalQuery := `
 		INSERT INTO leaderboard_snapshots (
 			user_id, category_id, points, rank, snapshot_type, snapshot_date
 		)
-		SELECT 
-			id, NULL, total_points, global_rank, $1, $2
-		FROM users
-		WHERE is_active = true AND global_rank IS NOT NULL
+		SELECT user_id, NULL, points, rank, $2, $3
+		FROM (` + board + `) b
 	`
 
-	_, err = s.db.Pool.Exec(ctx, globalQuery, snapshotType, snapshotDate)
+	_, err := s.db.Conn(ctx).Exec(ctx, globalQuery, append(args, snapshotType, snapshotDate)...)
 	return err
 }
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowStart(t *testing.T) {
+	now := time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		scope  string
+		now    time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "daily", scope: "daily", now: now, want: day(3, 10), wantOK: true},
+		{name: "weekly spans seven days", scope: "weekly", now: now, want: day(3, 4), wantOK: true},
+		{name: "monthly spans thirty days", scope: "monthly", now: now, want: day(2, 9), wantOK: true},
+		{name: "days are UTC", scope: "daily", now: time.Date(2026, 3, 11, 1, 0, 0, 0, time.FixedZone("UTC+3", 3*3600)), want: day(3, 10), wantOK: true},
+		{name: "all time has no window", scope: "all_time", now: now},
+		{name: "unknown scope has no window", scope: "yearly", now: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := windowStart(tt.scope, tt.now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("windowStart = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/fanmania/backend/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+// ReportService handles players' reports against challenges. A challenge
+// that collects threshold open reports is suspended until an admin
+// resolves them.
+type ReportService struct {
+	txRunner         postgres.TxRunner
+	reportRepo       *postgres.ReportRepository
+	challengeRepo    *postgres.ChallengeRepository
+	challengeService *ChallengeService
+	threshold        int
+}
+
+// NewReportService creates a new ReportService. A threshold below 1
+// disables automatic suspension.
+func NewReportService(
+	txRunner postgres.TxRunner,
+	reportRepo *postgres.ReportRepository,
+	challengeRepo *postgres.ChallengeRepository,
+	challengeService *ChallengeService,
+	threshold int,
+) *ReportService {
+	return &ReportService{
+		txRunner:         txRunner,
+		reportRepo:       reportRepo,
+		challengeRepo:    challengeRepo,
+		challengeService: challengeService,
+		threshold:        threshold,
+	}
+}
+
+// Report records a player's report against a challenge, suspending the
+// challenge if this report reaches the threshold
+func (s *ReportService) Report(
+	ctx context.Context,
+	userID uuid.UUID,
+	challengeID uuid.UUID,
+	req *models.ReportChallengeRequest,
+) (*models.ChallengeReport, error) {
+	report := &models.ChallengeReport{
+		ChallengeID: challengeID,
+		UserID:      userID,
+		Reason:      req.Reason,
+		Details:     req.Details,
+	}
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		// Lock the challenge so concurrent reports count each other
+		challenge, err := s.challengeRepo.GetForUpdate(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.reportRepo.Create(ctx, report); err != nil {
+			return err
+		}
+
+		if s.threshold < 1 || !challenge.IsActive {
+			return nil
+		}
+		open, err := s.reportRepo.CountOpen(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+		if open < s.threshold {
+			return nil
+		}
+
+		if err := s.challengeRepo.Suspend(ctx, challengeID); err != nil {
+			return err
+		}
+		log.Printf("⚠ Challenge %s suspended after %d reports", challengeID, open)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ListSummaries aggregates reports with the given status per challenge
+func (s *ReportService) ListSummaries(ctx context.Context, status string, limit, offset int) ([]models.ReportSummary, error) {
+	return s.reportRepo.ListSummaries(ctx, status, limit, offset)
+}
+
+// ListReports retrieves a challenge's reports, optionally filtered by status
+func (s *ReportService) ListReports(ctx context.Context, challengeID uuid.UUID, status string) ([]models.ChallengeReport, error) {
+	if _, err := s.challengeRepo.GetAnyByID(ctx, challengeID); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.ListForChallenge(ctx, challengeID, status)
+}
+
+// Resolve closes a challenge's open reports. Dismissing them puts a
+// suspended challenge back into play; upholding them either corrects the
+// answer and regrades attempts, or voids the challenge.
+func (s *ReportService) Resolve(
+	ctx context.Context,
+	editorID uuid.UUID,
+	challengeID uuid.UUID,
+	req *models.ResolveReportsRequest,
+) (*models.ReportResolution, error) {
+	resolution := &models.ReportResolution{
+		ChallengeID: challengeID,
+		Outcome:     req.Outcome,
+	}
+
+	var notes *string
+	if trimmed := strings.TrimSpace(req.Notes); trimmed != "" {
+		notes = &trimmed
+	}
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		challenge, err := s.challengeRepo.GetForUpdate(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+
+		open, err := s.reportRepo.CountOpen(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+		if open == 0 {
+			return errors.ErrNoOpenReports
+		}
+
+		status := models.ReportUpheld
+		switch req.Outcome {
+		case models.ReportOutcomeDismissed:
+			status = models.ReportDismissed
+			if challenge.SuspendedAt != nil {
+				if _, err := s.challengeService.SetChallengeActive(ctx, editorID, challengeID, true); err != nil {
+					return err
+				}
+			}
+		case models.ReportOutcomeCorrected:
+			if strings.TrimSpace(req.CorrectAnswer) == "" {
+				return errors.ErrInvalidAnswer
+			}
+			if resolution.Regrade, err = s.challengeService.RegradeChallenge(ctx, editorID, challengeID, req.CorrectAnswer); err != nil {
+				return err
+			}
+		case models.ReportOutcomeVoided:
+			if resolution.Regrade, err = s.challengeService.VoidChallenge(ctx, editorID, challengeID); err != nil {
+				return err
+			}
+		default:
+			return errors.ErrInvalidInput
+		}
+
+		resolution.ReportsResolved, err = s.reportRepo.ResolveOpen(ctx, challengeID, status, editorID, notes)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolution, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/fanmania/backend/internal/ai"
+	"github.com/fanmania/backend/internal/domain/errors"
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/fanmania/backend/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+// ReviewService handles the moderation queue for AI challenges the legal
+// validator flagged for review. Flagged challenges are saved but not
+// served until a moderator approves them.
+type ReviewService struct {
+	txRunner         postgres.TxRunner
+	challengeRepo    *postgres.ChallengeRepository
+	challengeService *ChallengeService
+}
+
+// NewReviewService creates a new ReviewService
+func NewReviewService(
+	txRunner postgres.TxRunner,
+	challengeRepo *postgres.ChallengeRepository,
+	challengeService *ChallengeService,
+) *ReviewService {
+	return &ReviewService{
+		txRunner:         txRunner,
+		challengeRepo:    challengeRepo,
+		challengeService: challengeService,
+	}
+}
+
+// ListPending returns challenges awaiting review, oldest first
+func (s *ReviewService) ListPending(ctx context.Context, limit, offset int) ([]models.Challenge, error) {
+	return s.challengeRepo.ListPendingReview(ctx, limit, offset)
+}
+
+// GetBacklog summarizes the review queue
+func (s *ReviewService) GetBacklog(ctx context.Context) (*models.ReviewBacklog, error) {
+	return s.challengeRepo.GetReviewBacklog(ctx)
+}
+
+// Approve publishes a challenge awaiting review
+func (s *ReviewService) Approve(ctx context.Context, reviewerID, challengeID uuid.UUID, reason string) (*models.Challenge, error) {
+	return s.decide(ctx, reviewerID, challengeID, models.ReviewApproved, reason)
+}
+
+// Reject keeps a challenge awaiting review from ever being served. A
+// reason is required so the decision can be audited.
+func (s *ReviewService) Reject(ctx context.Context, reviewerID, challengeID uuid.UUID, reason string) (*models.Challenge, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.ErrReasonRequired
+	}
+	return s.decide(ctx, reviewerID, challengeID, models.ReviewRejected, reason)
+}
+
+// Edit corrects a challenge awaiting review. The edit is saved as a new
+// revision and the challenge stays in the queue until it is approved.
+func (s *ReviewService) Edit(
+	ctx context.Context,
+	reviewerID uuid.UUID,
+	challengeID uuid.UUID,
+	input *models.ChallengeInput,
+) (*models.Challenge, *ai.ValidationResult, error) {
+	var challenge *models.Challenge
+	var validation *ai.ValidationResult
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		current, err := s.challengeRepo.GetForUpdate(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+		if current.ReviewStatus != models.ReviewPending {
+			return errors.ErrNotPendingReview
+		}
+
+		challenge, validation, err = s.challengeService.EditChallenge(ctx, reviewerID, challengeID, input)
+		return err
+	})
+	if err != nil {
+		return nil, validation, err
+	}
+
+	return challenge, validation, nil
+}
+
+func (s *ReviewService) decide(
+	ctx context.Context,
+	reviewerID uuid.UUID,
+	challengeID uuid.UUID,
+	status string,
+	reason string,
+) (*models.Challenge, error) {
+	challenge, err := s.challengeRepo.GetAnyByID(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reasonPtr *string
+	if reason = strings.TrimSpace(reason); reason != "" {
+		reasonPtr = &reason
+	}
+
+	if err := s.challengeRepo.SetReviewStatus(ctx, challenge, status, reviewerID, reasonPtr); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Challenge %s %s by %s", challenge.ID, status, reviewerID)
+	return challenge, nil
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/fanmania/backend/internal/domain/models"
+	"github.com/fanmania/backend/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+const (
+	// skillTargetMargin is how far below a user's rating challenges are
+	// served, so they answer about two in three correctly
+	skillTargetMargin = 100.0
+
+	// skillMinDeviation keeps settled ratings responsive to a change in form
+	skillMinDeviation = 50.0
+
+	// skillDeviationDrift is how much a rating's deviation grows per idle
+	// day; a settled rating is wholly uncertain again after about 100 days
+	skillDeviationDrift = 35.0
+
+	// Uncertainty in a challenge's rating when it is a user's opponent
+	challengeDeviation            = 60.0
+	provisionalChallengeDeviation = 150.0
+
+	// Elo K-factors for challenge ratings
+	challengeK            = 16.0
+	provisionalChallengeK = 32.0
+)
+
+// glickoQ converts the rating scale to natural-log odds
+var glickoQ = math.Ln10 / 400
+
+// SkillService keeps Glicko style skill ratings for users, in each
+// category and overall, and Elo ratings for challenges. Every rated
+// attempt is a game between the user and the challenge. It also picks the
+// difficulty of the challenges users are served.
+type SkillService struct {
+	txRunner            postgres.TxRunner
+	skillRepo           *postgres.SkillRepository
+	challengeRepo       *postgres.ChallengeRepository
+	notificationService *NotificationService
+}
+
+// NewSkillService creates a new SkillService
+func NewSkillService(
+	txRunner postgres.TxRunner,
+	skillRepo *postgres.SkillRepository,
+	challengeRepo *postgres.ChallengeRepository,
+	notificationService *NotificationService,
+) *SkillService {
+	return &SkillService{
+		txRunner:            txRunner,
+		skillRepo:           skillRepo,
+		challengeRepo:       challengeRepo,
+		notificationService: notificationService,
+	}
+}
+
+// TargetDifficulty returns the difficulty, on the 1-5 tier scale, of the
+// challenges to serve a user in a category
+func (s *SkillService) TargetDifficulty(ctx context.Context, userID, categoryID uuid.UUID) (float64, error) {
+	skill, err := s.skillRepo.Get(ctx, userID, categoryID)
+	if err != nil {
+		return 0, err
+	}
+	if skill == nil {
+		skill = models.NewUserSkill(userID, categoryID)
+	}
+	return targetDifficulty(skill), nil
+}
+
+// LockForAttempt holds off a recalculation of ratings until the attempt's
+// transaction ends. Attempts do not wait for each other. It must be called
+// before the attempt locks any row.
+func (s *SkillService) LockForAttempt(ctx context.Context) error {
+	return s.skillRepo.LockForRating(ctx)
+}
+
+// RecordAttempt rates an attempt at a challenge, updating the user's skill
+// in its category, their global rating and the challenge's rating.
+// Answering a challenge above the user's highest unlocked tier correctly
+// unlocks its tier, once their rating has reached it too. It joins the
+// attempt's transaction.
+func (s *SkillService) RecordAttempt(
+	ctx context.Context,
+	userID uuid.UUID,
+	challenge *models.Challenge,
+	score float64,
+) (*models.SkillUpdate, error) {
+	skill, err := s.skillRepo.GetForUpdate(ctx, userID, challenge.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	global, err := s.skillRepo.GetGlobalForUpdate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := skill.Rating
+	opponent := models.SkillRating{Rating: challenge.Rating, Attempts: challenge.RatingAttempts}
+	outcome := rateAttempt(skill, global, &opponent, challenge.DifficultyTier, score, time.Now())
+
+	if err := s.skillRepo.Save(ctx, skill); err != nil {
+		return nil, err
+	}
+	if err := s.skillRepo.SaveGlobal(ctx, userID, global); err != nil {
+		return nil, err
+	}
+	if outcome.challengeRated {
+		// The attempt holds the challenge row FOR UPDATE, so the rating the
+		// delta was computed from is still the stored one
+		if err := s.challengeRepo.AdjustRating(ctx, challenge.ID, outcome.challengeDelta); err != nil {
+			return nil, err
+		}
+	}
+
+	update := &models.SkillUpdate{
+		CategoryID:   challenge.CategoryID,
+		Rating:       skill.Rating,
+		RatingChange: skill.Rating - before,
+		GlobalRating: global.Rating,
+		Provisional:  skill.Provisional(),
+		Tier:         models.NearestTier(targetDifficulty(skill)),
+	}
+	if outcome.unlocked {
+		unlocked := skill.UnlockedTier
+		update.UnlockedTier = &unlocked
+	}
+
+	return update, nil
+}
+
+// RecalculateRatings replays every rated attempt, oldest first, to
+// rebuild all user and challenge ratings, for example after the rating
+// rules change. Each challenge starts from the tier it had when first
+// attempted, not the tier it may since have been moved to. Unlocked tiers
+// are kept. Submissions wait while it runs.
+func (s *SkillService) RecalculateRatings(ctx context.Context) (*models.RatingRecalculation, error) {
+	result := &models.RatingRecalculation{}
+
+	err := s.txRunner.WithTx(ctx, func(ctx context.Context) error {
+		*result = models.RatingRecalculation{}
+
+		if err := s.skillRepo.LockForRecalculation(ctx); err != nil {
+			return err
+		}
+
+		type skillKey struct{ userID, categoryID uuid.UUID }
+		skills := map[skillKey]*models.UserSkill{}
+		globals := map[uuid.UUID]*models.SkillRating{}
+		challenges := map[uuid.UUID]*models.SkillRating{}
+
+		err := s.skillRepo.ForEachRatedAttempt(ctx, func(attempt models.RatedAttempt) error {
+			key := skillKey{attempt.UserID, attempt.CategoryID}
+			skill, ok := skills[key]
+			if !ok {
+				skill = models.NewUserSkill(attempt.UserID, attempt.CategoryID)
+				skills[key] = skill
+			}
+			global, ok := globals[attempt.UserID]
+			if !ok {
+				rating := models.NewSkillRating()
+				global = &rating
+				globals[attempt.UserID] = global
+			}
+			opponent, ok := challenges[attempt.ChallengeID]
+			if !ok {
+				opponent = &models.SkillRating{
+					Rating: models.DifficultyRating(float64(attempt.DifficultyTier)),
+				}
+				challenges[attempt.ChallengeID] = opponent
+			}
+
+			outcome := rateAttempt(skill, global, opponent, attempt.DifficultyTier, attempt.Score, attempt.AttemptedAt)
+			if outcome.challengeRated {
+				opponent.Rating += outcome.challengeDelta
+				opponent.Attempts++
+			}
+
+			result.Attempts++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Written in the order an attempt writes them (the challenge, then
+		// the user, then their skill), so the row locks taken here cannot
+		// deadlock with work that locks a challenge and then its players
+		challengeRatings := make(map[uuid.UUID]models.SkillRating, len(challenges))
+		for challengeID, rating := range challenges {
+			if rating.Attempts > 0 {
+				challengeRatings[challengeID] = *rating
+			}
+		}
+		if err := s.challengeRepo.ReplaceRatings(ctx, challengeRatings); err != nil {
+			return err
+		}
+
+		globalRatings := make(map[uuid.UUID]models.SkillRating, len(globals))
+		for userID, rating := range globals {
+			globalRatings[userID] = *rating
+		}
+		if err := s.skillRepo.ReplaceGlobalRatings(ctx, globalRatings); err != nil {
+			return err
+		}
+
+		replayed := make([]models.UserSkill, 0, len(skills))
+		for _, skill := range skills {
+			replayed = append(replayed, *skill)
+		}
+		if err := s.skillRepo.ReplaceSkills(ctx, replayed); err != nil {
+			return err
+		}
+
+		result.Users = len(globals)
+		result.Challenges = len(challengeRatings)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// NotifyTierUnlocked tells a user they have unlocked a tier, with the share
+// of the category's players they are ahead of
+func (s *SkillService) NotifyTierUnlocked(ctx context.Context, userID, categoryID uuid.UUID, tier int) error {
+	reached, total, err := s.skillRepo.CountTierReached(ctx, categoryID, tier)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+
+	percentile := 100 * float64(total-reached) / float64(total)
+	return s.notificationService.SendDifficultyProgressNotification(ctx, userID, percentile)
+}
+
+// attemptOutcome is what rating an attempt did beyond the user's ratings
+type attemptOutcome struct {
+	challengeRated bool    // the challenge's rating moved
+	challengeDelta float64 // by this much
+	unlocked       bool    // the attempt unlocked the challenge's tier
+}
+
+// rateAttempt rates an attempt scoring score, made at the given time, at a
+// challenge of the given tier rated opponent. The user's category skill and
+// global rating are updated in place; the change to the challenge's rating
+// is returned for the caller to apply. Live attempts and recalculation
+// share it, so both rate the same way.
+func rateAttempt(
+	skill *models.UserSkill,
+	global *models.SkillRating,
+	opponent *models.SkillRating,
+	tier int,
+	score float64,
+	at time.Time,
+) attemptOutcome {
+	var outcome attemptOutcome
+
+	deviation := challengeDeviation
+	k := challengeK
+	if opponent.Attempts < models.ChallengeProvisionalAttempts {
+		deviation = provisionalChallengeDeviation
+		k = provisionalChallengeK
+	}
+
+	// Challenges only learn from users whose rating can be trusted
+	if !skill.Provisional() {
+		expected := 1 / (1 + math.Pow(10, (skill.Rating-opponent.Rating)/400))
+		outcome.challengeDelta = k * ((1 - score) - expected)
+		outcome.challengeRated = true
+	}
+
+	updateRating(&skill.SkillRating, opponent.Rating, deviation, score, at)
+	updateRating(global, opponent.Rating, deviation, score, at)
+
+	if score >= 1 && tier > skill.UnlockedTier &&
+		models.NearestTier(models.RatingDifficulty(skill.Rating)) >= tier {
+		skill.UnlockedTier = tier
+		outcome.unlocked = true
+	}
+
+	return outcome
+}
+
+// updateRating applies one game, of score 0 to 1, against an opponent to
+// a rating
+func updateRating(rating *models.SkillRating, opponentRating, opponentDeviation, score float64, at time.Time) {
+	value, deviation := glickoUpdate(
+		rating.Rating, idleDeviation(rating, at), opponentRating, opponentDeviation, score,
+	)
+	rating.Rating = value
+	rating.Deviation = math.Max(skillMinDeviation, deviation)
+	rating.Attempts++
+	rating.UpdatedAt = at
+}
+
+// targetDifficulty is a little below the user's rating, so most answers
+// are right, and at most one tier above the highest they have unlocked
+func targetDifficulty(skill *models.UserSkill) float64 {
+	target := models.RatingDifficulty(skill.Rating - skillTargetMargin)
+	return math.Min(target, float64(skill.UnlockedTier+1))
+}
+
+// idleDeviation is a rating's deviation grown by the time between its last
+// update and at
+func idleDeviation(rating *models.SkillRating, at time.Time) float64 {
+	if rating.Attempts == 0 {
+		return rating.Deviation
+	}
+	days := math.Max(0, at.Sub(rating.UpdatedAt).Hours()/24)
+	deviation := math.Sqrt(rating.Deviation*rating.Deviation + skillDeviationDrift*skillDeviationDrift*days)
+	return math.Min(models.SkillInitialDeviation, deviation)
+}
+
+// glickoUpdate applies one Glicko rating period holding a single game, of
+// score 0 to 1, against an opponent rated opponentRating with deviation
+// opponentDeviation. It returns the new rating and deviation.
+func glickoUpdate(rating, deviation, opponentRating, opponentDeviation, score float64) (float64, float64) {
+	g := 1 / math.Sqrt(1+3*glickoQ*glickoQ*opponentDeviation*opponentDeviation/(math.Pi*math.Pi))
+	expected := 1 / (1 + math.Pow(10, -g*(rating-opponentRating)/400))
+	dSquaredInv := glickoQ * glickoQ * g * g * expected * (1 - expected)
+
+	precision := 1/(deviation*deviation) + dSquaredInv
+	rating += glickoQ / precision * g * (score - expected)
+	return rating, math.Sqrt(1 / precision)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fanmania/backend/internal/domain/models"
+)
+
+func TestGlickoUpdate(t *testing.T) {
+	tests := []struct {
+		name              string
+		rating, deviation float64
+		opponent          float64
+		opponentDeviation float64
+		score             float64
+		wantRating        float64
+		wantDeviation     float64
+	}{
+		{name: "new player wins an even game", rating: 1500, deviation: 350, opponent: 1500, opponentDeviation: 60, score: 1, wantRating: 1674.99, wantDeviation: 248.78},
+		{name: "new player loses an even game", rating: 1500, deviation: 350, opponent: 1500, opponentDeviation: 60, score: 0, wantRating: 1325.01, wantDeviation: 248.78},
+		{name: "expected win moves little", rating: 1500, deviation: 200, opponent: 1400, opponentDeviation: 30, score: 1, wantRating: 1563.43, wantDeviation: 175.22},
+		{name: "uncertain opponent counts for less", rating: 1500, deviation: 200, opponent: 1700, opponentDeviation: 300, score: 0, wantRating: 1455.96, wantDeviation: 186.76},
+		{name: "half score against an equal keeps the rating", rating: 1500, deviation: 50, opponent: 1500, opponentDeviation: 60, score: 0.5, wantRating: 1500, wantDeviation: 49.51},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rating, deviation := glickoUpdate(tt.rating, tt.deviation, tt.opponent, tt.opponentDeviation, tt.score)
+			if math.Abs(rating-tt.wantRating) > 0.01 {
+				t.Errorf("rating = %.2f, want %.2f", rating, tt.wantRating)
+			}
+			if math.Abs(deviation-tt.wantDeviation) > 0.01 {
+				t.Errorf("deviation = %.2f, want %.2f", deviation, tt.wantDeviation)
+			}
+			if deviation >= tt.deviation {
+				t.Errorf("deviation grew from %.2f to %.2f", tt.deviation, deviation)
+			}
+		})
+	}
+}
+
+func TestRateAttempt(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	settled := models.SkillRating{Rating: 1400, Deviation: 50, Attempts: models.SkillProvisionalAttempts, UpdatedAt: at}
+	provisional := models.SkillRating{Rating: 1400, Deviation: 350, Attempts: 0, UpdatedAt: at}
+
+	tests := []struct {
+		name             string
+		skill            models.SkillRating
+		unlockedTier     int
+		opponent         models.SkillRating
+		tier             int
+		score            float64
+		wantRated        bool
+		wantDelta        float64
+		wantUnlocked     bool
+		wantUnlockedTier int
+		wantRatingUp     bool
+	}{
+		{
+			name: "settled user beats an even challenge", skill: settled, unlockedTier: 3,
+			opponent: models.SkillRating{Rating: 1400, Attempts: models.ChallengeProvisionalAttempts}, tier: 3, score: 1,
+			wantRated: true, wantDelta: -8, wantUnlockedTier: 3, wantRatingUp: true,
+		},
+		{
+			name: "settled user misses an even challenge", skill: settled, unlockedTier: 3,
+			opponent: models.SkillRating{Rating: 1400, Attempts: models.ChallengeProvisionalAttempts}, tier: 3, score: 0,
+			wantRated: true, wantDelta: 8, wantUnlockedTier: 3,
+		},
+		{
+			name: "provisional challenge moves faster", skill: settled, unlockedTier: 3,
+			opponent: models.SkillRating{Rating: 1400, Attempts: 0}, tier: 3, score: 1,
+			wantRated: true, wantDelta: -16, wantUnlockedTier: 3, wantRatingUp: true,
+		},
+		{
+			name: "provisional user does not move the challenge", skill: provisional, unlockedTier: 3,
+			opponent: models.SkillRating{Rating: 1400, Attempts: models.ChallengeProvisionalAttempts}, tier: 3, score: 1,
+			wantRated: false, wantUnlockedTier: 3, wantRatingUp: true,
+		},
+		{
+			name: "right answer above the unlocked tier unlocks it", skill: settled, unlockedTier: 2,
+			opponent: models.SkillRating{Rating: 1400, Attempts: models.ChallengeProvisionalAttempts}, tier: 3, score: 1,
+			wantRated: true, wantDelta: -8, wantUnlocked: true, wantUnlockedTier: 3, wantRatingUp: true,
+		},
+		{
+			name: "partly right answer does not unlock", skill: settled, unlockedTier: 2,
+			opponent: models.SkillRating{Rating: 1400, Attempts: models.ChallengeProvisionalAttempts}, tier: 3, score: 0.9,
+			wantRated: true, wantDelta: -6.4, wantUnlockedTier: 2, wantRatingUp: true,
+		},
+		{
+			name: "rating short of the tier does not unlock", skill: settled, unlockedTier: 2,
+			opponent: models.SkillRating{Rating: 1800, Attempts: models.ChallengeProvisionalAttempts}, tier: 5, score: 1,
+			wantRated: true, wantDelta: -14.55, wantUnlockedTier: 2, wantRatingUp: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skill := &models.UserSkill{SkillRating: tt.skill, UnlockedTier: tt.unlockedTier}
+			global := tt.skill
+			opponent := tt.opponent
+
+			outcome := rateAttempt(skill, &global, &opponent, tt.tier, tt.score, at)
+
+			if outcome.challengeRated != tt.wantRated {
+				t.Errorf("challengeRated = %v, want %v", outcome.challengeRated, tt.wantRated)
+			}
+			if math.Abs(outcome.challengeDelta-tt.wantDelta) > 0.01 {
+				t.Errorf("challengeDelta = %.2f, want %.2f", outcome.challengeDelta, tt.wantDelta)
+			}
+			if outcome.unlocked != tt.wantUnlocked {
+				t.Errorf("unlocked = %v, want %v", outcome.unlocked, tt.wantUnlocked)
+			}
+			if skill.UnlockedTier != tt.wantUnlockedTier {
+				t.Errorf("UnlockedTier = %d, want %d", skill.UnlockedTier, tt.wantUnlockedTier)
+			}
+			if up := skill.Rating > tt.skill.Rating; up != tt.wantRatingUp {
+				t.Errorf("rating went from %.2f to %.2f", tt.skill.Rating, skill.Rating)
+			}
+			if skill.Attempts != tt.skill.Attempts+1 || global.Attempts != tt.skill.Attempts+1 {
+				t.Errorf("attempts = %d/%d, want %d", skill.Attempts, global.Attempts, tt.skill.Attempts+1)
+			}
+			if skill.Rating != global.Rating {
+				t.Errorf("global rating %.2f differs from category rating %.2f", global.Rating, skill.Rating)
+			}
+			if skill.Deviation < skillMinDeviation {
+				t.Errorf("deviation %.2f fell below the floor", skill.Deviation)
+			}
+			if opponent != tt.opponent {
+				t.Errorf("opponent changed to %+v", opponent)
+			}
+		})
+	}
+}
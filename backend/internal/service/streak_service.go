@@ -8,6 +8,7 @@ import (
 	"github.com/fanmania/backend/internal/domain/models"
 	"github.com/fanmania/backend/internal/repository/postgres"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 // StreakService handles user streak tracking
@@ -22,23 +23,37 @@ func NewStreakService(db *postgres.DB) *StreakService {
 	}
 }
 
-// UpdateStreak updates user's streak after completing a challenge
-func (s *StreakService) UpdateStreak(ctx context.Context, userID uuid.UUID, categoryID *uuid.UUID) error {
-	today := time.Now().Truncate(24 * time.Hour)
-	yesterday := today.AddDate(0, 0, -1)
-
-	// Start transaction
-	tx, err := s.db.Pool.Begin(ctx)
+// UpdateStreak updates user's streak after completing a challenge.
+// It returns the resulting streak and whether it changed (first activity
+// of the day extends or restarts the streak; later ones leave it as is).
+// It joins the caller's transaction if ctx carries one.
+func (s *StreakService) UpdateStreak(ctx context.Context, userID uuid.UUID, categoryID *uuid.UUID) (*models.UserStreak, bool, error) {
+	var streak *models.UserStreak
+	var updated bool
+
+	err := s.db.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		streak, updated, err = s.updateStreak(ctx, userID, categoryID)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, false, err
 	}
-	defer tx.Rollback(ctx)
+
+	return streak, updated, nil
+}
+
+// updateStreak does the work of UpdateStreak; ctx must carry a transaction
+func (s *StreakService) updateStreak(ctx context.Context, userID uuid.UUID, categoryID *uuid.UUID) (*models.UserStreak, bool, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	conn := s.db.Conn(ctx)
 
 	// Get current streak
 	query := `
 		SELECT id, current_streak, longest_streak, last_activity_date
 		FROM user_streaks
-		WHERE user_id = $1 AND (category_id = $2 OR ($2 IS NULL AND category_id IS NULL))
+		WHERE user_id = $1 AND (category_id = $2 OR ($2::uuid IS NULL AND category_id IS NULL))
 		FOR UPDATE
 	`
 
@@ -46,21 +61,44 @@ func (s *StreakService) UpdateStreak(ctx context.Context, userID uuid.UUID, cate
 	var currentStreak, longestStreak int
 	var lastActivity *time.Time
 
-	err = tx.QueryRow(ctx, query, userID, categoryID).Scan(
+	err := conn.QueryRow(ctx, query, userID, categoryID).Scan(
 		&streakID, &currentStreak, &longestStreak, &lastActivity,
 	)
 
-	if err != nil {
+	if err == pgx.ErrNoRows {
 		// No streak exists, create one
 		insertQuery := `
 			INSERT INTO user_streaks (user_id, category_id, current_streak, longest_streak, last_activity_date)
 			VALUES ($1, $2, 1, 1, $3)
+			RETURNING id, updated_at
 		`
-		_, err = tx.Exec(ctx, insertQuery, userID, categoryID, today)
+		streak := &models.UserStreak{
+			UserID:           userID,
+			CategoryID:       categoryID,
+			CurrentStreak:    1,
+			LongestStreak:    1,
+			LastActivityDate: &today,
+		}
+		err = conn.QueryRow(ctx, insertQuery, userID, categoryID, today).Scan(&streak.ID, &streak.UpdatedAt)
 		if err != nil {
-			return fmt.Errorf("failed to create streak: %w", err)
+			return nil, false, fmt.Errorf("failed to create streak: %w", err)
 		}
-		return tx.Commit(ctx)
+		if err := s.syncCategoryRanking(ctx, streak); err != nil {
+			return nil, false, err
+		}
+		return streak, true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get streak: %w", err)
+	}
+
+	streak := &models.UserStreak{
+		ID:               streakID,
+		UserID:           userID,
+		CategoryID:       categoryID,
+		CurrentStreak:    currentStreak,
+		LongestStreak:    longestStreak,
+		LastActivityDate: lastActivity,
 	}
 
 	// Determine new streak value
@@ -70,10 +108,10 @@ func (s *StreakService) UpdateStreak(ctx context.Context, userID uuid.UUID, cate
 		newStreak = 1
 	} else {
 		lastActivityDate := lastActivity.Truncate(24 * time.Hour)
-		
+
 		if lastActivityDate.Equal(today) {
 			// Already completed today, no change
-			return tx.Commit(ctx)
+			return streak, false, nil
 		} else if lastActivityDate.Equal(yesterday) {
 			// Consecutive day, increment streak
 			newStreak = currentStreak + 1
@@ -97,14 +135,42 @@ func (s *StreakService) UpdateStreak(ctx context.Context, userID uuid.UUID, cate
 		    last_activity_date = $3,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = $4
+		RETURNING updated_at
 	`
 
-	_, err = tx.Exec(ctx, updateQuery, newStreak, newLongestStreak, today, streakID)
+	err = conn.QueryRow(ctx, updateQuery, newStreak, newLongestStreak, today, streakID).Scan(&streak.UpdatedAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to update streak: %w", err)
+	}
+
+	streak.CurrentStreak = newStreak
+	streak.LongestStreak = newLongestStreak
+	streak.LastActivityDate = &today
+
+	if err := s.syncCategoryRanking(ctx, streak); err != nil {
+		return nil, false, err
+	}
+
+	return streak, true, nil
+}
+
+// syncCategoryRanking mirrors a category streak onto category_rankings so
+// both tables report the same numbers
+func (s *StreakService) syncCategoryRanking(ctx context.Context, streak *models.UserStreak) error {
+	if streak.CategoryID == nil {
+		return nil
+	}
+
+	_, err := s.db.Conn(ctx).Exec(ctx, `
+		UPDATE category_rankings
+		SET streak_days = $1, longest_streak = $2
+		WHERE user_id = $3 AND category_id = $4
+	`, streak.CurrentStreak, streak.LongestStreak, streak.UserID, *streak.CategoryID)
 	if err != nil {
-		return fmt.Errorf("failed to update streak: %w", err)
+		return fmt.Errorf("failed to sync category streak: %w", err)
 	}
 
-	return tx.Commit(ctx)
+	return nil
 }
 
 // GetUserStreak gets user's current streak
@@ -112,11 +178,11 @@ func (s *StreakService) GetUserStreak(ctx context.Context, userID uuid.UUID, cat
 	query := `
 		SELECT id, user_id, category_id, current_streak, longest_streak, last_activity_date, updated_at
 		FROM user_streaks
-		WHERE user_id = $1 AND (category_id = $2 OR ($2 IS NULL AND category_id IS NULL))
+		WHERE user_id = $1 AND (category_id = $2 OR ($2::uuid IS NULL AND category_id IS NULL))
 	`
 
 	var streak models.UserStreak
-	err := s.db.Pool.QueryRow(ctx, query, userID, categoryID).Scan(
+	err := s.db.Conn(ctx).QueryRow(ctx, query, userID, categoryID).Scan(
 		&streak.ID,
 		&streak.UserID,
 		&streak.CategoryID,
@@ -161,7 +227,7 @@ func (s *StreakService) GetAllUserStreaks(ctx context.Context, userID uuid.UUID)
 		ORDER BY current_streak DESC
 	`
 
-	rows, err := s.db.Pool.Query(ctx, query, userID)
+	rows, err := s.db.Conn(ctx).Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query streaks: %w", err)
 	}
@@ -17,9 +17,20 @@ type TokenGenerator struct {
 
 // Claims represents JWT claims
 type Claims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	Username string    `json:"username"`
-	TokenType string   `json:"token_type"` // "access" or "refresh"
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	TokenType string    `json:"token_type"` // "access" or "refresh"
+	Role      string    `json:"role,omitempty"`
+	SessionID uuid.UUID `json:"sid"` // refresh token family; the token ID is carried in jti
+	jwt.RegisteredClaims
+}
+
+// AttemptClaims identifies a server-side challenge session
+type AttemptClaims struct {
+	SessionID   uuid.UUID `json:"session_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	ChallengeID uuid.UUID `json:"challenge_id"`
+	TokenType   string    `json:"token_type"` // "attempt"
 	jwt.RegisteredClaims
 }
 
@@ -32,13 +43,16 @@ func NewTokenGenerator(secret string, accessExpiry, refreshExpiry time.Duration)
 	}
 }
 
-// GenerateAccessToken generates an access token
-func (tg *TokenGenerator) GenerateAccessToken(userID uuid.UUID, username string) (string, error) {
+// GenerateAccessToken generates an access token for a login session
+func (tg *TokenGenerator) GenerateAccessToken(userID uuid.UUID, username, role string, sessionID uuid.UUID) (string, error) {
 	claims := Claims{
 		UserID:    userID,
 		Username:  username,
 		TokenType: "access",
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tg.accessTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -49,14 +63,17 @@ func (tg *TokenGenerator) GenerateAccessToken(userID uuid.UUID, username string)
 	return token.SignedString(tg.secret)
 }
 
-// GenerateRefreshToken generates a refresh token
-func (tg *TokenGenerator) GenerateRefreshToken(userID uuid.UUID, username string) (string, error) {
+// GenerateRefreshToken generates a refresh token. tokenID becomes the jti and
+// must match a stored refresh token; sessionID is the rotation family.
+func (tg *TokenGenerator) GenerateRefreshToken(userID uuid.UUID, username string, tokenID, sessionID uuid.UUID, expiresAt time.Time) (string, error) {
 	claims := Claims{
 		UserID:    userID,
 		Username:  username,
 		TokenType: "refresh",
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tg.refreshTokenExpiry)),
+			ID:        tokenID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
@@ -66,6 +83,51 @@ func (tg *TokenGenerator) GenerateRefreshToken(userID uuid.UUID, username string
 	return token.SignedString(tg.secret)
 }
 
+// GenerateAttemptToken signs a challenge session so submissions can be tied
+// back to the moment the challenge was served. The token is refused after
+// expiresAt.
+func (tg *TokenGenerator) GenerateAttemptToken(sessionID, userID, challengeID uuid.UUID, expiresAt time.Time) (string, error) {
+	claims := AttemptClaims{
+		SessionID:   sessionID,
+		UserID:      userID,
+		ChallengeID: challengeID,
+		TokenType:   "attempt",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(tg.secret)
+}
+
+// ValidateAttemptToken validates a challenge session token
+func (tg *TokenGenerator) ValidateAttemptToken(tokenString string) (*AttemptClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &AttemptClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return tg.secret, nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*AttemptClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if claims.TokenType != "attempt" {
+		return nil, fmt.Errorf("not an attempt token")
+	}
+
+	return claims, nil
+}
+
 // ValidateToken validates a token and returns its claims
 func (tg *TokenGenerator) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -0,0 +1,140 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateAttemptToken(t *testing.T) {
+	tg := NewTokenGenerator("test-secret", 15*time.Minute, 24*time.Hour)
+	other := NewTokenGenerator("other-secret", 15*time.Minute, 24*time.Hour)
+	sessionID, userID, challengeID := uuid.New(), uuid.New(), uuid.New()
+
+	sign := func(t *testing.T, tg *TokenGenerator, expiresAt time.Time) string {
+		t.Helper()
+		token, err := tg.GenerateAttemptToken(sessionID, userID, challengeID, expiresAt)
+		if err != nil {
+			t.Fatalf("GenerateAttemptToken: %v", err)
+		}
+		return token
+	}
+	access, err := tg.GenerateAccessToken(userID, "fan", "user", sessionID)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: sign(t, tg, time.Now().Add(time.Minute))},
+		{name: "expired", token: sign(t, tg, time.Now().Add(-time.Minute)), wantErr: true},
+		{name: "signed with another secret", token: sign(t, other, time.Now().Add(time.Minute)), wantErr: true},
+		{name: "access token", token: access, wantErr: true},
+		{name: "garbage", token: "not.a.token", wantErr: true},
+		{name: "empty", token: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tg.ValidateAttemptToken(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ValidateAttemptToken succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateAttemptToken: %v", err)
+			}
+			if claims.SessionID != sessionID || claims.UserID != userID || claims.ChallengeID != challengeID {
+				t.Errorf("claims = %+v, want session %s user %s challenge %s", claims, sessionID, userID, challengeID)
+			}
+		})
+	}
+}
+
+func TestValidateRefreshToken(t *testing.T) {
+	tg := NewTokenGenerator("test-secret", 15*time.Minute, 24*time.Hour)
+	other := NewTokenGenerator("other-secret", 15*time.Minute, 24*time.Hour)
+	userID, tokenID, sessionID := uuid.New(), uuid.New(), uuid.New()
+
+	sign := func(t *testing.T, tg *TokenGenerator, expiresAt time.Time) string {
+		t.Helper()
+		token, err := tg.GenerateRefreshToken(userID, "fan", tokenID, sessionID, expiresAt)
+		if err != nil {
+			t.Fatalf("GenerateRefreshToken: %v", err)
+		}
+		return token
+	}
+	access, err := tg.GenerateAccessToken(userID, "fan", "user", sessionID)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	attempt, err := tg.GenerateAttemptToken(uuid.New(), userID, uuid.New(), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GenerateAttemptToken: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: sign(t, tg, time.Now().Add(time.Hour))},
+		{name: "expired", token: sign(t, tg, time.Now().Add(-time.Minute)), wantErr: true},
+		{name: "signed with another secret", token: sign(t, other, time.Now().Add(time.Hour)), wantErr: true},
+		{name: "access token", token: access, wantErr: true},
+		{name: "attempt token", token: attempt, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tg.ValidateRefreshToken(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ValidateRefreshToken succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateRefreshToken: %v", err)
+			}
+			if claims.UserID != userID || claims.ID != tokenID.String() || claims.SessionID != sessionID {
+				t.Errorf("claims = %+v, want user %s token %s session %s", claims, userID, tokenID, sessionID)
+			}
+		})
+	}
+}
+
+func TestAccessTokenKeepsItsSession(t *testing.T) {
+	tg := NewTokenGenerator("test-secret", 15*time.Minute, 24*time.Hour)
+	userID, sessionID := uuid.New(), uuid.New()
+
+	first, err := tg.GenerateAccessToken(userID, "fan", "admin", sessionID)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	second, err := tg.GenerateAccessToken(userID, "fan", "admin", sessionID)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	a, err := tg.ValidateAccessToken(first)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	b, err := tg.ValidateAccessToken(second)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	if a.SessionID != sessionID || b.SessionID != sessionID || a.Role != "admin" {
+		t.Errorf("claims = %+v, want session %s and role admin", a, sessionID)
+	}
+	if a.ID == b.ID {
+		t.Errorf("access tokens share jti %s", a.ID)
+	}
+}
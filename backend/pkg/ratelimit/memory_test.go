@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreRefill(t *testing.T) {
+	limit := Limit{Burst: 5, Period: 10 * time.Second} // a token every 2s
+
+	tests := []struct {
+		name          string
+		taken         int           // tokens taken before idling
+		idle          time.Duration // time passed before the checked request
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration // approximate, when denied
+	}{
+		{name: "fresh bucket is full", wantAllowed: true, wantRemaining: 4},
+		{name: "partly used", taken: 2, wantAllowed: true, wantRemaining: 2},
+		{name: "empty bucket denies", taken: 5, wantAllowed: false, wantRemaining: 0, wantRetry: 2 * time.Second},
+		{name: "half a token is not enough", taken: 5, idle: time.Second, wantAllowed: false, wantRemaining: 0, wantRetry: time.Second},
+		{name: "one token refilled", taken: 5, idle: 2 * time.Second, wantAllowed: true, wantRemaining: 0},
+		{name: "refill accumulates", taken: 5, idle: 6 * time.Second, wantAllowed: true, wantRemaining: 2},
+		{name: "refill stops at the burst", taken: 5, idle: time.Minute, wantAllowed: true, wantRemaining: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewMemoryStore()
+
+			for i := 0; i < tt.taken; i++ {
+				if res, err := store.Allow(ctx, "key", limit); err != nil || !res.Allowed {
+					t.Fatalf("take %d: allowed=%v err=%v", i+1, res.Allowed, err)
+				}
+			}
+			if b, ok := store.buckets["key"]; ok {
+				b.updated = b.updated.Add(-tt.idle)
+			}
+
+			res, err := store.Allow(ctx, "key", limit)
+			if err != nil {
+				t.Fatalf("Allow: %v", err)
+			}
+			if res.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v", res.Allowed, tt.wantAllowed)
+			}
+			if res.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", res.Remaining, tt.wantRemaining)
+			}
+			if res.Limit != limit.Burst {
+				t.Errorf("Limit = %d, want %d", res.Limit, limit.Burst)
+			}
+			if diff := res.RetryAfter - tt.wantRetry; diff < -50*time.Millisecond || diff > 50*time.Millisecond {
+				t.Errorf("RetryAfter = %v, want about %v", res.RetryAfter, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreKeysAreSeparate(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	limit := Limit{Burst: 1, Period: time.Minute}
+
+	if res, _ := store.Allow(ctx, "a", limit); !res.Allowed {
+		t.Fatal("first request for a denied")
+	}
+	if res, _ := store.Allow(ctx, "a", limit); res.Allowed {
+		t.Fatal("second request for a allowed")
+	}
+	if res, _ := store.Allow(ctx, "b", limit); !res.Allowed {
+		t.Fatal("first request for b denied")
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	limit := Limit{Burst: 10, Period: 10 * time.Second} // a token a second
+
+	tests := []struct {
+		name    string
+		tokens  float64
+		allowed bool
+		want    Result
+	}{
+		{name: "full", tokens: 10, allowed: true, want: Result{Allowed: true, Limit: 10, Remaining: 10}},
+		{name: "part used", tokens: 7.5, allowed: true, want: Result{Allowed: true, Limit: 10, Remaining: 7, ResetAfter: 2500 * time.Millisecond}},
+		{name: "empty", tokens: 0, allowed: true, want: Result{Allowed: true, Limit: 10, Remaining: 0, ResetAfter: 10 * time.Second}},
+		{
+			name: "denied", tokens: 0.25, allowed: false,
+			want: Result{Allowed: false, Limit: 10, Remaining: 0, ResetAfter: 9750 * time.Millisecond, RetryAfter: 750 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newResult(limit, tt.tokens, tt.allowed); got != tt.want {
+				t.Errorf("newResult = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
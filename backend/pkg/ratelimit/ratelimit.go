@@ -0,0 +1,60 @@
+// Package ratelimit implements token-bucket rate limiting over pluggable stores.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit allows bursts of up to Burst requests, refilling Burst tokens every Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// ratePerSecond is how many tokens are added back each second
+func (l Limit) ratePerSecond() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Result describes the outcome of taking a token
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+	// RetryAfter is how long until the next request would be allowed (zero when allowed)
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets keyed by caller
+type Store interface {
+	// Allow takes one token from the bucket for key
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// newResult builds a Result from the tokens left in a bucket after a take
+func newResult(limit Limit, tokens float64, allowed bool) Result {
+	rate := limit.ratePerSecond()
+
+	result := Result{
+		Allowed:    allowed,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsToDuration((float64(limit.Burst) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
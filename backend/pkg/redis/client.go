@@ -0,0 +1,302 @@
+// Package redis is a small RESP client covering the commands the API needs.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Nil is returned when Redis replies with a null value
+var Nil = errors.New("redis: nil")
+
+// Error is an error reply sent by the Redis server
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	defaultPoolSize    = 10
+	defaultMaxConns    = 50
+	defaultDialTimeout = 5 * time.Second
+	defaultIOTimeout   = 3 * time.Second
+)
+
+// Client is a pooled Redis connection. At most defaultMaxConns connections
+// are open at once; callers past that wait for one to free up.
+type Client struct {
+	addr     string
+	username string
+	password string
+	db       int
+	useTLS   bool
+	pool     chan *conn
+	slots    chan struct{} // one entry per open connection
+}
+
+type conn struct {
+	net.Conn
+	rd *bufio.Reader
+}
+
+// NewClient connects to the Redis server at a redis:// or rediss:// URL
+func NewClient(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redis URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("unsupported redis URL scheme %q", u.Scheme)
+	}
+
+	client := &Client{
+		addr:   u.Host,
+		useTLS: u.Scheme == "rediss",
+		pool:   make(chan *conn, defaultPoolSize),
+		slots:  make(chan struct{}, defaultMaxConns),
+	}
+	if u.Port() == "" {
+		client.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		client.username = u.User.Username()
+		client.password, _ = u.User.Password()
+	}
+	if dbPart := strings.TrimPrefix(u.Path, "/"); dbPart != "" {
+		client.db, err = strconv.Atoi(dbPart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis database %q", dbPart)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultDialTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return client, nil
+}
+
+// Ping checks that the server is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Close closes all pooled connections
+func (c *Client) Close() {
+	for {
+		select {
+		case cn := <-c.pool:
+			c.discard(cn)
+		default:
+			return
+		}
+	}
+}
+
+// Do sends a command and returns its reply. Replies are decoded as
+// string, int64, []interface{} or nil; error replies are returned as Error.
+func (c *Client) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, args...)
+	if err != nil {
+		var redisErr Error
+		if errors.As(err, &redisErr) || errors.Is(err, Nil) {
+			c.put(cn)
+		} else {
+			// The connection state is unknown after an I/O error
+			c.discard(cn)
+		}
+		return nil, err
+	}
+
+	c.put(cn)
+	return reply, nil
+}
+
+// get takes an idle connection, or opens one if fewer than defaultMaxConns
+// are open, waiting for either until ctx is done
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.pool:
+		return cn, nil
+	default:
+	}
+
+	select {
+	case cn := <-c.pool:
+		return cn, nil
+	case c.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to get redis connection: %w", ctx.Err())
+	}
+
+	cn, err := c.dial(ctx)
+	if err != nil {
+		<-c.slots
+		return nil, err
+	}
+
+	return cn, nil
+}
+
+// dial opens and sets up a new connection
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := &net.Dialer{Timeout: defaultDialTimeout}
+	var netConn net.Conn
+	var err error
+	if c.useTLS {
+		host, _, _ := net.SplitHostPort(c.addr)
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", c.addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	cn := &conn{Conn: netConn, rd: bufio.NewReader(netConn)}
+
+	if c.password != "" {
+		args := []interface{}{"AUTH", c.password}
+		if c.username != "" {
+			args = []interface{}{"AUTH", c.username, c.password}
+		}
+		if _, err := cn.do(ctx, args...); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("redis auth failed: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.do(ctx, "SELECT", c.db); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("redis select failed: %w", err)
+		}
+	}
+
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.pool <- cn:
+	default:
+		c.discard(cn)
+	}
+}
+
+// discard closes cn and frees its slot
+func (c *Client) discard(cn *conn) {
+	cn.Close()
+	<-c.slots
+}
+
+func (cn *conn) do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultIOTimeout)
+	}
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if _, err := cn.Write(encodeCommand(args)); err != nil {
+		return nil, err
+	}
+
+	return readReply(cn.rd)
+}
+
+// encodeCommand writes args as a RESP array of bulk strings
+func encodeCommand(args []interface{}) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var s string
+		switch v := arg.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		case int:
+			s = strconv.Itoa(v)
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		case float64:
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			s = fmt.Sprint(v)
+		}
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(s), s)
+	}
+	return []byte(b.String())
+}
+
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, Nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, Nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			item, err := readReply(rd)
+			if err != nil && !errors.Is(err, Nil) {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
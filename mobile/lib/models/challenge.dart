@@ -86,15 +86,40 @@ class Challenge {
   }
 }
 
+/// Attempt session returned when a challenge is started; the token is sent
+/// back with the answer
+class ChallengeStart {
+  final String sessionToken;
+  final DateTime? expiresAt;
+
+  ChallengeStart({
+    required this.sessionToken,
+    this.expiresAt,
+  });
+
+  factory ChallengeStart.fromJson(Map<String, dynamic> json) {
+    return ChallengeStart(
+      sessionToken: json['session_token'],
+      expiresAt: json['expires_at'] != null
+          ? DateTime.parse(json['expires_at'])
+          : null,
+    );
+  }
+}
+
 class QuestionData {
   final String type;
   final String question;
   final List<QuestionOption> options;
+  final List<QuestionOption> events;
+  final PatternData? pattern;
 
   QuestionData({
     required this.type,
     required this.question,
     required this.options,
+    this.events = const [],
+    this.pattern,
   });
 
   factory QuestionData.fromJson(Map<String, dynamic> json) {
@@ -105,6 +130,13 @@ class QuestionData {
               ?.map((o) => QuestionOption.fromJson(o))
               .toList() ??
           [],
+      events: (json['events'] as List<dynamic>?)
+              ?.map((e) => QuestionOption.fromJson(e))
+              .toList() ??
+          [],
+      pattern: json['pattern'] != null
+          ? PatternData.fromJson(json['pattern'])
+          : null,
     );
   }
 
@@ -113,6 +145,35 @@ class QuestionData {
       'type': type,
       'question': question,
       'options': options.map((o) => o.toJson()).toList(),
+      'events': events.map((e) => e.toJson()).toList(),
+      if (pattern != null) 'pattern': pattern!.toJson(),
+    };
+  }
+}
+
+class PatternData {
+  final String kind;
+  final List<String> sequence;
+
+  PatternData({
+    required this.kind,
+    this.sequence = const [],
+  });
+
+  factory PatternData.fromJson(Map<String, dynamic> json) {
+    return PatternData(
+      kind: json['kind'] ?? 'missing_element',
+      sequence: (json['sequence'] as List<dynamic>?)
+              ?.map((s) => s.toString())
+              .toList() ??
+          [],
+    );
+  }
+
+  Map<String, dynamic> toJson() {
+    return {
+      'kind': kind,
+      'sequence': sequence,
     };
   }
 }
@@ -143,6 +204,8 @@ class QuestionOption {
 
 class ChallengeResult {
   final bool isCorrect;
+  final double score;
+  final bool pending;
   final int pointsEarned;
   final String? explanation;
   final int newTotalPoints;
@@ -152,6 +215,8 @@ class ChallengeResult {
 
   ChallengeResult({
     required this.isCorrect,
+    required this.score,
+    this.pending = false,
     required this.pointsEarned,
     this.explanation,
     required this.newTotalPoints,
@@ -163,6 +228,8 @@ class ChallengeResult {
   factory ChallengeResult.fromJson(Map<String, dynamic> json) {
     return ChallengeResult(
       isCorrect: json['is_correct'] ?? false,
+      score: (json['score'] ?? 0).toDouble(),
+      pending: json['pending'] ?? false,
       pointsEarned: json['points_earned'] ?? 0,
       explanation: json['explanation'],
       newTotalPoints: json['new_total_points'] ?? 0,
@@ -175,6 +242,8 @@ class ChallengeResult {
   Map<String, dynamic> toJson() {
     return {
       'is_correct': isCorrect,
+      'score': score,
+      'pending': pending,
       'points_earned': pointsEarned,
       'explanation': explanation,
       'new_total_points': newTotalPoints,
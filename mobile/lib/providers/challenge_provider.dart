@@ -13,6 +13,7 @@ class ChallengeProvider extends ChangeNotifier {
   List<Challenge> _challenges = [];
   int _currentChallengeIndex = 0;
   ChallengeResult? _lastResult;
+  Future<ChallengeStart?>? _currentStart; // clock for the current challenge
   int _sessionTargetQuestions = 10; // Fixed session length
 
   // Session statistics
@@ -183,14 +184,11 @@ class ChallengeProvider extends ChangeNotifier {
     notifyListeners();
 
     try {
-      final timeTaken = challenge.timeLimitSeconds != null
-          ? challenge.timeLimitSeconds! - _remainingSeconds
-          : null;
-
+      final start = await _currentStart;
       _lastResult = await _apiService.submitChallenge(
         challengeId: challenge.id,
         selectedAnswer: selectedAnswer,
-        timeTakenSeconds: timeTaken,
+        sessionToken: start?.sessionToken ?? '',
       );
 
       // Update session stats
@@ -282,6 +280,20 @@ class ChallengeProvider extends ChangeNotifier {
       _timerActive = true;
     }
     _isSubmitting = false;
+    _currentStart = _startCurrentChallenge();
+  }
+
+  /// Start the server-side clock for the current challenge, now that the
+  /// player can see it
+  Future<ChallengeStart?> _startCurrentChallenge() async {
+    final challenge = currentChallenge;
+    if (challenge == null) return null;
+    try {
+      return await _apiService.startChallenge(challenge.id);
+    } catch (e) {
+      debugPrint('Failed to start challenge: $e');
+      return null;
+    }
   }
 
   /// Pause timer
@@ -299,6 +311,7 @@ class ChallengeProvider extends ChangeNotifier {
   /// End the current session
   void endSession() {
     _currentCategory = null;
+    _currentStart = null;
     _challenges = [];
     _currentChallengeIndex = 0;
     _lastResult = null;
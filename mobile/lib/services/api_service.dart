@@ -126,6 +126,11 @@ class ApiService {
   }
 
   Future<void> logout() async {
+    // Revoke the session server-side; clear local tokens even if that fails
+    try {
+      final url = Uri.parse('${ApiConfig.apiUrl}${ApiConfig.auth}/logout');
+      await _client.post(url, headers: _getHeaders(includeAuth: true));
+    } catch (_) {}
     await clearTokens();
   }
 
@@ -272,10 +277,28 @@ class ApiService {
     }
   }
 
+  Future<ChallengeStart> startChallenge(String challengeId) async {
+    final url = Uri.parse(
+        '${ApiConfig.apiUrl}${ApiConfig.challenges}/$challengeId/start');
+
+    final response = await _client.post(
+      url,
+      headers: _getHeaders(includeAuth: true),
+    );
+
+    if (response.statusCode == 200) {
+      return ChallengeStart.fromJson(json.decode(response.body));
+    } else {
+      _handleError(response);
+      throw Exception('Failed to start challenge');
+    }
+  }
+
   Future<ChallengeResult> submitChallenge({
     required String challengeId,
     required String selectedAnswer,
-    int? timeTakenSeconds,
+    required String sessionToken,
+    List<String>? selectedOrder,
   }) async {
     final url = Uri.parse(
         '${ApiConfig.apiUrl}${ApiConfig.challenges}/$challengeId/attempt');
@@ -285,7 +308,8 @@ class ApiService {
       headers: _getHeaders(includeAuth: true),
       body: json.encode({
         'selected_answer': selectedAnswer,
-        if (timeTakenSeconds != null) 'time_taken_seconds': timeTakenSeconds,
+        if (selectedOrder != null) 'selected_order': selectedOrder,
+        'session_token': sessionToken,
       }),
     );
 